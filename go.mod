@@ -4,5 +4,9 @@ go 1.13
 
 require (
 	github.com/google/go-querystring v1.0.0
-	github.com/stretchr/testify v1.6.1
+	github.com/prometheus/client_golang v1.11.1
+	github.com/stretchr/testify v1.7.0
+	go.opentelemetry.io/otel v1.0.0
+	go.opentelemetry.io/otel/trace v1.0.0
+	golang.org/x/sync v0.1.0
 )
@@ -10,13 +10,21 @@
 package base
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 const (
@@ -28,12 +36,48 @@ const (
 
 // Base Mapbox API base
 type Base struct {
-	token string
-	debug bool
+	token   string
+	debug   bool
+	baseURL string
+
+	requestLogger  RequestLogger
+	responseLogger ResponseLogger
+	logger         structuredLogger
+	debugHook      DebugHook
+	metrics        MetricsRecorder
+	transport      http.RoundTripper
+	wrapTransport  func(http.RoundTripper) http.RoundTripper
+	cache          *responseCache
+	breaker        *circuitBreaker
+	semaphore      chan struct{}
+
+	last *lastResponse
+}
+
+// RateLimitInfo is the rate limit state reported by the API
+type RateLimitInfo struct {
+	// Interval is the period over which the limit applies
+	Interval time.Duration
+	// Limit is the maximum number of requests per interval
+	Limit int
+	// Reset is the time at which the current interval ends
+	Reset time.Time
+}
+
+// IsZero checks whether no rate limit information has been received
+func (r RateLimitInfo) IsZero() bool {
+	return r.Interval == 0 && r.Limit == 0 && r.Reset.IsZero()
+}
+
+// lastResponse holds the details of the most recent response, shared across goroutines
+type lastResponse struct {
+	mu        sync.Mutex
+	rateLimit RateLimitInfo
+	requestID string
 }
 
 // NewBase Create a new API base instance
-func NewBase(token string) (*Base, error) {
+func NewBase(token string, opts ...Option) (*Base, error) {
 	if token == "" {
 		return nil, errors.New("Mapbox API token not found")
 	}
@@ -41,45 +85,283 @@ func NewBase(token string) (*Base, error) {
 	b := &Base{}
 
 	b.token = token
+	b.baseURL = BaseURL
+	b.last = &lastResponse{}
+	b.metrics = NoopMetricsRecorder{}
+
+	for _, o := range opts {
+		if err := o(b); err != nil {
+			return nil, err
+		}
+	}
+
+	// Transport wrappers apply to the configured transport regardless of option order
+	if b.wrapTransport != nil {
+		b.transport = b.wrapTransport(b.transport)
+	}
 
 	return b, nil
 }
 
+// CloneWithToken creates a copy of the API base that authenticates with the provided token
+func (b *Base) CloneWithToken(token string) (*Base, error) {
+	if token == "" {
+		return nil, errors.New("Mapbox API token not found")
+	}
+
+	c := *b
+	c.token = token
+	c.last = &lastResponse{}
+	if b.cache != nil {
+		// Cache keys exclude the token, so clones must not share cached responses
+		c.cache = newResponseCache(b.cache.ttl, b.cache.maxEntries)
+	}
+
+	return &c, nil
+}
+
 // SetDebug enables debug output for API calls
 func (b *Base) SetDebug(debug bool) {
 	b.debug = true
 }
 
+// MaxConcurrency returns the request concurrency limit set with WithMaxConcurrency, or 0 if unlimited
+func (b *Base) MaxConcurrency() int {
+	return cap(b.semaphore)
+}
+
+// LastRateLimit returns the rate limit information from the most recent successful or rate limited response
+func (b *Base) LastRateLimit() RateLimitInfo {
+	b.last.mu.Lock()
+	defer b.last.mu.Unlock()
+
+	return b.last.rateLimit
+}
+
+// LastRequestID returns the request ID of the most recent successful or rate limited response, for debugging
+func (b *Base) LastRequestID() string {
+	b.last.mu.Lock()
+	defer b.last.mu.Unlock()
+
+	return b.last.requestID
+}
+
+// recordResponse stores the rate limit and request ID headers of a response
+func (b *Base) recordResponse(resp *http.Response) {
+	if resp.StatusCode >= http.StatusBadRequest && resp.StatusCode != statusRateLimitExceeded {
+		return
+	}
+
+	info := RateLimitInfo{}
+	if interval, err := strconv.Atoi(resp.Header.Get("X-Rate-Limit-Interval")); err == nil {
+		info.Interval = time.Duration(interval) * time.Second
+	}
+	if limit, err := strconv.Atoi(resp.Header.Get("X-Rate-Limit-Limit")); err == nil {
+		info.Limit = limit
+	}
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-Rate-Limit-Reset"), 10, 64); err == nil {
+		info.Reset = time.Unix(reset, 0)
+	}
+
+	b.last.mu.Lock()
+	defer b.last.mu.Unlock()
+
+	b.last.rateLimit = info
+	b.last.requestID = resp.Header.Get("X-Request-Id")
+}
+
+// Username returns the username of the account that owns the API token
+// Mapbox tokens are of the form prefix.payload.signature where the payload is base64 encoded JSON
+func (b *Base) Username() (string, error) {
+	parts := strings.Split(b.token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("Mapbox API token is malformed")
+	}
+
+	payload := strings.NewReplacer("-", "+", "_", "/", "=", "").Replace(parts[1])
+	data, err := base64.RawStdEncoding.DecodeString(payload)
+	if err != nil {
+		return "", fmt.Errorf("Mapbox API token is malformed (%s)", err)
+	}
+
+	claims := struct {
+		Username string `json:"u"`
+	}{}
+	err = json.Unmarshal(data, &claims)
+	if err != nil {
+		return "", fmt.Errorf("Mapbox API token is malformed (%s)", err)
+	}
+	if claims.Username == "" {
+		return "", errors.New("Mapbox API token does not contain a username")
+	}
+
+	return claims.Username, nil
+}
+
+// MapboxApiMessage is the body of an API error response
 type MapboxApiMessage struct {
 	Message string
 }
 
+// queryURL generates the URL for the provided query path
+func (b *Base) queryURL(query string) string {
+	// Paths are resolved relative to the base URL so any path prefix is preserved
+	base, err := url.Parse(b.baseURL + "/")
+	if err != nil {
+		return fmt.Sprintf("%s/%s", b.baseURL, query)
+	}
+	ref, err := url.Parse("./" + strings.TrimPrefix(query, "/"))
+	if err != nil {
+		return fmt.Sprintf("%s/%s", b.baseURL, query)
+	}
+
+	return base.ResolveReference(ref).String()
+}
+
+// RequestURL generates the full URL (including access token) for the provided query path and arguments
+// This is useful where the URL is used directly, for example as an image source
+func (b *Base) RequestURL(query string, v *url.Values) string {
+	v.Set("access_token", b.token)
+
+	return fmt.Sprintf("%s?%s", b.queryURL(query), v.Encode())
+}
+
 // QueryRequest make a get with the provided query string and return the response if successful
 func (b *Base) QueryRequest(query string, v *url.Values) (*http.Response, error) {
+	return b.QueryRequestContext(context.Background(), query, v)
+}
+
+// QueryRequestContext make a get bound to the provided context and return the response if successful
+func (b *Base) QueryRequestContext(ctx context.Context, query string, v *url.Values) (*http.Response, error) {
+	return b.request(ctx, http.MethodGet, query, v, nil)
+}
+
+// request issues a request with an optional JSON body and returns the response if successful
+func (b *Base) request(ctx context.Context, method, query string, v *url.Values, body []byte) (*http.Response, error) {
 	// Add token to args
 	v.Set("access_token", b.token)
 
 	// Generate URL
-	url := fmt.Sprintf("%s/%s", BaseURL, query)
+	url := b.queryURL(query)
 
 	if b.debug {
 		fmt.Printf("URL: %s\n", url)
 	}
 
 	// Create request object
-	request, err := http.NewRequest(http.MethodGet, url, nil)
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
 		return nil, err
 	}
 	request.URL.RawQuery = v.Encode()
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	// Serve cached responses without contacting the API
+	key, cacheable := cacheKey(method, url, *v)
+	cacheable = cacheable && b.cache != nil && body == nil
+	if cacheable {
+		if resp, ok := b.cache.get(key, request); ok {
+			return resp, nil
+		}
+	}
+
+	// Wait for a request slot where concurrency is limited
+	if b.semaphore != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		select {
+		case b.semaphore <- struct{}{}:
+			defer func() { <-b.semaphore }()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	var generation uint64
+	if b.breaker != nil {
+		if generation, err = b.breaker.allow(); err != nil {
+			return nil, err
+		}
+	}
+
+	if b.requestLogger != nil {
+		b.requestLogger(request, body)
+	}
+	if b.logger != nil {
+		b.logger.logRequest(request, body)
+	}
 
 	// Create client instance
-	client := &http.Client{}
+	client := &http.Client{Transport: b.transport}
 
+	start := time.Now()
 	resp, err := client.Do(request)
+	if b.breaker != nil {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		b.breaker.record(generation, statusCode, err)
+	}
 	if err != nil {
+		b.metrics.RecordRequest(method, request.URL.Path, 0, time.Since(start), int64(len(body)), 0)
+		if b.logger != nil {
+			b.logger.logError(request, err)
+		}
+		if b.debugHook != nil {
+			b.debugHook(redactRequest(request), nil, nil)
+		}
 		return nil, err
 	}
+	duration := time.Since(start)
+
+	if b.responseLogger != nil || b.logger != nil || b.debugHook != nil {
+		// Read the body for the hooks, restoring it for subsequent decoding
+		data, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+		if b.responseLogger != nil {
+			b.responseLogger(resp, data, duration)
+		}
+		if b.logger != nil {
+			b.logger.logResponse(resp, data, duration)
+		}
+		if b.debugHook != nil {
+			redacted := redactRequest(request)
+			r := *resp
+			r.Request = redacted
+			r.Body = ioutil.NopCloser(bytes.NewReader(data))
+			b.debugHook(redacted, &r, data)
+		}
+	}
+
+	if cacheable && resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		data, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+		b.cache.put(key, resp, data)
+	}
+
+	// Metrics are recorded once the response body has been consumed and closed
+	statusCode := resp.StatusCode
+	resp.Body = &meteredBody{ReadCloser: resp.Body, record: func(respBytes int64) {
+		b.metrics.RecordRequest(method, request.URL.Path, statusCode, duration, int64(len(body)), respBytes)
+	}}
 
 	if b.debug {
 		data, _ := httputil.DumpRequest(request, true)
@@ -88,24 +370,25 @@ func (b *Base) QueryRequest(query string, v *url.Values) (*http.Response, error)
 		fmt.Printf("Response: %s", string(data))
 	}
 
-	if resp.StatusCode == statusRateLimitExceeded {
-		return nil, ErrorAPILimitExceeded
-	}
-	if resp.StatusCode == http.StatusUnauthorized {
-		return nil, ErrorAPIUnauthorized
+	b.recordResponse(resp)
+
+	// Convert error responses into APIErrors
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, newAPIError(resp.StatusCode, body)
 	}
 
 	return resp, nil
 }
 
-// QueryBase Query the mapbox API and fill the provided instance with the returned JSON
-// TODO: Rename this
-func (b *Base) QueryBase(query string, v *url.Values, inst interface{}) error {
-	// Make request
-	resp, err := b.QueryRequest(query, v)
-	if err != nil && (resp == nil || resp.StatusCode != http.StatusBadRequest) {
-		return err
-	}
+// decodeResponse reads the response body and decodes it into the provided instance (if any)
+func decodeResponse(resp *http.Response, inst interface{}) error {
 	defer resp.Body.Close()
 
 	// Read body into buffer
@@ -114,23 +397,68 @@ func (b *Base) QueryBase(query string, v *url.Values, inst interface{}) error {
 		return err
 	}
 
-	// Handle bad requests with messages
-	if resp.StatusCode == http.StatusBadRequest {
-		apiMessage := MapboxApiMessage{}
-		messageErr := json.Unmarshal(body, &apiMessage)
-		if messageErr == nil {
-			return fmt.Errorf("api error: %s", apiMessage.Message)
-		}
-		return fmt.Errorf("Bad Request (400) - no message")
+	// Responses without content (or callers without interest) are not decoded
+	if inst == nil || len(body) == 0 {
+		return nil
 	}
 
 	// Attempt to decode body into inst type
-	err = json.Unmarshal(body, &inst)
+	return json.Unmarshal(body, &inst)
+}
+
+// RawResponse captures the raw JSON body of a response while decoding it into Value (if not nil)
+// Pass a *RawResponse as the instance to a query to access fields that are not yet modelled
+type RawResponse struct {
+	Value interface{}
+	// Body is a copy of the response body, safe to retain
+	Body []byte
+}
+
+// UnmarshalJSON stores a copy of the raw body and decodes it into Value
+func (r *RawResponse) UnmarshalJSON(data []byte) error {
+	r.Body = append([]byte(nil), data...)
+	if r.Value == nil {
+		return nil
+	}
+	return json.Unmarshal(data, r.Value)
+}
+
+// QueryBase Query the mapbox API and fill the provided instance with the returned JSON
+// TODO: Rename this
+func (b *Base) QueryBase(query string, v *url.Values, inst interface{}) error {
+	return b.QueryBaseContext(context.Background(), query, v, inst)
+}
+
+// QueryBaseContext Query the mapbox API bound to the provided context and fill the provided instance with the returned JSON
+func (b *Base) QueryBaseContext(ctx context.Context, query string, v *url.Values, inst interface{}) error {
+	// Make request
+	resp, err := b.QueryRequestContext(ctx, query, v)
+	if err != nil {
+		return err
+	}
+
+	return decodeResponse(resp, inst)
+}
+
+// QueryWithBodyBase Query the mapbox API using the provided method and JSON encoded body (if not nil),
+// and fill the provided instance (if not nil) with the returned JSON
+func (b *Base) QueryWithBodyBase(ctx context.Context, method, query string, v *url.Values, body interface{}, inst interface{}) error {
+	var data []byte
+	if body != nil {
+		var err error
+		data, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Make request
+	resp, err := b.request(ctx, method, query, v, data)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return decodeResponse(resp, inst)
 }
 
 // Query the mapbox API
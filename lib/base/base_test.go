@@ -0,0 +1,1308 @@
+/**
+ * go-mapbox Base Module Tests
+ * Provides a common base for API modules
+ * See https://www.mapbox.com/api-documentation/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBase(t *testing.T, handler http.HandlerFunc) (*Base, *httptest.Server) {
+	server := httptest.NewServer(handler)
+
+	b, err := NewBase("test-token", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b, server
+}
+
+func TestAPIErrors(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		message    string
+		sentinel   error
+	}{
+		{"Bad request", http.StatusBadRequest, `{"message":"Invalid query"}`, "Invalid query", nil},
+		{"Unauthorized", http.StatusUnauthorized, `{"message":"Not Authorized - Invalid Token"}`, "Not Authorized - Invalid Token", ErrorAPIUnauthorized},
+		{"Not found", http.StatusNotFound, `{"message":"Not Found"}`, "Not Found", nil},
+		{"Unprocessable entity", http.StatusUnprocessableEntity, `{"message":"Coordinates are invalid"}`, "Coordinates are invalid", nil},
+		{"Rate limited", statusRateLimitExceeded, `{"message":"Too Many Requests"}`, "Too Many Requests", ErrorAPILimitExceeded},
+		{"Server error without JSON", http.StatusInternalServerError, `upstream failure`, "", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b, server := newTestBase(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.statusCode)
+				w.Write([]byte(test.body))
+			})
+			defer server.Close()
+
+			var inst map[string]interface{}
+			err := b.QueryBase("test/v1/query", &url.Values{}, &inst)
+
+			apiErr := &APIError{}
+			if !assert.True(t, errors.As(err, &apiErr)) {
+				t.FailNow()
+			}
+			assert.EqualValues(t, test.statusCode, apiErr.StatusCode)
+			assert.EqualValues(t, test.message, apiErr.Message)
+			assert.EqualValues(t, test.body, string(apiErr.Body))
+
+			if test.sentinel != nil {
+				assert.True(t, errors.Is(err, test.sentinel))
+			} else {
+				assert.False(t, errors.Is(err, ErrorAPIUnauthorized))
+				assert.False(t, errors.Is(err, ErrorAPILimitExceeded))
+			}
+		})
+	}
+
+	t.Run("Decodes successful responses", func(t *testing.T) {
+		b, server := newTestBase(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.EqualValues(t, "/test/v1/query", r.URL.Path)
+			assert.EqualValues(t, "test-token", r.URL.Query().Get("access_token"))
+			w.Write([]byte(`{"code":"Ok"}`))
+		})
+		defer server.Close()
+
+		var inst map[string]interface{}
+		err := b.QueryBase("test/v1/query", &url.Values{}, &inst)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "Ok", inst["code"])
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	status := http.StatusOK
+	b, server := newTestBase(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Rate-Limit-Interval", "60")
+		w.Header().Set("X-Rate-Limit-Limit", "600")
+		w.Header().Set("X-Rate-Limit-Reset", "1700000000")
+		w.Header().Set("X-Request-Id", r.URL.Query().Get("id"))
+		w.WriteHeader(status)
+		w.Write([]byte(`{}`))
+	})
+	defer server.Close()
+
+	query := func(id string) error {
+		v := url.Values{}
+		v.Set("id", id)
+		var inst map[string]interface{}
+		return b.QueryBase("test/v1/query", &v, &inst)
+	}
+
+	t.Run("Starts empty", func(t *testing.T) {
+		assert.True(t, b.LastRateLimit().IsZero())
+		assert.EqualValues(t, "", b.LastRequestID())
+	})
+
+	t.Run("Records successful responses", func(t *testing.T) {
+		assert.Nil(t, query("req-1"))
+
+		assert.EqualValues(t, RateLimitInfo{Interval: time.Minute, Limit: 600, Reset: time.Unix(1700000000, 0)}, b.LastRateLimit())
+		assert.EqualValues(t, "req-1", b.LastRequestID())
+	})
+
+	t.Run("Records rate limited responses", func(t *testing.T) {
+		status = statusRateLimitExceeded
+		defer func() { status = http.StatusOK }()
+
+		assert.True(t, errors.Is(query("req-2"), ErrorAPILimitExceeded))
+		assert.EqualValues(t, "req-2", b.LastRequestID())
+		assert.False(t, b.LastRateLimit().IsZero())
+	})
+
+	t.Run("Ignores other error responses", func(t *testing.T) {
+		status = http.StatusNotFound
+		defer func() { status = http.StatusOK }()
+
+		assert.NotNil(t, query("req-3"))
+		assert.EqualValues(t, "req-2", b.LastRequestID())
+	})
+
+	t.Run("Does not share state with clones", func(t *testing.T) {
+		c, err := b.CloneWithToken("other-token")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.True(t, c.LastRateLimit().IsZero())
+	})
+
+	t.Run("Is safe for concurrent use", func(t *testing.T) {
+		wg := sync.WaitGroup{}
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.Nil(t, query("concurrent"))
+				b.LastRateLimit()
+				b.LastRequestID()
+			}()
+		}
+		wg.Wait()
+
+		assert.EqualValues(t, "concurrent", b.LastRequestID())
+	})
+}
+
+func TestLoggers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"created"}`))
+	}))
+	defer server.Close()
+
+	var loggedRequest *http.Request
+	var requestBody, responseBody []byte
+	var loggedResponse *http.Response
+	var loggedDuration time.Duration
+
+	b, err := NewBase("test-token", WithBaseURL(server.URL),
+		WithRequestLogger(func(req *http.Request, body []byte) {
+			loggedRequest, requestBody = req, body
+		}),
+		WithResponseLogger(func(resp *http.Response, body []byte, duration time.Duration) {
+			loggedResponse, responseBody, loggedDuration = resp, body, duration
+		}),
+	)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	t.Run("Calls hooks with requests and responses", func(t *testing.T) {
+		resp := map[string]interface{}{}
+		err := b.QueryWithBodyBase(context.Background(), http.MethodPost, "test/v1/items", &url.Values{}, map[string]string{"name": "item"}, &resp)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		if assert.NotNil(t, loggedRequest) {
+			assert.EqualValues(t, http.MethodPost, loggedRequest.Method)
+			assert.EqualValues(t, "/test/v1/items", loggedRequest.URL.Path)
+		}
+		assert.JSONEq(t, `{"name":"item"}`, string(requestBody))
+
+		if assert.NotNil(t, loggedResponse) {
+			assert.EqualValues(t, http.StatusCreated, loggedResponse.StatusCode)
+		}
+		assert.EqualValues(t, `{"id":"created"}`, string(responseBody))
+		assert.True(t, loggedDuration > 0)
+
+		// The body is restored for decoding after logging
+		assert.EqualValues(t, "created", resp["id"])
+	})
+
+	t.Run("Prints requests and responses without tokens", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		writerRequestLogger(&buf)(loggedRequest, requestBody)
+		writerResponseLogger(&buf)(loggedResponse, responseBody, 1500*time.Millisecond)
+
+		assert.EqualValues(t, fmt.Sprintf("Request: POST %[1]s/test/v1/items?access_token=REDACTED\n{\"name\":\"item\"}\n"+
+			"Response: 201 Created %[1]s/test/v1/items?access_token=REDACTED (16 bytes, 1.5s)\n", server.URL), buf.String())
+	})
+}
+
+func TestDebugHook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Not Found"}`))
+			return
+		}
+		w.Write([]byte(`{"id":"debug"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	var hookRequest *http.Request
+	var hookResponse *http.Response
+	var hookBody []byte
+	calls := 0
+
+	b, err := NewBase("secret-token", WithBaseURL(server.URL), WithDebug(func(req *http.Request, resp *http.Response, body []byte) {
+		calls++
+		hookRequest, hookResponse, hookBody = req, resp, body
+	}))
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	t.Run("Calls the hook with redacted requests", func(t *testing.T) {
+		resp := map[string]interface{}{}
+		err := b.QueryBase("test", &url.Values{"limit": {"1"}}, &resp)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.EqualValues(t, 1, calls)
+		assert.EqualValues(t, "REDACTED", hookRequest.URL.Query().Get("access_token"))
+		assert.EqualValues(t, "1", hookRequest.URL.Query().Get("limit"))
+		assert.NotContains(t, hookResponse.Request.URL.String(), "secret-token")
+		assert.EqualValues(t, `{"id":"debug"}`, string(hookBody))
+
+		// The body is restored for decoding after the hook
+		assert.EqualValues(t, "debug", resp["id"])
+	})
+
+	t.Run("Calls the hook for error responses", func(t *testing.T) {
+		err := b.QueryBase("missing", &url.Values{}, nil)
+		assert.NotNil(t, err)
+		assert.EqualValues(t, 2, calls)
+		assert.EqualValues(t, http.StatusNotFound, hookResponse.StatusCode)
+		assert.EqualValues(t, `{"message":"Not Found"}`, string(hookBody))
+	})
+}
+
+func TestRawResponse(t *testing.T) {
+	body := `{"id":"raw","unmodelled":{"nested":[1,2,3]}}`
+	b, server := newTestBase(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	})
+	defer server.Close()
+
+	t.Run("Captures the body while decoding", func(t *testing.T) {
+		decoded := struct {
+			ID string `json:"id"`
+		}{}
+		raw := RawResponse{Value: &decoded}
+
+		err := b.QueryBase("test", &url.Values{}, &raw)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, body, string(raw.Body))
+		assert.EqualValues(t, "raw", decoded.ID)
+	})
+
+	t.Run("Captures the body without decoding", func(t *testing.T) {
+		raw := RawResponse{}
+		err := b.QueryWithBodyBase(context.Background(), http.MethodPost, "test", &url.Values{}, nil, &raw)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, body, string(raw.Body))
+	})
+}
+
+func TestPagination(t *testing.T) {
+	requests := 0
+	b, server := newTestBase(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.EqualValues(t, "/test/v1/items", r.URL.Path)
+		assert.EqualValues(t, "2", r.URL.Query().Get("limit"))
+
+		switch r.URL.Query().Get("start") {
+		case "":
+			w.Header().Set("Link", `<https://api.mapbox.com/test/v1/items?limit=2&start=b>; rel="next"`)
+			w.Write([]byte(`["a","b"]`))
+		case "b":
+			w.Write([]byte(`["c"]`))
+		default:
+			t.Errorf("Unexpected cursor %s", r.URL.Query().Get("start"))
+		}
+	})
+	defer server.Close()
+
+	v := url.Values{}
+	v.Set("limit", "2")
+
+	t.Run("Parses next cursors", func(t *testing.T) {
+		p := NewPaginator(&http.Response{Header: http.Header{"Link": []string{
+			`<https://api.mapbox.com/test/v1/items?start=first>; rel="first", <https://api.mapbox.com/test/v1/items?limit=2&start=cjnext>; rel="next"`,
+		}}})
+		cursor, more := p.Next()
+		assert.True(t, more)
+		assert.EqualValues(t, "cjnext", cursor)
+
+		_, more = NewPaginator(&http.Response{Header: http.Header{}}).Next()
+		assert.False(t, more)
+	})
+
+	t.Run("Queries all pages", func(t *testing.T) {
+		requests = 0
+		items := make([]string, 0)
+		err := b.QueryPaginated(context.Background(), "test/v1/items", &v, func(body []byte) error {
+			page := make([]string, 0)
+			err := json.Unmarshal(body, &page)
+			items = append(items, page...)
+			return err
+		})
+		assert.Nil(t, err)
+		assert.EqualValues(t, []string{"a", "b", "c"}, items)
+		assert.EqualValues(t, 2, requests)
+		assert.EqualValues(t, "", v.Get("start"))
+	})
+
+	t.Run("Stops on callback errors", func(t *testing.T) {
+		requests = 0
+		stop := errors.New("stop")
+		err := b.QueryPaginated(context.Background(), "test/v1/items", &v, func(body []byte) error {
+			return stop
+		})
+		assert.True(t, errors.Is(err, stop))
+		assert.EqualValues(t, 1, requests)
+	})
+
+	t.Run("Respects context cancellation", func(t *testing.T) {
+		requests = 0
+		ctx, cancel := context.WithCancel(context.Background())
+		err := b.QueryPaginated(ctx, "test/v1/items", &v, func(body []byte) error {
+			cancel()
+			return nil
+		})
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.EqualValues(t, 1, requests)
+	})
+}
+
+type testMetricsRecord struct {
+	method, path        string
+	statusCode          int
+	duration            time.Duration
+	reqBytes, respBytes int64
+}
+
+type testMetricsRecorder struct {
+	records []testMetricsRecord
+}
+
+func (r *testMetricsRecorder) RecordRequest(method, path string, statusCode int, duration time.Duration, reqBytes, respBytes int64) {
+	r.records = append(r.records, testMetricsRecord{method, path, statusCode, duration, reqBytes, respBytes})
+}
+
+func TestCache(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(fmt.Sprintf(`{"path":"%s","request":%d}`, r.URL.Path, requests)))
+	}))
+	t.Cleanup(server.Close)
+
+	newCachedBase := func(t *testing.T, ttl time.Duration, maxEntries int) *Base {
+		requests = 0
+		b, err := NewBase("test-token", WithBaseURL(server.URL), WithCache(ttl, maxEntries))
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		return b
+	}
+
+	query := func(b *Base, path string, v url.Values) map[string]interface{} {
+		resp := map[string]interface{}{}
+		err := b.QueryBase(path, &v, &resp)
+		assert.Nil(t, err)
+		return resp
+	}
+
+	t.Run("Rejects invalid configuration", func(t *testing.T) {
+		_, err := NewBase("test-token", WithCache(0, 10))
+		assert.NotNil(t, err)
+		_, err = NewBase("test-token", WithCache(time.Minute, 0))
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Serves copies of cached responses", func(t *testing.T) {
+		b := newCachedBase(t, time.Minute, 10)
+
+		first := query(b, "test/v1/a", url.Values{"limit": {"1"}})
+		first["path"] = "modified"
+
+		second := query(b, "test/v1/a", url.Values{"limit": {"1"}})
+		assert.EqualValues(t, "/test/v1/a", second["path"])
+		assert.EqualValues(t, 1, requests)
+
+		// Differing parameters are cached separately
+		query(b, "test/v1/a", url.Values{"limit": {"2"}})
+		assert.EqualValues(t, 2, requests)
+
+		assert.EqualValues(t, CacheStats{Hits: 1, Misses: 2}, b.CacheStats())
+
+		b.CacheClear()
+		query(b, "test/v1/a", url.Values{"limit": {"1"}})
+		assert.EqualValues(t, 3, requests)
+	})
+
+	t.Run("Excludes tokens from keys", func(t *testing.T) {
+		key, ok := cacheKey(http.MethodGet, "https://api.mapbox.com/test/v1/a", url.Values{"access_token": {"test-token"}, "limit": {"1"}})
+		assert.True(t, ok)
+		assert.EqualValues(t, "https://api.mapbox.com/test/v1/a?limit=1", key)
+
+		b := newCachedBase(t, time.Minute, 10)
+		query(b, "test/v1/a", url.Values{})
+		assert.EqualValues(t, 1, requests)
+
+		// Clones use a separate cache
+		c, err := b.CloneWithToken("other-token")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		query(c, "test/v1/a", url.Values{})
+		assert.EqualValues(t, 2, requests)
+	})
+
+	t.Run("Evicts least recently used responses", func(t *testing.T) {
+		b := newCachedBase(t, time.Minute, 2)
+
+		query(b, "test/v1/a", url.Values{})
+		query(b, "test/v1/b", url.Values{})
+		query(b, "test/v1/a", url.Values{})
+		query(b, "test/v1/c", url.Values{})
+		assert.EqualValues(t, 3, requests)
+
+		query(b, "test/v1/a", url.Values{})
+		query(b, "test/v1/b", url.Values{})
+		assert.EqualValues(t, 4, requests)
+		assert.EqualValues(t, 2, b.CacheStats().Evictions)
+	})
+
+	t.Run("Expires responses", func(t *testing.T) {
+		b := newCachedBase(t, 10*time.Millisecond, 10)
+
+		query(b, "test/v1/a", url.Values{})
+		time.Sleep(20 * time.Millisecond)
+		query(b, "test/v1/a", url.Values{})
+		assert.EqualValues(t, 2, requests)
+	})
+
+	t.Run("Bypasses permanent geocoding", func(t *testing.T) {
+		b := newCachedBase(t, time.Minute, 10)
+
+		query(b, "geocoding/v5/mapbox.places-permanent/sydney.json", url.Values{})
+		query(b, "geocoding/v5/mapbox.places-permanent/sydney.json", url.Values{})
+		query(b, "search/geocode/v6/forward", url.Values{"permanent": {"true"}})
+		query(b, "search/geocode/v6/forward", url.Values{"permanent": {"true"}})
+		assert.EqualValues(t, 4, requests)
+	})
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	requests, failing := 0, true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch {
+		case r.URL.Path == "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case failing:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	t.Run("Rejects invalid configuration", func(t *testing.T) {
+		_, err := NewBase("test-token", WithCircuitBreaker(0, time.Second))
+		assert.NotNil(t, err)
+		_, err = NewBase("test-token", WithCircuitBreaker(1, 0))
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Transitions between states", func(t *testing.T) {
+		b, err := NewBase("test-token", WithBaseURL(server.URL), WithCircuitBreaker(3, 20*time.Millisecond))
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		query := func(path string) error {
+			return b.QueryBase(path, &url.Values{}, nil)
+		}
+
+		// Client errors do not count as failures
+		for i := 0; i < 5; i++ {
+			assert.NotNil(t, query("missing"))
+		}
+		assert.EqualValues(t, Closed, b.CircuitState())
+
+		requests = 0
+		for i := 0; i < 3; i++ {
+			assert.NotNil(t, query("test"))
+		}
+		assert.EqualValues(t, Open, b.CircuitState())
+		assert.True(t, errors.Is(query("test"), ErrCircuitOpen))
+		assert.EqualValues(t, 3, requests)
+
+		// A failed probe reopens the circuit and resets the timer
+		time.Sleep(30 * time.Millisecond)
+		err = query("test")
+		assert.False(t, errors.Is(err, ErrCircuitOpen))
+		assert.EqualValues(t, Open, b.CircuitState())
+		assert.True(t, errors.Is(query("test"), ErrCircuitOpen))
+		assert.EqualValues(t, 4, requests)
+
+		// A successful probe closes the circuit
+		failing = false
+		time.Sleep(30 * time.Millisecond)
+		assert.Nil(t, query("test"))
+		assert.EqualValues(t, Closed, b.CircuitState())
+		assert.Nil(t, query("test"))
+		assert.EqualValues(t, 6, requests)
+	})
+
+	t.Run("Allows a single probe", func(t *testing.T) {
+		c := &circuitBreaker{threshold: 1, resetTimeout: time.Millisecond}
+		generation, err := c.allow()
+		assert.Nil(t, err)
+		c.record(generation, 0, errors.New("connection refused"))
+		assert.EqualValues(t, Open, c.state)
+
+		time.Sleep(5 * time.Millisecond)
+		probe, err := c.allow()
+		assert.Nil(t, err)
+		assert.EqualValues(t, HalfOpen, c.state)
+		_, err = c.allow()
+		assert.EqualValues(t, ErrCircuitOpen, err)
+
+		// Cancelled probes release the half open circuit
+		c.record(probe, 0, context.Canceled)
+		assert.EqualValues(t, HalfOpen, c.state)
+		_, err = c.allow()
+		assert.Nil(t, err)
+	})
+
+	t.Run("Ignores requests sent before the circuit opened", func(t *testing.T) {
+		arrived := make(chan struct{})
+		release := map[string]chan struct{}{"stale-ok": make(chan struct{}), "stale-fail": make(chan struct{}), "probe": make(chan struct{})}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/fail" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			arrived <- struct{}{}
+			<-release[r.URL.Query().Get("id")]
+			if r.URL.Query().Get("id") == "stale-fail" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{}`))
+		}))
+		t.Cleanup(server.Close)
+
+		b, err := NewBase("test-token", WithBaseURL(server.URL), WithCircuitBreaker(1, 20*time.Millisecond))
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		done := make(map[string]chan error)
+		send := func(id string) {
+			result := make(chan error, 1)
+			done[id] = result
+			go func() {
+				result <- b.QueryBase("slow", &url.Values{"id": {id}}, nil)
+			}()
+			<-arrived
+		}
+
+		send("stale-ok")
+		send("stale-fail")
+		assert.NotNil(t, b.QueryBase("fail", &url.Values{}, nil))
+		assert.EqualValues(t, Open, b.CircuitState())
+
+		// A stale success does not close the open circuit
+		close(release["stale-ok"])
+		assert.Nil(t, <-done["stale-ok"])
+		assert.EqualValues(t, Open, b.CircuitState())
+
+		time.Sleep(30 * time.Millisecond)
+		send("probe")
+		assert.EqualValues(t, HalfOpen, b.CircuitState())
+
+		// A stale failure neither reopens the circuit nor releases the probe
+		close(release["stale-fail"])
+		assert.NotNil(t, <-done["stale-fail"])
+		assert.EqualValues(t, HalfOpen, b.CircuitState())
+		assert.True(t, errors.Is(b.QueryBase("slow", &url.Values{}, nil), ErrCircuitOpen))
+
+		close(release["probe"])
+		assert.Nil(t, <-done["probe"])
+		assert.EqualValues(t, Closed, b.CircuitState())
+	})
+}
+
+func TestMaxConcurrency(t *testing.T) {
+	_, err := NewBase("test-token", WithMaxConcurrency(0))
+	assert.NotNil(t, err)
+
+	t.Run("Limits requests in flight", func(t *testing.T) {
+		var inFlight, maxInFlight, requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			atomic.AddInt32(&requests, 1)
+
+			for {
+				m := atomic.LoadInt32(&maxInFlight)
+				if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+					break
+				}
+			}
+
+			time.Sleep(2 * time.Millisecond)
+			w.Write([]byte(`{}`))
+		}))
+		t.Cleanup(server.Close)
+
+		b, err := NewBase("test-token", WithBaseURL(server.URL), WithMaxConcurrency(3))
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		wg := sync.WaitGroup{}
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.Nil(t, b.QueryBaseContext(context.Background(), "test", &url.Values{}, nil))
+			}()
+		}
+		wg.Wait()
+
+		assert.EqualValues(t, 50, requests)
+		assert.True(t, maxInFlight <= 3, "max in flight %d", maxInFlight)
+		assert.Len(t, b.semaphore, 0)
+	})
+
+	t.Run("Cancels waiting requests", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+			w.Write([]byte(`{}`))
+		}))
+		t.Cleanup(server.Close)
+
+		b, err := NewBase("test-token", WithBaseURL(server.URL), WithMaxConcurrency(1))
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		done := make(chan error)
+		go func() {
+			done <- b.QueryBaseContext(context.Background(), "test", &url.Values{}, nil)
+		}()
+		for len(b.semaphore) == 0 {
+			time.Sleep(time.Millisecond)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err = b.QueryBaseContext(ctx, "test", &url.Values{}, nil)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+		close(release)
+		assert.Nil(t, <-done)
+	})
+}
+
+func TestMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/test/v1/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Not Found"}`))
+			return
+		}
+		w.Write([]byte(`{"id":"created"}`))
+	}))
+	defer server.Close()
+
+	t.Run("Defaults to no metrics", func(t *testing.T) {
+		b, err := NewBase("test-token")
+		assert.Nil(t, err)
+		assert.EqualValues(t, NoopMetricsRecorder{}, b.metrics)
+	})
+
+	t.Run("Records requests", func(t *testing.T) {
+		recorder := testMetricsRecorder{}
+		b, err := NewBase("test-token", WithBaseURL(server.URL), WithMetricsRecorder(&recorder))
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		var resp map[string]interface{}
+		err = b.QueryWithBodyBase(context.Background(), http.MethodPost, "test/v1/items", &url.Values{}, map[string]string{"name": "item"}, &resp)
+		assert.Nil(t, err)
+		err = b.QueryBase("test/v1/missing", &url.Values{}, &resp)
+		assert.NotNil(t, err)
+
+		if !assert.Len(t, recorder.records, 2) {
+			t.FailNow()
+		}
+
+		created := recorder.records[0]
+		assert.EqualValues(t, http.MethodPost, created.method)
+		assert.EqualValues(t, "/test/v1/items", created.path)
+		assert.EqualValues(t, http.StatusOK, created.statusCode)
+		assert.EqualValues(t, len(`{"name":"item"}`), created.reqBytes)
+		assert.EqualValues(t, len(`{"id":"created"}`), created.respBytes)
+		assert.True(t, created.duration > 0)
+
+		missing := recorder.records[1]
+		assert.EqualValues(t, http.MethodGet, missing.method)
+		assert.EqualValues(t, http.StatusNotFound, missing.statusCode)
+		assert.EqualValues(t, 0, missing.reqBytes)
+		assert.EqualValues(t, len(`{"message":"Not Found"}`), missing.respBytes)
+	})
+
+	t.Run("Records failed requests", func(t *testing.T) {
+		recorder := testMetricsRecorder{}
+		b, err := NewBase("test-token", WithBaseURL("http://127.0.0.1:1"), WithMetricsRecorder(&recorder))
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		err = b.QueryBase("test/v1/items", &url.Values{}, nil)
+		assert.NotNil(t, err)
+		if assert.Len(t, recorder.records, 1) {
+			assert.EqualValues(t, 0, recorder.records[0].statusCode)
+		}
+	})
+}
+
+type testTransport struct {
+	requests []*http.Request
+}
+
+func (t *testTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests = append(t.requests, req)
+	req = req.Clone(req.Context())
+	req.Header.Set("X-Test", "transport")
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestTransport(t *testing.T) {
+	b, server := newTestBase(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.EqualValues(t, "transport", r.Header.Get("X-Test"))
+		w.Write([]byte(`{}`))
+	})
+	defer server.Close()
+
+	transport := testTransport{}
+	err := WithTransport(&transport)(b)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	var inst map[string]interface{}
+	assert.Nil(t, b.QueryBase("test/v1/query", &url.Values{}, &inst))
+	if assert.Len(t, transport.requests, 1) {
+		assert.EqualValues(t, "/test/v1/query", transport.requests[0].URL.Path)
+	}
+}
+
+func TestBaseURL(t *testing.T) {
+
+	t.Run("Defaults to the Mapbox API", func(t *testing.T) {
+		b, err := NewBase("test-token")
+		assert.Nil(t, err)
+		assert.EqualValues(t, BaseURL, b.baseURL)
+	})
+
+	t.Run("Rejects malformed URLs", func(t *testing.T) {
+		for _, u := range []string{"", "api.example.com", "ftp://api.example.com", "https://", "http://[::1", "http://api.example.com", "/relative/path"} {
+			_, err := NewBase("test-token", WithBaseURL(u))
+			assert.NotNil(t, err, u)
+		}
+	})
+
+	t.Run("Permits http for localhost only", func(t *testing.T) {
+		for _, u := range []string{"http://localhost:8080", "http://127.0.0.1", "http://[::1]:9000", "https://mapbox.example.com"} {
+			_, err := NewBase("test-token", WithBaseURL(u))
+			assert.Nil(t, err, u)
+		}
+	})
+
+	t.Run("Resolves paths without duplicate slashes", func(t *testing.T) {
+		for _, u := range []string{"https://mapbox.example.com/api", "https://mapbox.example.com/api/", "https://mapbox.example.com/api//"} {
+			b, err := NewBase("test-token", WithBaseURL(u))
+			if !assert.Nil(t, err) {
+				t.FailNow()
+			}
+			assert.EqualValues(t, "https://mapbox.example.com/api/styles/v1/mapbox/streets-v12", b.queryURL("styles/v1/mapbox/streets-v12"), u)
+			assert.EqualValues(t, "https://mapbox.example.com/api/styles/v1/mapbox/streets-v12", b.queryURL("/styles/v1/mapbox/streets-v12"), u)
+		}
+
+		b, err := NewBase("test-token")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, BaseURL+"/directions/v5/mapbox/driving/-122.42,37.78;-77.03,38.91", b.queryURL("directions/v5/mapbox/driving/-122.42,37.78;-77.03,38.91"))
+	})
+
+	t.Run("Composes paths against a custom host", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.EqualValues(t, "/proxy/search/geocode/v6/forward", r.URL.Path)
+			assert.EqualValues(t, "paris", r.URL.Query().Get("q"))
+			w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		for _, u := range []string{server.URL + "/proxy", server.URL + "/proxy/"} {
+			b, err := NewBase("test-token", WithBaseURL(u))
+			if !assert.Nil(t, err) {
+				t.FailNow()
+			}
+
+			v := url.Values{}
+			v.Set("q", "paris")
+
+			var inst map[string]interface{}
+			err = b.QueryBase("search/geocode/v6/forward", &v, &inst)
+			assert.Nil(t, err)
+		}
+	})
+}
+
+func TestLanguageCodes(t *testing.T) {
+	for l := range languageCodes {
+		assert.True(t, ValidLanguageCode(string(l)), string(l))
+	}
+
+	for _, l := range []string{"EN", "zh_Hans", "zh-hant", "ZH_HANT"} {
+		assert.True(t, ValidLanguageCode(l), l)
+	}
+
+	for _, l := range []string{"", "english", "xx", "en-", "zh-Hanz", "en,fr"} {
+		assert.False(t, ValidLanguageCode(l), l)
+	}
+}
+
+func TestUsername(t *testing.T) {
+
+	t.Run("Parses usernames from tokens", func(t *testing.T) {
+		b, err := NewBase("pk.eyJ1IjoiZXhhbXBsZSIsImEiOiJjamFiYyJ9.signature")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		username, err := b.Username()
+		assert.Nil(t, err)
+		assert.EqualValues(t, "example", username)
+	})
+
+	t.Run("Rejects malformed tokens", func(t *testing.T) {
+		for _, token := range []string{"test-token", "pk.!!!.signature", "pk.e30.signature"} {
+			b, err := NewBase(token)
+			if !assert.Nil(t, err) {
+				t.FailNow()
+			}
+
+			_, err = b.Username()
+			assert.NotNil(t, err, token)
+		}
+	})
+}
+
+func TestFeatureJSON(t *testing.T) {
+
+	t.Run("Round trips non-point geometries", func(t *testing.T) {
+		data := `{"id":"a","type":"Feature","properties":{"name":"park","area":12.5},"geometry":{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}}`
+
+		f := Feature{}
+		err := json.Unmarshal([]byte(data), &f)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "Polygon", f.Geometry.Type)
+		assert.Nil(t, f.Geometry.Coordinates)
+		assert.EqualValues(t, "park", f.RawProperties["name"])
+
+		encoded, err := json.Marshal(&f)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.JSONEq(t, data, string(encoded))
+	})
+
+	t.Run("Decodes numeric IDs", func(t *testing.T) {
+		f := Feature{}
+		err := json.Unmarshal([]byte(`{"id":1234,"type":"Feature","properties":{},"geometry":null}`), &f)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "1234", f.ID)
+	})
+
+	t.Run("Encodes point geometries", func(t *testing.T) {
+		f := Feature{Geometry: Geometry{Type: "Point", Coordinates: []float64{151.2, -33.8}}}
+
+		encoded, err := json.Marshal(&f)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.JSONEq(t, `{"type":"Feature","properties":{"category":"","tel":"","wikidata":"","landmark":false,"short_code":""},"geometry":{"type":"Point","coordinates":[151.2,-33.8]}}`, string(encoded))
+	})
+}
+
+func TestGeometryTypes(t *testing.T) {
+
+	decode := func(t *testing.T, data string) *Geometry {
+		g := Geometry{}
+		if !assert.Nil(t, json.Unmarshal([]byte(data), &g)) {
+			t.FailNow()
+		}
+		return &g
+	}
+
+	t.Run("Decodes points", func(t *testing.T) {
+		g := decode(t, `{"type":"Point","coordinates":[151.2,-33.8,12]}`)
+
+		p, err := g.AsPoint()
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, Point{151.2, -33.8}, p)
+		assert.EqualValues(t, Location{Latitude: -33.8, Longitude: 151.2}, p.Location())
+
+		p, err = (&Geometry{Type: "Point", Coordinates: []float64{1, 2}}).AsPoint()
+		assert.Nil(t, err)
+		assert.EqualValues(t, Point{1, 2}, p)
+
+		_, err = (&Geometry{Type: "Point", Coordinates: []float64{1}}).AsPoint()
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Decodes line strings", func(t *testing.T) {
+		g := decode(t, `{"type":"LineString","coordinates":[[0,0],[1,1],[2,0]]}`)
+
+		l, err := g.AsLineString()
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, LineString{{0, 0}, {1, 1}, {2, 0}}, l)
+	})
+
+	t.Run("Decodes polygons", func(t *testing.T) {
+		g := decode(t, `{"type":"Polygon","coordinates":[[[0,0],[4,0],[4,4],[0,0]],[[1,1],[2,1],[2,2],[1,1]]]}`)
+
+		p, err := g.AsPolygon()
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.Len(t, p, 2)
+		assert.EqualValues(t, []Point{{0, 0}, {4, 0}, {4, 4}, {0, 0}}, p[0])
+	})
+
+	t.Run("Decodes multi polygons", func(t *testing.T) {
+		g := decode(t, `{"type":"MultiPolygon","coordinates":[[[[0,0],[1,0],[1,1],[0,0]]],[[[5,5],[6,5],[6,6],[5,5]]]]}`)
+
+		m, err := g.AsMultiPolygon()
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.Len(t, m, 2)
+		assert.EqualValues(t, Point{5, 5}, m[1][0][0])
+	})
+
+	t.Run("Rejects mismatched types", func(t *testing.T) {
+		g := decode(t, `{"type":"LineString","coordinates":[[0,0],[1,1]]}`)
+
+		_, err := g.AsPoint()
+		assert.EqualError(t, err, "Geometry type LineString is not Point")
+		_, err = g.AsPolygon()
+		assert.NotNil(t, err)
+		_, err = g.AsMultiPolygon()
+		assert.NotNil(t, err)
+	})
+}
+
+func TestBoundingBox(t *testing.T) {
+
+	t.Run("Validates bounds", func(t *testing.T) {
+		_, err := NewBoundingBox(10, 0, -10, 5)
+		assert.NotNil(t, err)
+
+		_, err = NewBoundingBox(-10, 5, 10, 5)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Checks containment", func(t *testing.T) {
+		b, err := NewBoundingBox(-122.5, 37.7, -122.3, 37.8)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.True(t, b.Contains(Location{Latitude: 37.75, Longitude: -122.4}))
+		assert.True(t, b.Contains(Location{Latitude: 37.7, Longitude: -122.5}))
+		assert.False(t, b.Contains(Location{Latitude: 37.9, Longitude: -122.4}))
+		assert.False(t, BoundingBox{}.Contains(Location{}))
+	})
+
+	t.Run("Creates boxes from locations", func(t *testing.T) {
+		assert.Len(t, BoundingBoxFromLocations(nil), 0)
+
+		single := BoundingBoxFromLocations([]Location{{Latitude: 37.75, Longitude: -122.4}})
+		assert.EqualValues(t, BoundingBox{-122.4, 37.75, -122.4, 37.75}, single)
+		assert.True(t, single.Contains(Location{Latitude: 37.75, Longitude: -122.4}))
+
+		b := BoundingBoxFromLocations([]Location{
+			{Latitude: 37.8, Longitude: -122.3},
+			{Latitude: 37.7, Longitude: -122.5},
+			{Latitude: 37.75, Longitude: -122.4},
+		})
+		assert.EqualValues(t, BoundingBox{-122.5, 37.7, -122.3, 37.8}, b)
+		assert.False(t, b.CrossesAntimeridian())
+	})
+
+	t.Run("Handles boxes straddling the antimeridian", func(t *testing.T) {
+		// Fiji spans the 180th meridian
+		b := BoundingBoxFromLocations([]Location{
+			{Latitude: -16.5, Longitude: 179.5},
+			{Latitude: -18.1, Longitude: 178.4},
+			{Latitude: -17.0, Longitude: -179.8},
+		})
+		assert.EqualValues(t, BoundingBox{178.4, -18.1, -179.8, -16.5}, b)
+		assert.True(t, b.CrossesAntimeridian())
+
+		assert.True(t, b.Contains(Location{Latitude: -17, Longitude: 180}))
+		assert.True(t, b.Contains(Location{Latitude: -17, Longitude: -179.9}))
+		assert.False(t, b.Contains(Location{Latitude: -17, Longitude: 0}))
+
+		center := b.Center()
+		assert.InDelta(t, -17.3, center.Latitude, 1e-9)
+		assert.InDelta(t, 179.3, center.Longitude, 1e-9)
+
+		assert.True(t, b.Intersects(BoundingBox{-179.9, -17, -179, -16}))
+		assert.True(t, b.Intersects(BoundingBox{170, -20, 179, -10}))
+		assert.False(t, b.Intersects(BoundingBox{-170, -20, 170, -10}))
+		assert.True(t, b.Intersects(BoundingBox{179, -20, -170, -10}))
+
+		expanded := b.Expand(0.5)
+		assert.InDeltaSlice(t, []float64{177.9, -18.6, -179.3, -16}, expanded, 1e-9)
+
+		assert.EqualValues(t, BoundingBox{-180, -12, 180, 12}, BoundingBox{-179, -10, 179, 10}.Expand(2))
+		assert.EqualValues(t, BoundingBox{-180, -90, 180, 90}, b.Expand(180))
+	})
+
+	t.Run("Checks intersection", func(t *testing.T) {
+		b := BoundingBox{-122.5, 37.7, -122.3, 37.8}
+
+		assert.True(t, b.Intersects(BoundingBox{-122.4, 37.75, -122.0, 38.0}))
+		assert.True(t, b.Intersects(BoundingBox{-122.3, 37.8, -122.0, 38.0}))
+		assert.False(t, b.Intersects(BoundingBox{-122.2, 37.7, -122.0, 37.8}))
+		assert.False(t, b.Intersects(BoundingBox{}))
+
+		assert.EqualValues(t, Location{Latitude: 37.75, Longitude: -122.4}, b.Center())
+		assert.InDeltaSlice(t, []float64{-122.6, 37.6, -122.2, 37.9}, b.Expand(0.1), 1e-9)
+	})
+
+	t.Run("Encodes query values", func(t *testing.T) {
+		b, err := NewBoundingBox(-122.5, 37.7, -122.3, 37.8)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "-122.5,37.7,-122.3,37.8", b.String())
+
+		v := url.Values{}
+		assert.Nil(t, b.EncodeValues("bbox", &v))
+		assert.EqualValues(t, "-122.5,37.7,-122.3,37.8", v.Get("bbox"))
+	})
+}
+
+func TestPolyline(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		encoded   string
+		precision int
+		locations []Location
+	}{
+		{"empty", "", 5, []Location{}},
+		{"precision 5", "_p~iF~ps|U_ulLnnqC_mqNvxq`@", 5, []Location{
+			{Latitude: 38.5, Longitude: -120.2},
+			{Latitude: 40.7, Longitude: -120.95},
+			{Latitude: 43.252, Longitude: -126.453},
+		}},
+		{"precision 6", "_izlhA~rlgdF_{geC~ywl@_kwzCn`{nI", 6, []Location{
+			{Latitude: 38.5, Longitude: -120.2},
+			{Latitude: 40.7, Longitude: -120.95},
+			{Latitude: 43.252, Longitude: -126.453},
+		}},
+		{"negative coordinates", "b_vmEaa|y[", 5, []Location{
+			{Latitude: -33.86882, Longitude: 151.20929},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locations, err := DecodePolyline(tt.encoded, tt.precision)
+			if !assert.Nil(t, err) {
+				t.FailNow()
+			}
+			if !assert.Len(t, locations, len(tt.locations)) {
+				t.FailNow()
+			}
+			for i := range locations {
+				assert.InDelta(t, tt.locations[i].Latitude, locations[i].Latitude, 1e-9)
+				assert.InDelta(t, tt.locations[i].Longitude, locations[i].Longitude, 1e-9)
+			}
+
+			assert.EqualValues(t, tt.encoded, EncodePolyline(tt.locations, tt.precision))
+		})
+	}
+
+	t.Run("Rejects malformed polylines", func(t *testing.T) {
+		_, err := DecodePolyline("_p~iF~ps|U_ulL", 5)
+		assert.NotNil(t, err)
+
+		_, err = DecodePolyline("_p~iF ps|U", 5)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Rejects unsupported precisions", func(t *testing.T) {
+		_, err := DecodePolyline("_p~iF~ps|U", 7)
+		validationErr := &ValidationError{}
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.EqualValues(t, "precision", validationErr.Field)
+		}
+
+		assert.Panics(t, func() { EncodePolyline([]Location{{Latitude: 1, Longitude: 2}}, 0) })
+	})
+}
+
+func TestGeodesy(t *testing.T) {
+	london := Location{Latitude: 51.5074, Longitude: -0.1278}
+	newYork := Location{Latitude: 40.7128, Longitude: -74.0060}
+
+	tests := []struct {
+		name         string
+		a, b         Location
+		haversine    float64
+		vincenty     float64
+		initial      float64
+		final        float64
+		midpoint     Location
+		skipBearings bool
+	}{
+		{
+			name: "london to new york", a: london, b: newYork,
+			haversine: 5570230, vincenty: 5585234, initial: 288.33, final: 231.21,
+			midpoint: Location{Latitude: 52.3684, Longitude: -41.2903},
+		},
+		{
+			name: "identical points", a: london, b: london,
+			haversine: 0, vincenty: 0, initial: 0, final: 0,
+			midpoint: london,
+		},
+		{
+			name: "across the antimeridian", a: Location{Latitude: -17, Longitude: 179}, b: Location{Latitude: -17, Longitude: -179},
+			haversine: 212672, vincenty: 212971, initial: 90.29, final: 89.71,
+			midpoint: Location{Latitude: -17.0024, Longitude: 180},
+		},
+		{
+			name: "equator to north pole", a: Location{Latitude: 0, Longitude: 0}, b: Location{Latitude: 90, Longitude: 0},
+			haversine: 10007557, vincenty: 10001966, initial: 0, final: 0,
+			midpoint: Location{Latitude: 45, Longitude: 0},
+		},
+		{
+			name: "pole to pole", a: Location{Latitude: 90, Longitude: 0}, b: Location{Latitude: -90, Longitude: 10},
+			haversine: 20015114, vincenty: 20003931, skipBearings: true,
+			midpoint: Location{Latitude: 0, Longitude: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.haversine, HaversineDistance(tt.a, tt.b), 1)
+
+			d, err := VincentyDistance(tt.a, tt.b)
+			if assert.Nil(t, err) {
+				assert.InDelta(t, tt.vincenty, d, 1)
+			}
+
+			if !tt.skipBearings {
+				assert.InDelta(t, tt.initial, InitialBearing(tt.a, tt.b), 0.01)
+				assert.InDelta(t, tt.final, FinalBearing(tt.a, tt.b), 0.01)
+			}
+
+			m := Midpoint(tt.a, tt.b)
+			assert.InDelta(t, tt.midpoint.Latitude, m.Latitude, 1e-4)
+			assert.InDelta(t, tt.midpoint.Longitude, m.Longitude, 1e-4)
+		})
+	}
+
+	t.Run("Rejects antipodal points", func(t *testing.T) {
+		_, err := VincentyDistance(Location{Latitude: 0, Longitude: 0}, Location{Latitude: 0, Longitude: 180})
+		assert.True(t, errors.Is(err, ErrVincentyNoConvergence))
+	})
+}
+
+func TestFeatureCollection(t *testing.T) {
+	point := func(id string, lng, lat float64, props map[string]interface{}) Feature {
+		return Feature{ID: id, Geometry: Geometry{Type: "Point", Coordinates: []float64{lng, lat}}, RawProperties: props}
+	}
+
+	fc := FeatureCollection{
+		Type: "FeatureCollection",
+		Features: []Feature{
+			point("sydney", 151.2093, -33.8688, map[string]interface{}{"feature_type": "place", "relevance": 0.5}),
+			point("opera-house", 151.2153, -33.8568, map[string]interface{}{"feature_type": "poi", "relevance": 0.9}),
+			{ID: "bondi", PlaceType: []string{"neighborhood"}, Center: []float64{151.2743, -33.8915}, Relevance: 0.7},
+			{ID: "nsw", Geometry: Geometry{Type: "Polygon"}, RawProperties: map[string]interface{}{"feature_type": "region"}},
+			point("melbourne", 144.9631, -37.8136, map[string]interface{}{"feature_type": "place", "tags": []interface{}{"vic"}}),
+		},
+	}
+
+	ids := func(c *FeatureCollection) []string {
+		s := make([]string, len(c.Features))
+		for i, f := range c.Features {
+			s[i] = f.ID
+		}
+		return s
+	}
+
+	t.Run("Filters by type", func(t *testing.T) {
+		assert.EqualValues(t, []string{"sydney", "melbourne"}, ids(fc.FilterByType("place")))
+		assert.EqualValues(t, []string{"bondi"}, ids(fc.FilterByType("neighborhood")))
+		assert.Len(t, fc.FilterByType("country").Features, 0)
+	})
+
+	t.Run("Filters by bounding box", func(t *testing.T) {
+		bb := BoundingBox{151.0, -34.0, 151.5, -33.5}
+		filtered := fc.FilterByBBox(bb)
+		assert.EqualValues(t, []string{"sydney", "opera-house", "bondi"}, ids(filtered))
+		assert.EqualValues(t, "FeatureCollection", filtered.Type)
+	})
+
+	t.Run("Sorts by distance", func(t *testing.T) {
+		origin := Location{Latitude: -33.8570, Longitude: 151.2150}
+		assert.EqualValues(t, []string{"opera-house", "sydney", "bondi", "melbourne", "nsw"}, ids(fc.SortByDistance(origin)))
+	})
+
+	t.Run("Sorts by relevance", func(t *testing.T) {
+		assert.EqualValues(t, []string{"opera-house", "bondi", "sydney", "nsw", "melbourne"}, ids(fc.SortByRelevance()))
+	})
+
+	t.Run("Copies features", func(t *testing.T) {
+		sorted := fc.SortByRelevance()
+		sorted.Features[0].RawProperties["feature_type"] = "changed"
+		sorted.Features[2].Geometry.Coordinates[0] = 0
+		sorted.Features[4].RawProperties["tags"].([]interface{})[0] = "changed"
+
+		assert.EqualValues(t, "poi", fc.Features[1].RawProperties["feature_type"])
+		assert.EqualValues(t, 151.2093, fc.Features[0].Geometry.Coordinates[0])
+		assert.EqualValues(t, "vic", fc.Features[4].RawProperties["tags"].([]interface{})[0])
+		assert.EqualValues(t, "sydney", fc.Features[0].ID)
+	})
+}
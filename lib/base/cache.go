@@ -0,0 +1,184 @@
+/**
+ * go-mapbox Base Module Response Caching
+ * Provides an LRU cache of successful API responses
+ * See https://www.mapbox.com/api-documentation/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"bytes"
+	"container/list"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CacheStats are the counters of a response cache
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// responseCache is an LRU cache of successful responses with a fixed time to live
+type responseCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	order   *list.List
+	entries sync.Map
+
+	hits, misses, evictions uint64
+}
+
+// cachedResponse is a cache entry, stored as an element of the LRU list
+type cachedResponse struct {
+	key        string
+	expires    time.Time
+	statusCode int
+	status     string
+	header     http.Header
+	body       []byte
+}
+
+func newResponseCache(ttl time.Duration, maxEntries int) *responseCache {
+	return &responseCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		order:      list.New(),
+	}
+}
+
+// cacheKey generates the cache key for a request, excluding the access token
+// Permanent geocoding requests are billed and return false so they always reach the server
+func cacheKey(method, u string, v url.Values) (string, bool) {
+	if method != http.MethodGet || v.Get("permanent") == "true" || strings.Contains(u, "mapbox.places-permanent") {
+		return "", false
+	}
+
+	c := url.Values{}
+	for k, values := range v {
+		if k != "access_token" {
+			c[k] = values
+		}
+	}
+
+	return u + "?" + c.Encode(), true
+}
+
+// get returns a copy of the cached response for a key, if present and not expired
+func (c *responseCache) get(key string, req *http.Request) (*http.Response, bool) {
+	e, ok := c.entries.Load(key)
+	if !ok {
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+
+	c.mu.Lock()
+	elem := e.(*list.Element)
+	entry := elem.Value.(*cachedResponse)
+	if time.Now().After(entry.expires) {
+		c.remove(elem)
+		c.mu.Unlock()
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	c.mu.Unlock()
+
+	atomic.AddUint64(&c.hits, 1)
+
+	// Responses are copied so callers cannot modify cached data
+	body := append([]byte(nil), entry.body...)
+
+	return &http.Response{
+		Status:        entry.status,
+		StatusCode:    entry.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        entry.header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, true
+}
+
+// put stores a copy of a response body, evicting the least recently used entries over the limit
+func (c *responseCache) put(key string, resp *http.Response, body []byte) {
+	entry := &cachedResponse{
+		key:        key,
+		expires:    time.Now().Add(c.ttl),
+		statusCode: resp.StatusCode,
+		status:     resp.Status,
+		header:     resp.Header.Clone(),
+		body:       append([]byte(nil), body...),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries.Load(key); ok {
+		elem := e.(*list.Element)
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries.Store(key, c.order.PushFront(entry))
+
+	for c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+		atomic.AddUint64(&c.evictions, 1)
+	}
+}
+
+// remove deletes an element from the cache, the caller must hold the lock
+func (c *responseCache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	c.entries.Delete(elem.Value.(*cachedResponse).key)
+}
+
+// clear removes all entries from the cache
+func (c *responseCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for elem := c.order.Front(); elem != nil; elem = c.order.Front() {
+		c.remove(elem)
+	}
+}
+
+// stats returns the current cache counters
+func (c *responseCache) stats() CacheStats {
+	return CacheStats{
+		Hits:      atomic.LoadUint64(&c.hits),
+		Misses:    atomic.LoadUint64(&c.misses),
+		Evictions: atomic.LoadUint64(&c.evictions),
+	}
+}
+
+// CacheClear removes all cached responses
+func (b *Base) CacheClear() {
+	if b.cache != nil {
+		b.cache.clear()
+	}
+}
+
+// CacheStats returns the hit, miss and eviction counts of the response cache
+// The counts are zero where caching is not enabled
+func (b *Base) CacheStats() CacheStats {
+	if b.cache == nil {
+		return CacheStats{}
+	}
+	return b.cache.stats()
+}
@@ -0,0 +1,134 @@
+/**
+ * go-mapbox Base Module Circuit Breaker
+ * Fails requests fast while the API is unavailable
+ * See https://www.mapbox.com/api-documentation/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting the API while the circuit breaker is open
+var ErrCircuitOpen = errors.New("Mapbox API circuit breaker is open")
+
+// CircuitBreakerState is the state of a circuit breaker
+type CircuitBreakerState int
+
+const (
+	// Closed allows all requests
+	Closed CircuitBreakerState = iota
+	// Open rejects all requests until the reset timeout has elapsed
+	Open
+	// HalfOpen allows a single probe request to determine whether to close the circuit
+	HalfOpen
+)
+
+func (s CircuitBreakerState) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// circuitBreaker opens after a number of consecutive failures, probing the API once the reset timeout has elapsed
+// Each state change starts a new generation, and only outcomes of requests allowed in the current generation
+// are recorded, so requests sent before the circuit opened cannot stand in for the probe
+type circuitBreaker struct {
+	threshold    int
+	resetTimeout time.Duration
+
+	mu         sync.Mutex
+	state      CircuitBreakerState
+	generation uint64
+	failures   int
+	openedAt   time.Time
+	probing    bool
+}
+
+// setState transitions the breaker to a new state and generation
+func (c *circuitBreaker) setState(state CircuitBreakerState) {
+	c.state = state
+	c.generation++
+	c.failures = 0
+	c.probing = false
+	if state == Open {
+		c.openedAt = time.Now()
+	}
+}
+
+// allow checks whether a request may be sent, transitioning to half open once the reset timeout has elapsed
+// The returned generation must be passed to record with the outcome of the request
+func (c *circuitBreaker) allow() (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.state {
+	case Open:
+		if time.Since(c.openedAt) < c.resetTimeout {
+			return 0, ErrCircuitOpen
+		}
+		c.setState(HalfOpen)
+	case HalfOpen:
+		if c.probing {
+			return 0, ErrCircuitOpen
+		}
+	default:
+		return c.generation, nil
+	}
+
+	c.probing = true
+	return c.generation, nil
+}
+
+// record updates the breaker with the outcome of a request allowed in the provided generation
+// Server errors and network failures count as failures, cancelled requests are ignored
+func (c *circuitBreaker) record(generation uint64, statusCode int, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Outcomes from earlier generations are stale
+	if generation != c.generation {
+		return
+	}
+
+	switch {
+	case err != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)):
+		c.probing = false
+	case err != nil || statusCode >= http.StatusInternalServerError:
+		c.failures++
+		if c.state == HalfOpen || c.failures >= c.threshold {
+			c.setState(Open)
+		}
+	case c.state == HalfOpen:
+		c.setState(Closed)
+	default:
+		c.failures = 0
+	}
+}
+
+// CircuitState returns the state of the circuit breaker, which is always Closed where no breaker is configured
+func (b *Base) CircuitState() CircuitBreakerState {
+	if b.breaker == nil {
+		return Closed
+	}
+
+	b.breaker.mu.Lock()
+	defer b.breaker.mu.Unlock()
+
+	return b.breaker.state
+}
@@ -0,0 +1,166 @@
+/**
+ * go-mapbox Base Module Feature Collections
+ * Provides filtering and sorting of feature collections
+ * See https://www.mapbox.com/api-documentation/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"math"
+	"sort"
+)
+
+// FilterByType returns a copy of the collection containing only features of the provided type
+// The type is read from the feature_type property, falling back to the first place type
+func (fc *FeatureCollection) FilterByType(featureType string) *FeatureCollection {
+	return fc.filter(func(f *Feature) bool {
+		return f.featureType() == featureType
+	})
+}
+
+// FilterByBBox returns a copy of the collection containing only features located within the bounding box
+// Features without a point location are excluded
+func (fc *FeatureCollection) FilterByBBox(bb BoundingBox) *FeatureCollection {
+	return fc.filter(func(f *Feature) bool {
+		loc, ok := f.location()
+		return ok && bb.Contains(loc)
+	})
+}
+
+// SortByDistance returns a copy of the collection with features in ascending distance from the origin
+// Features without a point location are placed last
+func (fc *FeatureCollection) SortByDistance(origin Location) *FeatureCollection {
+	c := fc.filter(nil)
+
+	distances := make([]float64, len(c.Features))
+	for i := range c.Features {
+		distances[i] = math.Inf(1)
+		if loc, ok := c.Features[i].location(); ok {
+			distances[i] = HaversineDistance(origin, loc)
+		}
+	}
+
+	sort.Stable(byDistance{c.Features, distances})
+
+	return c
+}
+
+// byDistance sorts features by their corresponding distances
+type byDistance struct {
+	features  []Feature
+	distances []float64
+}
+
+func (s byDistance) Len() int           { return len(s.features) }
+func (s byDistance) Less(i, j int) bool { return s.distances[i] < s.distances[j] }
+func (s byDistance) Swap(i, j int) {
+	s.features[i], s.features[j] = s.features[j], s.features[i]
+	s.distances[i], s.distances[j] = s.distances[j], s.distances[i]
+}
+
+// SortByRelevance returns a copy of the collection with features in descending relevance
+// The relevance property is used where present, falling back to the v5 relevance field
+func (fc *FeatureCollection) SortByRelevance() *FeatureCollection {
+	c := fc.filter(nil)
+
+	sort.SliceStable(c.Features, func(i, j int) bool {
+		return c.Features[i].relevance() > c.Features[j].relevance()
+	})
+
+	return c
+}
+
+// filter returns a deep copy of the collection containing the features matching fn (or all features where fn is nil)
+func (fc *FeatureCollection) filter(fn func(f *Feature) bool) *FeatureCollection {
+	c := &FeatureCollection{Features: make([]Feature, 0)}
+	if fc == nil {
+		return c
+	}
+
+	c.Type, c.Attribution = fc.Type, fc.Attribution
+	for i := range fc.Features {
+		if fn == nil || fn(&fc.Features[i]) {
+			c.Features = append(c.Features, fc.Features[i].copy())
+		}
+	}
+
+	return c
+}
+
+// featureType returns the feature_type property, falling back to the first place type
+func (f *Feature) featureType() string {
+	if t, ok := f.RawProperties["feature_type"].(string); ok && t != "" {
+		return t
+	}
+	if len(f.PlaceType) > 0 {
+		return f.PlaceType[0]
+	}
+	return ""
+}
+
+// relevance returns the relevance property, falling back to the relevance field
+func (f *Feature) relevance() float64 {
+	if r, ok := f.RawProperties["relevance"].(float64); ok {
+		return r
+	}
+	return f.Relevance
+}
+
+// location returns the location of a point feature, falling back to the feature center
+func (f *Feature) location() (Location, bool) {
+	if p, err := f.Geometry.AsPoint(); err == nil {
+		return p.Location(), true
+	}
+	if len(f.Center) >= 2 {
+		return Location{Latitude: f.Center[1], Longitude: f.Center[0]}, true
+	}
+	return Location{}, false
+}
+
+// copy returns a deep copy of the feature
+func (f Feature) copy() Feature {
+	c := f
+
+	c.PlaceType = append([]string(nil), f.PlaceType...)
+	c.BBox = append(BoundingBox(nil), f.BBox...)
+	c.Center = append([]float64(nil), f.Center...)
+	c.Context = append([]Context(nil), f.Context...)
+	c.Geometry.Coordinates = append([]float64(nil), f.Geometry.Coordinates...)
+	c.Geometry.RawCoordinates = append([]byte(nil), f.Geometry.RawCoordinates...)
+	if f.RoutablePoints != nil {
+		points := RoutablePoints{Points: make([]RoutablePoint, len(f.RoutablePoints.Points))}
+		for i, p := range f.RoutablePoints.Points {
+			points.Points[i] = RoutablePoint{Name: p.Name, Coordinates: append([]float64(nil), p.Coordinates...)}
+		}
+		c.RoutablePoints = &points
+	}
+	if f.RawProperties != nil {
+		c.RawProperties = copyValue(f.RawProperties).(map[string]interface{})
+	}
+
+	return c
+}
+
+// copyValue deep copies a decoded JSON value
+func copyValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			m[k] = copyValue(e)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i, e := range t {
+			s[i] = copyValue(e)
+		}
+		return s
+	default:
+		return v
+	}
+}
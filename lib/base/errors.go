@@ -10,7 +10,10 @@
 package base
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
 )
 
 // ErrorAPIUnauthorized indicates authorization failed
@@ -18,3 +21,82 @@ var ErrorAPIUnauthorized = errors.New("Mapbox API error unauthorized")
 
 // ErrorAPILimitExceeded indicates the API limit has been exceeded
 var ErrorAPILimitExceeded = errors.New("Mapbox API error api rate limit exceeded")
+
+// APIError is returned when the Mapbox API responds with an error status code
+// The sentinel errors above can still be matched using errors.Is
+type APIError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// Message is the message field of the JSON error body, if available
+	Message string
+	// Body is the raw response body
+	Body []byte
+}
+
+// newAPIError creates an APIError from a response status and body
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiMessage := MapboxApiMessage{}
+	json.Unmarshal(body, &apiMessage)
+
+	return &APIError{
+		StatusCode: statusCode,
+		Message:    apiMessage.Message,
+		Body:       body,
+	}
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("Mapbox API error %d (%s)", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("Mapbox API error %d (%s)", e.StatusCode, e.Message)
+}
+
+// Unwrap returns the sentinel error associated with the status code (if any)
+func (e *APIError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusUnauthorized:
+		return ErrorAPIUnauthorized
+	case statusRateLimitExceeded:
+		return ErrorAPILimitExceeded
+	}
+	return nil
+}
+
+// ValidationError is returned when request options are rejected before a request is made
+type ValidationError struct {
+	// Field is the name of the invalid option
+	Field string
+	// Message describes why the option is invalid
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("Invalid %s (%s)", e.Field, e.Message)
+}
+
+// ScopeError is returned when the API rejects a request as unauthorized
+// Many APIs require a secret token with the scope for the operation
+type ScopeError struct {
+	*APIError
+	// Scope is the token scope required by the request
+	Scope string
+}
+
+func (e *ScopeError) Error() string {
+	return fmt.Sprintf("Mapbox API error %d, token requires the %s scope (%s)", e.StatusCode, e.Scope, e.Message)
+}
+
+// Unwrap returns the underlying APIError
+func (e *ScopeError) Unwrap() error {
+	return e.APIError
+}
+
+// WrapScopeError converts unauthorized and forbidden APIErrors into ScopeErrors for the required scope
+func WrapScopeError(err error, scope string) error {
+	apiErr := &APIError{}
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+		return &ScopeError{APIError: apiErr, Scope: scope}
+	}
+	return err
+}
@@ -0,0 +1,148 @@
+/**
+ * go-mapbox Base Module Geodesy
+ * Provides distance, bearing and midpoint calculations between locations
+ * See https://www.movable-type.co.uk/scripts/latlong.html for formula information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"errors"
+	"math"
+)
+
+// EarthRadius is the mean radius of the earth in meters
+const EarthRadius = 6371008.8
+
+// WGS84 ellipsoid parameters used by VincentyDistance
+const (
+	wgs84A = 6378137.0
+	wgs84F = 1 / 298.257223563
+	wgs84B = wgs84A * (1 - wgs84F)
+)
+
+// ErrVincentyNoConvergence is returned by VincentyDistance for (nearly) antipodal points
+var ErrVincentyNoConvergence = errors.New("Vincenty formula failed to converge")
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func toDegrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+// HaversineDistance returns the great-circle distance between two locations in meters
+func HaversineDistance(a, b Location) float64 {
+	lat1, lat2 := toRadians(a.Latitude), toRadians(b.Latitude)
+	dLat := lat2 - lat1
+	dLng := toRadians(b.Longitude - a.Longitude)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * EarthRadius * math.Atan2(math.Sqrt(h), math.Sqrt(1-h))
+}
+
+// VincentyDistance returns the distance between two locations on the WGS84 ellipsoid in meters
+// ErrVincentyNoConvergence is returned for antipodal points, where HaversineDistance should be used instead
+func VincentyDistance(a, b Location) (float64, error) {
+	if a.Latitude == b.Latitude && normalizeLongitude(a.Longitude) == normalizeLongitude(b.Longitude) {
+		return 0, nil
+	}
+
+	L := toRadians(normalizeLongitude(b.Longitude - a.Longitude))
+	U1 := math.Atan((1 - wgs84F) * math.Tan(toRadians(a.Latitude)))
+	U2 := math.Atan((1 - wgs84F) * math.Tan(toRadians(b.Latitude)))
+	sinU1, cosU1 := math.Sin(U1), math.Cos(U1)
+	sinU2, cosU2 := math.Sin(U2), math.Cos(U2)
+
+	lambda := L
+	var sinSigma, cosSigma, sigma, cosSqAlpha, cos2SigmaM float64
+
+	for i := 0; ; i++ {
+		if i >= 200 {
+			return 0, ErrVincentyNoConvergence
+		}
+
+		sinLambda, cosLambda := math.Sin(lambda), math.Cos(lambda)
+		sinSigma = math.Sqrt((cosU2*sinLambda)*(cosU2*sinLambda) +
+			(cosU1*sinU2-sinU1*cosU2*cosLambda)*(cosU1*sinU2-sinU1*cosU2*cosLambda))
+		if sinSigma == 0 {
+			// Distinct points with no separation on the auxiliary sphere are antipodal
+			return 0, ErrVincentyNoConvergence
+		}
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha = 1 - sinAlpha*sinAlpha
+		cos2SigmaM = 0
+		if cosSqAlpha != 0 {
+			// Equatorial lines have cosSqAlpha = 0
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		}
+
+		C := wgs84F / 16 * cosSqAlpha * (4 + wgs84F*(4-3*cosSqAlpha))
+		prev := lambda
+		lambda = L + (1-C)*wgs84F*sinAlpha*(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+
+		if math.Abs(lambda-prev) < 1e-12 {
+			break
+		}
+		if math.Abs(lambda) > math.Pi {
+			return 0, ErrVincentyNoConvergence
+		}
+	}
+
+	uSq := cosSqAlpha * (wgs84A*wgs84A - wgs84B*wgs84B) / (wgs84B * wgs84B)
+	A := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	B := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+	deltaSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+		B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+
+	return wgs84B * A * (sigma - deltaSigma), nil
+}
+
+// InitialBearing returns the initial great-circle bearing from a to b in degrees [0, 360)
+// Identical locations have a bearing of 0
+func InitialBearing(a, b Location) float64 {
+	if a == b {
+		return 0
+	}
+
+	lat1, lat2 := toRadians(a.Latitude), toRadians(b.Latitude)
+	dLng := toRadians(b.Longitude - a.Longitude)
+
+	y := math.Sin(dLng) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLng)
+
+	return math.Mod(toDegrees(math.Atan2(y, x))+360, 360)
+}
+
+// FinalBearing returns the great-circle bearing on arrival at b from a in degrees [0, 360)
+// Identical locations have a bearing of 0
+func FinalBearing(a, b Location) float64 {
+	if a == b {
+		return 0
+	}
+
+	return math.Mod(InitialBearing(b, a)+180, 360)
+}
+
+// Midpoint returns the point halfway along the great-circle path between two locations
+func Midpoint(a, b Location) Location {
+	lat1, lat2 := toRadians(a.Latitude), toRadians(b.Latitude)
+	lng1 := toRadians(a.Longitude)
+	dLng := toRadians(b.Longitude - a.Longitude)
+
+	bx := math.Cos(lat2) * math.Cos(dLng)
+	by := math.Cos(lat2) * math.Sin(dLng)
+
+	lat := math.Atan2(math.Sin(lat1)+math.Sin(lat2), math.Sqrt((math.Cos(lat1)+bx)*(math.Cos(lat1)+bx)+by*by))
+	lng := lng1 + math.Atan2(by, math.Cos(lat1)+bx)
+
+	return Location{Latitude: toDegrees(lat), Longitude: normalizeLongitude(toDegrees(lng))}
+}
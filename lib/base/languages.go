@@ -0,0 +1,120 @@
+/**
+ * go-mapbox Base Module Languages
+ * Defines the language codes supported by Mapbox APIs
+ * See https://docs.mapbox.com/api/search/geocoding/#language-coverage for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import "strings"
+
+// LanguageCode is a BCP-47 language tag supported by Mapbox APIs
+type LanguageCode string
+
+// Language codes supported by Mapbox APIs
+const (
+	LanguageAR     LanguageCode = "ar"
+	LanguageBG     LanguageCode = "bg"
+	LanguageCA     LanguageCode = "ca"
+	LanguageCS     LanguageCode = "cs"
+	LanguageDA     LanguageCode = "da"
+	LanguageDE     LanguageCode = "de"
+	LanguageEL     LanguageCode = "el"
+	LanguageEN     LanguageCode = "en"
+	LanguageES     LanguageCode = "es"
+	LanguageFA     LanguageCode = "fa"
+	LanguageFI     LanguageCode = "fi"
+	LanguageFR     LanguageCode = "fr"
+	LanguageHE     LanguageCode = "he"
+	LanguageHU     LanguageCode = "hu"
+	LanguageID     LanguageCode = "id"
+	LanguageIS     LanguageCode = "is"
+	LanguageIT     LanguageCode = "it"
+	LanguageJA     LanguageCode = "ja"
+	LanguageKA     LanguageCode = "ka"
+	LanguageKO     LanguageCode = "ko"
+	LanguageLV     LanguageCode = "lv"
+	LanguageMN     LanguageCode = "mn"
+	LanguageNB     LanguageCode = "nb"
+	LanguageNL     LanguageCode = "nl"
+	LanguagePL     LanguageCode = "pl"
+	LanguagePT     LanguageCode = "pt"
+	LanguageRO     LanguageCode = "ro"
+	LanguageRU     LanguageCode = "ru"
+	LanguageSK     LanguageCode = "sk"
+	LanguageSL     LanguageCode = "sl"
+	LanguageSR     LanguageCode = "sr"
+	LanguageSV     LanguageCode = "sv"
+	LanguageTH     LanguageCode = "th"
+	LanguageTL     LanguageCode = "tl"
+	LanguageTR     LanguageCode = "tr"
+	LanguageUK     LanguageCode = "uk"
+	LanguageVI     LanguageCode = "vi"
+	LanguageZH     LanguageCode = "zh"
+	LanguageZHHans LanguageCode = "zh-Hans"
+	LanguageZHHant LanguageCode = "zh-Hant"
+)
+
+// languageCodes is the set of supported language codes
+var languageCodes = map[LanguageCode]bool{
+	LanguageAR:     true,
+	LanguageBG:     true,
+	LanguageCA:     true,
+	LanguageCS:     true,
+	LanguageDA:     true,
+	LanguageDE:     true,
+	LanguageEL:     true,
+	LanguageEN:     true,
+	LanguageES:     true,
+	LanguageFA:     true,
+	LanguageFI:     true,
+	LanguageFR:     true,
+	LanguageHE:     true,
+	LanguageHU:     true,
+	LanguageID:     true,
+	LanguageIS:     true,
+	LanguageIT:     true,
+	LanguageJA:     true,
+	LanguageKA:     true,
+	LanguageKO:     true,
+	LanguageLV:     true,
+	LanguageMN:     true,
+	LanguageNB:     true,
+	LanguageNL:     true,
+	LanguagePL:     true,
+	LanguagePT:     true,
+	LanguageRO:     true,
+	LanguageRU:     true,
+	LanguageSK:     true,
+	LanguageSL:     true,
+	LanguageSR:     true,
+	LanguageSV:     true,
+	LanguageTH:     true,
+	LanguageTL:     true,
+	LanguageTR:     true,
+	LanguageUK:     true,
+	LanguageVI:     true,
+	LanguageZH:     true,
+	LanguageZHHans: true,
+	LanguageZHHant: true,
+}
+
+// normaliseLanguageCode lowercases a language tag, accepting underscore separators (such as zh_Hans)
+func normaliseLanguageCode(s string) string {
+	return strings.ToLower(strings.Replace(s, "_", "-", -1))
+}
+
+// ValidLanguageCode checks whether a language tag is supported by Mapbox APIs
+// Tags are matched ignoring case, and underscore separators are accepted as the API does
+func ValidLanguageCode(s string) bool {
+	n := normaliseLanguageCode(s)
+	for l := range languageCodes {
+		if normaliseLanguageCode(string(l)) == n {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+/**
+ * go-mapbox Base Module Logging
+ * Provides request and response logging hooks for API modules
+ * See https://www.mapbox.com/api-documentation/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// RequestLogger is called with each outbound request and its body (nil where there is none)
+type RequestLogger func(req *http.Request, body []byte)
+
+// ResponseLogger is called with each response, its body and the round trip duration
+type ResponseLogger func(resp *http.Response, body []byte, duration time.Duration)
+
+// DebugHook is called after each request with the request (with the access token redacted), the response
+// and the response body. The response is nil where no response was received
+type DebugHook func(req *http.Request, resp *http.Response, body []byte)
+
+// structuredLogger logs requests, responses and request failures independently of the configured loggers
+type structuredLogger interface {
+	logRequest(req *http.Request, body []byte)
+	logResponse(resp *http.Response, body []byte, duration time.Duration)
+	logError(req *http.Request, err error)
+}
+
+// StdoutRequestLogger returns a RequestLogger that prints requests to stdout
+func StdoutRequestLogger() RequestLogger {
+	return writerRequestLogger(os.Stdout)
+}
+
+// StdoutResponseLogger returns a ResponseLogger that prints responses to stdout
+func StdoutResponseLogger() ResponseLogger {
+	return writerResponseLogger(os.Stdout)
+}
+
+// writerRequestLogger prints requests to a writer, redacting the access token
+func writerRequestLogger(w io.Writer) RequestLogger {
+	return func(req *http.Request, body []byte) {
+		fmt.Fprintf(w, "Request: %s %s\n", req.Method, redactURL(req.URL))
+		if len(body) > 0 {
+			fmt.Fprintf(w, "%s\n", body)
+		}
+	}
+}
+
+// writerResponseLogger prints responses to a writer, redacting the access token
+func writerResponseLogger(w io.Writer) ResponseLogger {
+	return func(resp *http.Response, body []byte, duration time.Duration) {
+		u := ""
+		if resp.Request != nil {
+			u = redactURL(resp.Request.URL)
+		}
+		fmt.Fprintf(w, "Response: %s %s (%d bytes, %s)\n", resp.Status, u, len(body), duration)
+	}
+}
+
+// redactURL formats a URL with the access token removed
+func redactURL(u *url.URL) string {
+	return redactedURL(u).String()
+}
+
+// redactedURL returns a copy of a URL with the access token removed
+func redactedURL(u *url.URL) *url.URL {
+	c := *u
+	v := c.Query()
+	if v.Get("access_token") != "" {
+		v.Set("access_token", "REDACTED")
+		c.RawQuery = v.Encode()
+	}
+	return &c
+}
+
+// redactRequest returns a copy of a request with the access token removed
+func redactRequest(req *http.Request) *http.Request {
+	c := req.Clone(req.Context())
+	c.URL = redactedURL(req.URL)
+	return c
+}
@@ -0,0 +1,115 @@
+//go:build go1.21
+// +build go1.21
+
+/**
+ * go-mapbox Base Module Structured Logging
+ * Provides log/slog request and response logging for API modules
+ * See https://www.mapbox.com/api-documentation/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// WithSlogLogger logs each request and response as structured records
+// Requests are logged at Debug, successful responses at Info, client errors at Warn,
+// and server errors or network failures at Error. Access tokens are redacted to tok_****last4.
+// Records are logged in addition to any request or response loggers, regardless of option order.
+func WithSlogLogger(logger *slog.Logger) Option {
+	return func(b *Base) error {
+		if logger == nil {
+			return nil
+		}
+		b.logger = &slogLogger{logger: logger}
+		return nil
+	}
+}
+
+// slogLogger logs requests and responses to a slog.Logger
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func (l *slogLogger) logRequest(req *http.Request, body []byte) {
+	l.logger.LogAttrs(req.Context(), slog.LevelDebug, "Mapbox request",
+		slog.String("method", req.Method),
+		slog.String("url", (&url.URL{Scheme: req.URL.Scheme, Host: req.URL.Host, Path: req.URL.Path}).String()),
+		slog.String("query", redactTokenQuery(req.URL.Query())),
+		slog.Int("body_bytes", len(body)),
+	)
+}
+
+func (l *slogLogger) logResponse(resp *http.Response, body []byte, duration time.Duration) {
+	level := slog.LevelInfo
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError:
+		level = slog.LevelError
+	case resp.StatusCode >= http.StatusBadRequest:
+		level = slog.LevelWarn
+	}
+
+	ctx, method, u := context.Background(), "", ""
+	if resp.Request != nil {
+		ctx, method, u = resp.Request.Context(), resp.Request.Method, redactTokenURL(resp.Request.URL)
+	}
+
+	l.logger.LogAttrs(ctx, level, "Mapbox response",
+		slog.String("method", method),
+		slog.String("url", u),
+		slog.Int("status", resp.StatusCode),
+		slog.Duration("duration", duration),
+		slog.Int("body_bytes", len(body)),
+		slog.String("request_id", resp.Header.Get("X-Request-Id")),
+	)
+}
+
+func (l *slogLogger) logError(req *http.Request, err error) {
+	l.logger.LogAttrs(req.Context(), slog.LevelError, "Mapbox request failed",
+		slog.String("method", req.Method),
+		slog.String("url", redactTokenURL(req.URL)),
+		// Transport errors include the request URL, so the token is redacted from the message
+		slog.String("error", redactTokenError(req.URL, err)),
+	)
+}
+
+// redactToken masks an access token, retaining only the last four characters
+func redactToken(token string) string {
+	if len(token) <= 4 {
+		return "tok_****"
+	}
+	return "tok_****" + token[len(token)-4:]
+}
+
+// redactTokenQuery encodes query parameters with the access token masked
+func redactTokenQuery(v url.Values) string {
+	if token := v.Get("access_token"); token != "" {
+		v.Set("access_token", redactToken(token))
+	}
+	return v.Encode()
+}
+
+// redactTokenURL formats a URL with the access token masked
+func redactTokenURL(u *url.URL) string {
+	c := *u
+	c.RawQuery = redactTokenQuery(c.Query())
+	return c.String()
+}
+
+// redactTokenError formats a request error with any occurrence of the access token masked
+func redactTokenError(u *url.URL, err error) string {
+	msg := err.Error()
+	if token := u.Query().Get("access_token"); token != "" {
+		msg = strings.ReplaceAll(msg, token, redactToken(token))
+	}
+	return msg
+}
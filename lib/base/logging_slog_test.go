@@ -0,0 +1,119 @@
+//go:build go1.21
+// +build go1.21
+
+/**
+ * go-mapbox Base Module Structured Logging Tests
+ * Provides log/slog request and response logging for API modules
+ * See https://www.mapbox.com/api-documentation/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogLogger(t *testing.T) {
+	const token = "sk.secret-token-abcd"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.Write([]byte(`{}`))
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Not Found"}`))
+		case "/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	buf := bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	b, err := NewBase(token, WithBaseURL(server.URL), WithSlogLogger(logger))
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	tests := []struct {
+		path    string
+		level   string
+		failure bool
+	}{
+		{"ok", "level=INFO", false},
+		{"missing", "level=WARN", true},
+		{"broken", "level=ERROR", true},
+	}
+
+	for _, tt := range tests {
+		t.Run("Logs "+tt.path+" responses", func(t *testing.T) {
+			buf.Reset()
+
+			err := b.QueryBaseContext(context.Background(), tt.path, &url.Values{"limit": {"1"}}, nil)
+			assert.Equal(t, tt.failure, err != nil)
+
+			out := buf.String()
+			assert.Contains(t, out, "level=DEBUG msg=\"Mapbox request\" method=GET url="+server.URL+"/"+tt.path)
+			assert.Contains(t, out, "query=\"access_token=tok_%2A%2A%2A%2Aabcd&limit=1\"")
+			assert.Contains(t, out, tt.level+" msg=\"Mapbox response\"")
+			assert.NotContains(t, out, token)
+		})
+	}
+
+	t.Run("Logs network failures", func(t *testing.T) {
+		buf.Reset()
+
+		unreachable, err := NewBase(token, WithBaseURL("http://127.0.0.1:1"), WithSlogLogger(logger))
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		err = unreachable.QueryBaseContext(context.Background(), "ok", &url.Values{}, nil)
+		assert.NotNil(t, err)
+
+		out := buf.String()
+		assert.Contains(t, out, "level=ERROR msg=\"Mapbox request failed\"")
+		assert.Contains(t, out, "tok_****abcd")
+		assert.NotContains(t, out, token)
+	})
+
+	t.Run("Logs alongside request and response loggers in any order", func(t *testing.T) {
+		var requests, responses int
+		requestLogger := WithRequestLogger(func(req *http.Request, body []byte) { requests++ })
+		responseLogger := WithResponseLogger(func(resp *http.Response, body []byte, duration time.Duration) { responses++ })
+
+		orders := [][]Option{
+			{WithSlogLogger(logger), requestLogger, responseLogger},
+			{requestLogger, responseLogger, WithSlogLogger(logger)},
+		}
+
+		for i, opts := range orders {
+			buf.Reset()
+
+			ordered, err := NewBase(token, append([]Option{WithBaseURL(server.URL)}, opts...)...)
+			if !assert.Nil(t, err) {
+				t.FailNow()
+			}
+
+			assert.Nil(t, ordered.QueryBaseContext(context.Background(), "ok", &url.Values{}, nil))
+			assert.EqualValues(t, i+1, requests)
+			assert.EqualValues(t, i+1, responses)
+			assert.Contains(t, buf.String(), "msg=\"Mapbox request\"")
+			assert.Contains(t, buf.String(), "msg=\"Mapbox response\"")
+		}
+	})
+}
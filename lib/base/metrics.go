@@ -0,0 +1,51 @@
+/**
+ * go-mapbox Base Module Metrics
+ * Provides request metrics hooks for API modules
+ * See https://www.mapbox.com/api-documentation/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// MetricsRecorder records the outcome of each API request
+// The status code is zero where no response was received
+type MetricsRecorder interface {
+	RecordRequest(method, path string, statusCode int, duration time.Duration, reqBytes, respBytes int64)
+}
+
+// NoopMetricsRecorder discards request metrics, and is used by default
+type NoopMetricsRecorder struct{}
+
+// RecordRequest does nothing
+func (NoopMetricsRecorder) RecordRequest(method, path string, statusCode int, duration time.Duration, reqBytes, respBytes int64) {
+}
+
+// meteredBody counts the bytes read from a response body, calling record once the body is closed
+type meteredBody struct {
+	io.ReadCloser
+	count  int64
+	once   sync.Once
+	record func(respBytes int64)
+}
+
+func (m *meteredBody) Read(p []byte) (int, error) {
+	n, err := m.ReadCloser.Read(p)
+	m.count += int64(n)
+	return n, err
+}
+
+func (m *meteredBody) Close() error {
+	err := m.ReadCloser.Close()
+	m.once.Do(func() {
+		m.record(m.count)
+	})
+	return err
+}
@@ -0,0 +1,62 @@
+//go:build prometheus
+// +build prometheus
+
+/**
+ * go-mapbox Base Module Prometheus Metrics
+ * Provides a prometheus MetricsRecorder, built with the prometheus build tag
+ * See https://www.mapbox.com/api-documentation/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// prometheusMetricsRecorder records request metrics to prometheus collectors
+type prometheusMetricsRecorder struct {
+	duration      *prometheus.HistogramVec
+	requests      *prometheus.CounterVec
+	responseBytes *prometheus.CounterVec
+}
+
+// PrometheusMetricsRecorder creates a MetricsRecorder registering request metrics with the provided registerer
+// (or the default registerer where nil). Paths are not used as labels, as they contain request parameters
+func PrometheusMetricsRecorder(reg prometheus.Registerer) MetricsRecorder {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	r := &prometheusMetricsRecorder{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "mapbox_request_duration_seconds",
+			Help:    "Duration of Mapbox API requests",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method"}),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mapbox_requests_total",
+			Help: "Number of Mapbox API requests by response status",
+		}, []string{"method", "status"}),
+		responseBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mapbox_response_bytes_total",
+			Help: "Size of Mapbox API response bodies",
+		}, []string{"method"}),
+	}
+
+	reg.MustRegister(r.duration, r.requests, r.responseBytes)
+
+	return r
+}
+
+// RecordRequest records the duration, status and response size of a request
+func (r *prometheusMetricsRecorder) RecordRequest(method, path string, statusCode int, duration time.Duration, reqBytes, respBytes int64) {
+	r.duration.WithLabelValues(method).Observe(duration.Seconds())
+	r.requests.WithLabelValues(method, strconv.Itoa(statusCode)).Inc()
+	r.responseBytes.WithLabelValues(method).Add(float64(respBytes))
+}
@@ -0,0 +1,58 @@
+//go:build prometheus
+// +build prometheus
+
+/**
+ * go-mapbox Base Module Prometheus Metrics Tests
+ * Provides a prometheus MetricsRecorder, built with the prometheus build tag
+ * See https://www.mapbox.com/api-documentation/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusMetricsRecorder(t *testing.T) {
+	body := `{"type":"FeatureCollection","features":[]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	reg := prometheus.NewRegistry()
+	recorder := PrometheusMetricsRecorder(reg)
+
+	b, err := NewBase("test-token", WithBaseURL(server.URL), WithMetricsRecorder(recorder))
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	var resp map[string]interface{}
+	assert.Nil(t, b.QueryBase("ok", &url.Values{}, &resp))
+	assert.Nil(t, b.QueryBase("ok", &url.Values{}, &resp))
+	assert.NotNil(t, b.QueryBase("missing", &url.Values{}, &resp))
+
+	r := recorder.(*prometheusMetricsRecorder)
+	assert.EqualValues(t, 2, testutil.ToFloat64(r.requests.WithLabelValues(http.MethodGet, "200")))
+	assert.EqualValues(t, 1, testutil.ToFloat64(r.requests.WithLabelValues(http.MethodGet, "404")))
+	assert.EqualValues(t, 2*len(body), testutil.ToFloat64(r.responseBytes.WithLabelValues(http.MethodGet)))
+	assert.EqualValues(t, 1, testutil.CollectAndCount(r.duration))
+
+	// Recorders register their collectors once
+	assert.Panics(t, func() { PrometheusMetricsRecorder(reg) })
+}
@@ -0,0 +1,131 @@
+/**
+ * go-mapbox Base Module Options
+ * Provides configuration options for the API base
+ * See https://www.mapbox.com/api-documentation/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Option configures a Base instance on creation
+type Option func(b *Base) error
+
+// WithBaseURL overrides the Mapbox API base URL, for example for enterprise deployments or proxies
+// Query paths are resolved relative to the provided URL, so any path prefix is preserved
+// The URL must use https, except for localhost where http is permitted for test servers
+func WithBaseURL(baseURL string) Option {
+	return func(b *Base) error {
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return fmt.Errorf("Invalid base URL (%s)", err)
+		}
+		if u.Host == "" {
+			return fmt.Errorf("Invalid base URL (no host specified)")
+		}
+		if u.Scheme != "https" && !(u.Scheme == "http" && isLocalhost(u.Hostname())) {
+			return fmt.Errorf("Invalid base URL scheme (%s), https is required for hosts other than localhost", u.Scheme)
+		}
+
+		b.baseURL = strings.TrimRight(u.String(), "/")
+
+		return nil
+	}
+}
+
+// isLocalhost checks whether a host refers to the local machine, where plain http is permitted for testing
+func isLocalhost(host string) bool {
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
+
+// WithRequestLogger sets a function called with each outbound request and its body before it is sent
+func WithRequestLogger(fn RequestLogger) Option {
+	return func(b *Base) error {
+		b.requestLogger = fn
+		return nil
+	}
+}
+
+// WithResponseLogger sets a function called with each response, its body and the round trip duration
+// The response body remains available for decoding after the logger is called
+func WithResponseLogger(fn ResponseLogger) Option {
+	return func(b *Base) error {
+		b.responseLogger = fn
+		return nil
+	}
+}
+
+// WithCache caches successful GET responses for the provided duration, evicting the least
+// recently used responses beyond maxEntries. Permanent geocoding requests are never cached.
+func WithCache(ttl time.Duration, maxEntries int) Option {
+	return func(b *Base) error {
+		if ttl <= 0 || maxEntries <= 0 {
+			return fmt.Errorf("Cache requires a positive ttl and maximum entries (received %s, %d)", ttl, maxEntries)
+		}
+		b.cache = newResponseCache(ttl, maxEntries)
+		return nil
+	}
+}
+
+// WithCircuitBreaker fails requests with ErrCircuitOpen after threshold consecutive server errors
+// or network failures. Once resetTimeout has elapsed a single probe request is sent, closing the
+// circuit on success or reopening it on failure. Clones share the circuit breaker.
+func WithCircuitBreaker(threshold int, resetTimeout time.Duration) Option {
+	return func(b *Base) error {
+		if threshold <= 0 || resetTimeout <= 0 {
+			return fmt.Errorf("Circuit breaker requires a positive threshold and reset timeout (received %d, %s)", threshold, resetTimeout)
+		}
+		b.breaker = &circuitBreaker{threshold: threshold, resetTimeout: resetTimeout}
+		return nil
+	}
+}
+
+// WithMaxConcurrency limits the number of requests in flight to n, with further requests waiting
+// for a slot or for their context to be cancelled. Slots are held until the request returns,
+// and clones share the limit.
+func WithMaxConcurrency(n int) Option {
+	return func(b *Base) error {
+		if n <= 0 {
+			return fmt.Errorf("Maximum concurrency must be positive (received %d)", n)
+		}
+		b.semaphore = make(chan struct{}, n)
+		return nil
+	}
+}
+
+// WithDebug sets a hook called after each request with the request, response and buffered response body
+// The access token is redacted from the request, and the response body remains available for decoding
+func WithDebug(fn DebugHook) Option {
+	return func(b *Base) error {
+		b.debugHook = fn
+		return nil
+	}
+}
+
+// WithMetricsRecorder sets a recorder called with the method, path, status, duration and sizes of each request
+func WithMetricsRecorder(r MetricsRecorder) Option {
+	return func(b *Base) error {
+		if r == nil {
+			r = NoopMetricsRecorder{}
+		}
+		b.metrics = r
+		return nil
+	}
+}
+
+// WithTransport sets the HTTP transport used for requests, defaulting to http.DefaultTransport
+func WithTransport(rt http.RoundTripper) Option {
+	return func(b *Base) error {
+		b.transport = rt
+		return nil
+	}
+}
@@ -0,0 +1,104 @@
+/**
+ * go-mapbox Base Module Pagination
+ * Provides cursor based pagination for list endpoints
+ * See https://docs.mapbox.com/api/overview/#pagination for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Paginator reads the pagination cursor from a list response
+type Paginator struct {
+	link string
+}
+
+// NewPaginator creates a paginator from the Link header of a response
+func NewPaginator(resp *http.Response) *Paginator {
+	return &Paginator{link: resp.Header.Get("Link")}
+}
+
+// Next returns the start cursor of the following page, and whether there is one
+func (p *Paginator) Next() (string, bool) {
+	cursor := NextCursor(p.link)
+	return cursor, cursor != ""
+}
+
+// PageFunc is called with the body of each page of a paginated listing
+// Returning an error stops pagination
+type PageFunc func(body []byte) error
+
+// QueryPaginated queries a list endpoint, calling fn with each page until there are no more pages
+// The start argument is set from the Link header of each response, and the context is checked between pages
+func (b *Base) QueryPaginated(ctx context.Context, query string, v *url.Values, fn PageFunc) error {
+	args := url.Values{}
+	for k, values := range *v {
+		args[k] = append([]string(nil), values...)
+	}
+
+	for {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		resp, err := b.QueryRequestContext(ctx, query, &args)
+		if err != nil {
+			return err
+		}
+
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		err = fn(body)
+		if err != nil {
+			return err
+		}
+
+		cursor, more := NewPaginator(resp).Next()
+		if !more {
+			return nil
+		}
+		args.Set("start", cursor)
+	}
+}
+
+// NextCursor extracts the start cursor from the next link of a Link header (if present), for paginated listings
+func NextCursor(header string) string {
+	for _, link := range strings.Split(header, ",") {
+		parts := strings.Split(link, ";")
+		if len(parts) < 2 {
+			continue
+		}
+
+		isNext := false
+		for _, p := range parts[1:] {
+			if strings.TrimSpace(p) == `rel="next"` {
+				isNext = true
+			}
+		}
+		if !isNext {
+			continue
+		}
+
+		u, err := url.Parse(strings.Trim(strings.TrimSpace(parts[0]), "<>"))
+		if err != nil {
+			return ""
+		}
+		return u.Query().Get("start")
+	}
+
+	return ""
+}
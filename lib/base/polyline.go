@@ -0,0 +1,109 @@
+/**
+ * go-mapbox Base Module Polylines
+ * Provides encoding and decoding of polylines for API modules
+ * See https://developers.google.com/maps/documentation/utilities/polylinealgorithm for format information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// validatePolylinePrecision checks the precision is 5 (the "polyline" geometry type) or 6 (the "polyline6" geometry type)
+func validatePolylinePrecision(precision int) error {
+	if precision != 5 && precision != 6 {
+		return &ValidationError{Field: "precision", Message: fmt.Sprintf("%d is not 5 or 6", precision)}
+	}
+	return nil
+}
+
+// DecodePolyline decodes an encoded polyline with the provided precision (5 or 6) into a list of locations
+// Empty polylines decode to an empty list of locations
+func DecodePolyline(encoded string, precision int) ([]Location, error) {
+	if err := validatePolylinePrecision(precision); err != nil {
+		return nil, err
+	}
+
+	factor := math.Pow10(precision)
+	locations := make([]Location, 0)
+
+	var lat, lng int64
+	for i := 0; i < len(encoded); {
+		var deltas [2]int64
+		for j := range deltas {
+			var result int64
+			var shift uint
+			for {
+				if i >= len(encoded) {
+					return nil, errors.New("Polyline is truncated")
+				}
+				b := int64(encoded[i]) - 63
+				i++
+				if b < 0 || b > 0x3f {
+					return nil, errors.New("Polyline contains invalid characters")
+				}
+				result |= (b & 0x1f) << shift
+				shift += 5
+				if b < 0x20 {
+					break
+				}
+			}
+			if result&1 != 0 {
+				deltas[j] = ^(result >> 1)
+			} else {
+				deltas[j] = result >> 1
+			}
+		}
+
+		lat += deltas[0]
+		lng += deltas[1]
+		locations = append(locations, Location{Latitude: float64(lat) / factor, Longitude: float64(lng) / factor})
+	}
+
+	return locations, nil
+}
+
+// EncodePolyline encodes a list of locations as a polyline with the provided precision (5 or 6)
+// EncodePolyline panics with a ValidationError if the precision is not 5 or 6
+func EncodePolyline(locs []Location, precision int) string {
+	if err := validatePolylinePrecision(precision); err != nil {
+		panic(err)
+	}
+
+	factor := math.Pow10(precision)
+	var sb strings.Builder
+
+	var prevLat, prevLng int64
+	for _, l := range locs {
+		lat := int64(math.Round(l.Latitude * factor))
+		lng := int64(math.Round(l.Longitude * factor))
+
+		encodePolylineValue(&sb, lat-prevLat)
+		encodePolylineValue(&sb, lng-prevLng)
+
+		prevLat, prevLng = lat, lng
+	}
+
+	return sb.String()
+}
+
+// encodePolylineValue writes a single signed polyline value
+func encodePolylineValue(sb *strings.Builder, v int64) {
+	u := v << 1
+	if v < 0 {
+		u = ^u
+	}
+
+	for u >= 0x20 {
+		sb.WriteByte(byte((0x20 | (u & 0x1f)) + 63))
+		u >>= 5
+	}
+	sb.WriteByte(byte(u + 63))
+}
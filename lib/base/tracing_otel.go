@@ -0,0 +1,118 @@
+//go:build otel
+// +build otel
+
+/**
+ * go-mapbox Base Module OpenTelemetry Tracing
+ * Provides trace propagation for API requests, built with the otel build tag
+ * See https://www.mapbox.com/api-documentation/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/ryankurte/go-mapbox"
+
+// WithTracePropagation starts a client span for each request using the global tracer provider,
+// and injects the W3C trace context into outbound requests. The span wraps the transport set
+// with WithTransport (or http.DefaultTransport), regardless of option order, and ends once the
+// response body has been read or closed.
+func WithTracePropagation() Option {
+	return func(b *Base) error {
+		b.wrapTransport = func(next http.RoundTripper) http.RoundTripper {
+			if next == nil {
+				next = http.DefaultTransport
+			}
+			return &tracingTransport{
+				next:       next,
+				tracer:     otel.Tracer(tracerName),
+				propagator: propagation.TraceContext{},
+			}
+		}
+		return nil
+	}
+}
+
+// tracingTransport wraps a transport to trace each request
+type tracingTransport struct {
+	next       http.RoundTripper
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// RoundTrip traces a request, the access token is redacted from the recorded URL
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := t.tracer.Start(req.Context(), fmt.Sprintf("mapbox.%s.%s", req.Method, req.URL.Path),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.method", req.Method),
+			attribute.String("http.url", redactURL(req.URL)),
+			attribute.String("net.peer.name", req.URL.Hostname()),
+		),
+	)
+
+	// Requests must not be modified by transports, so the trace context is injected into a copy
+	req = req.Clone(ctx)
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+
+	resp.Body = &tracedBody{ReadCloser: resp.Body, span: span}
+
+	return resp, nil
+}
+
+// tracedBody ends a span once the response body has been read or closed
+type tracedBody struct {
+	io.ReadCloser
+	span trace.Span
+	once sync.Once
+}
+
+func (b *tracedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.end()
+	} else if err != nil {
+		b.span.RecordError(err)
+		b.end()
+	}
+	return n, err
+}
+
+func (b *tracedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.end()
+	return err
+}
+
+func (b *tracedBody) end() {
+	b.once.Do(func() {
+		b.span.End()
+	})
+}
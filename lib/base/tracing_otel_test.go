@@ -0,0 +1,127 @@
+//go:build otel
+// +build otel
+
+/**
+ * go-mapbox Base Module OpenTelemetry Tracing Tests
+ * Provides trace propagation for API requests, built with the otel build tag
+ * See https://www.mapbox.com/api-documentation/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package base
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// testTracer records the spans it starts
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	s := &testSpan{Span: trace.SpanFromContext(ctx), name: name}
+	t.spans = append(t.spans, s)
+	return trace.ContextWithSpan(ctx, s), s
+}
+
+// testSpan records whether it has ended
+type testSpan struct {
+	trace.Span
+	name  string
+	ended int
+}
+
+func (s *testSpan) End(opts ...trace.SpanEndOption) {
+	s.ended++
+}
+
+func TestTracePropagation(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	}))
+
+	t.Run("Propagates the trace context", func(t *testing.T) {
+		b, err := NewBase("test-token", WithTracePropagation(), WithBaseURL(server.URL))
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		var resp map[string]interface{}
+		err = b.QueryBaseContext(ctx, "test", &url.Values{}, &resp)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		parts := strings.Split(traceparent, "-")
+		if assert.Len(t, parts, 4) {
+			assert.EqualValues(t, "00", parts[0])
+			assert.EqualValues(t, traceID.String(), parts[1])
+			assert.EqualValues(t, "01", parts[3])
+		}
+
+		err = b.QueryBaseContext(context.Background(), "test", &url.Values{}, &resp)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "", traceparent)
+	})
+
+	t.Run("Wraps the configured transport regardless of option order", func(t *testing.T) {
+		b, err := NewBase("test-token", WithTracePropagation(), WithTransport(http.DefaultTransport))
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		tt, ok := b.transport.(*tracingTransport)
+		if assert.True(t, ok) {
+			assert.Equal(t, http.DefaultTransport, tt.next)
+		}
+	})
+
+	t.Run("Ends spans once the response body is read", func(t *testing.T) {
+		tracer := &testTracer{}
+		transport := &tracingTransport{next: http.DefaultTransport, tracer: tracer, propagator: propagation.TraceContext{}}
+
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/test?access_token=secret", nil)
+		resp, err := transport.RoundTrip(req.WithContext(ctx))
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		if !assert.Len(t, tracer.spans, 1) {
+			t.FailNow()
+		}
+		span := tracer.spans[0]
+		assert.EqualValues(t, "mapbox.GET./test", span.name)
+		assert.EqualValues(t, 0, span.ended)
+
+		_, err = ioutil.ReadAll(resp.Body)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, span.ended)
+
+		resp.Body.Close()
+		assert.EqualValues(t, 1, span.ended)
+	})
+}
@@ -9,18 +9,287 @@
 
 package base
 
-type Point []float64
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Point is a GeoJSON position in the form [lng, lat]
+type Point [2]float64
+
+// Location converts the point to a Location
+func (p Point) Location() Location {
+	return Location{Latitude: p[1], Longitude: p[0]}
+}
+
+// LineString is the coordinates of a GeoJSON LineString geometry
+type LineString []Point
+
+// Polygon is the coordinates of a GeoJSON Polygon geometry, the first ring is the exterior and others are holes
+type Polygon [][]Point
+
+// MultiPolygon is the coordinates of a GeoJSON MultiPolygon geometry
+type MultiPolygon [][][]Point
 
 type Location struct {
 	Latitude  float64 `json:"lat"`
 	Longitude float64 `json:"lng"`
 }
 
+// BoundingBox is a geographic area in the form [minLng, minLat, maxLng, maxLat]
 type BoundingBox []float64
 
+// NewBoundingBox creates a bounding box, validating that the minimums are less than the maximums
+func NewBoundingBox(minLng, minLat, maxLng, maxLat float64) (BoundingBox, error) {
+	if minLng >= maxLng {
+		return nil, fmt.Errorf("Bounding box minimum longitude (%f) must be less than maximum (%f)", minLng, maxLng)
+	}
+	if minLat >= maxLat {
+		return nil, fmt.Errorf("Bounding box minimum latitude (%f) must be less than maximum (%f)", minLat, maxLat)
+	}
+
+	return BoundingBox{minLng, minLat, maxLng, maxLat}, nil
+}
+
+// BoundingBoxFromLocations creates the smallest bounding box containing all of the locations,
+// crossing the antimeridian where that results in a smaller box
+// An empty bounding box is returned where no locations are provided
+func BoundingBoxFromLocations(locs []Location) BoundingBox {
+	if len(locs) == 0 {
+		return BoundingBox{}
+	}
+
+	minLat, maxLat := locs[0].Latitude, locs[0].Latitude
+	lngs := make([]float64, len(locs))
+	for i, l := range locs {
+		minLat = math.Min(minLat, l.Latitude)
+		maxLat = math.Max(maxLat, l.Latitude)
+		lngs[i] = l.Longitude
+	}
+	sort.Float64s(lngs)
+
+	// The box spans all longitudes except the largest gap between them
+	minLng, maxLng := lngs[0], lngs[len(lngs)-1]
+	gap := lngs[0] + 360 - lngs[len(lngs)-1]
+	for i := 1; i < len(lngs); i++ {
+		if lngs[i]-lngs[i-1] > gap {
+			gap = lngs[i] - lngs[i-1]
+			minLng, maxLng = lngs[i], lngs[i-1]
+		}
+	}
+
+	return BoundingBox{minLng, minLat, maxLng, maxLat}
+}
+
+// CrossesAntimeridian indicates whether the bounding box crosses the 180th meridian (where minLng > maxLng)
+func (b BoundingBox) CrossesAntimeridian() bool {
+	return len(b) == 4 && b[0] > b[2]
+}
+
+// lngRanges returns the longitude ranges of the bounding box, split at the antimeridian
+func (b BoundingBox) lngRanges() [][2]float64 {
+	if b.CrossesAntimeridian() {
+		return [][2]float64{{b[0], 180}, {-180, b[2]}}
+	}
+	return [][2]float64{{b[0], b[2]}}
+}
+
+// Contains checks whether a location is within (or on the edge of) the bounding box
+func (b BoundingBox) Contains(loc Location) bool {
+	if len(b) != 4 || loc.Latitude < b[1] || loc.Latitude > b[3] {
+		return false
+	}
+
+	for _, r := range b.lngRanges() {
+		if loc.Longitude >= r[0] && loc.Longitude <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// Intersects checks whether two bounding boxes overlap (or touch)
+func (b BoundingBox) Intersects(other BoundingBox) bool {
+	if len(b) != 4 || len(other) != 4 || b[1] > other[3] || other[1] > b[3] {
+		return false
+	}
+
+	for _, r := range b.lngRanges() {
+		for _, o := range other.lngRanges() {
+			if r[0] <= o[1] && o[0] <= r[1] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Center returns the center of the bounding box
+func (b BoundingBox) Center() Location {
+	if len(b) != 4 {
+		return Location{}
+	}
+
+	maxLng := b[2]
+	if b.CrossesAntimeridian() {
+		maxLng += 360
+	}
+
+	return Location{
+		Latitude:  (b[1] + b[3]) / 2,
+		Longitude: normalizeLongitude((b[0] + maxLng) / 2),
+	}
+}
+
+// Expand returns the bounding box grown by a margin (in degrees) on each side
+// Latitudes are limited to the poles, and longitudes wrap across the antimeridian
+func (b BoundingBox) Expand(margin float64) BoundingBox {
+	if len(b) != 4 {
+		return BoundingBox{}
+	}
+
+	span := b[2] - b[0]
+	if b.CrossesAntimeridian() {
+		span += 360
+	}
+
+	minLng, maxLng := normalizeLongitude(b[0]-margin), normalizeLongitude(b[2]+margin)
+	if span+2*margin >= 360 {
+		minLng, maxLng = -180, 180
+	}
+
+	return BoundingBox{minLng, math.Max(b[1]-margin, -90), maxLng, math.Min(b[3]+margin, 90)}
+}
+
+// normalizeLongitude wraps a longitude into the range [-180, 180]
+func normalizeLongitude(lng float64) float64 {
+	for lng > 180 {
+		lng -= 360
+	}
+	for lng < -180 {
+		lng += 360
+	}
+	return lng
+}
+
+// String formats the bounding box as the comma separated list used in API queries
+func (b BoundingBox) String() string {
+	values := make([]string, len(b))
+	for i, f := range b {
+		values[i] = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	return strings.Join(values, ",")
+}
+
+// EncodeValues encodes the bounding box as a single query parameter
+func (b BoundingBox) EncodeValues(key string, v *url.Values) error {
+	v.Set(key, b.String())
+	return nil
+}
+
 type Geometry struct {
-	Type        string `json:"type"`
-	Coordinates Point  `json:"coordinates"`
+	Type string `json:"type"`
+	// Coordinates contains the position of Point geometries
+	// Deprecated: use AsPoint, or the As* method for the geometry type
+	Coordinates []float64 `json:"coordinates"`
+
+	// RawCoordinates contains the undecoded coordinates for geometries of any type
+	RawCoordinates json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes a geometry, only Point coordinates are decoded into Coordinates
+// while the coordinates of all geometry types are retained in RawCoordinates
+func (g *Geometry) UnmarshalJSON(data []byte) error {
+	raw := struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}{}
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	g.Type = raw.Type
+	g.RawCoordinates = raw.Coordinates
+	g.Coordinates = nil
+
+	if raw.Type == "Point" && len(raw.Coordinates) > 0 {
+		return json.Unmarshal(raw.Coordinates, &g.Coordinates)
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes a geometry, preferring the raw coordinates (where set) over Coordinates
+func (g Geometry) MarshalJSON() ([]byte, error) {
+	var coordinates interface{} = g.Coordinates
+	if len(g.RawCoordinates) > 0 {
+		coordinates = g.RawCoordinates
+	}
+
+	return json.Marshal(struct {
+		Type        string      `json:"type"`
+		Coordinates interface{} `json:"coordinates"`
+	}{g.Type, coordinates})
+}
+
+// coordinates decodes the raw coordinates of a geometry of the expected type
+func (g *Geometry) coordinates(geometryType string, inst interface{}) error {
+	if g.Type != geometryType {
+		return fmt.Errorf("Geometry type %s is not %s", g.Type, geometryType)
+	}
+
+	raw := []byte(g.RawCoordinates)
+	if len(raw) == 0 {
+		var err error
+		raw, err = json.Marshal(g.Coordinates)
+		if err != nil {
+			return err
+		}
+	}
+
+	return json.Unmarshal(raw, inst)
+}
+
+// AsPoint returns the coordinates of a Point geometry
+func (g *Geometry) AsPoint() (Point, error) {
+	coordinates := []float64{}
+	err := g.coordinates("Point", &coordinates)
+	if err != nil {
+		return Point{}, err
+	}
+	if len(coordinates) < 2 {
+		return Point{}, fmt.Errorf("Point geometry requires two coordinates (got %d)", len(coordinates))
+	}
+
+	return Point{coordinates[0], coordinates[1]}, nil
+}
+
+// AsLineString returns the coordinates of a LineString geometry
+func (g *Geometry) AsLineString() (LineString, error) {
+	line := LineString{}
+	err := g.coordinates("LineString", &line)
+	return line, err
+}
+
+// AsPolygon returns the coordinates of a Polygon geometry
+func (g *Geometry) AsPolygon() (Polygon, error) {
+	polygon := Polygon{}
+	err := g.coordinates("Polygon", &polygon)
+	return polygon, err
+}
+
+// AsMultiPolygon returns the coordinates of a MultiPolygon geometry
+func (g *Geometry) AsMultiPolygon() (MultiPolygon, error) {
+	multi := MultiPolygon{}
+	err := g.coordinates("MultiPolygon", &multi)
+	return multi, err
 }
 
 type Context struct {
@@ -47,9 +316,91 @@ type Feature struct {
 	Relevance  float64     `json:"relevance"`
 	Properties Properties  `json:"properties"`
 	BBox       BoundingBox `json:"bbox"`
-	Center     Point       `json:"center"`
+	Center     []float64   `json:"center"`
 	Geometry   Geometry    `json:"geometry"`
 	Context    []Context   `json:"context"`
+	// RoutablePoints are returned by v5 geocoding where routing is requested
+	RoutablePoints *RoutablePoints `json:"routable_points,omitempty"`
+
+	// RawProperties contains the untyped properties object as returned by the API
+	RawProperties map[string]interface{} `json:"-"`
+}
+
+// UnmarshalJSON decodes a feature, retaining the raw properties alongside the typed Properties
+func (f *Feature) UnmarshalJSON(data []byte) error {
+	type feature Feature
+	withID := struct {
+		*feature
+		ID json.RawMessage `json:"id"`
+	}{feature: (*feature)(f)}
+	err := json.Unmarshal(data, &withID)
+	if err != nil {
+		return err
+	}
+
+	// GeoJSON feature IDs may be strings or numbers
+	f.ID = ""
+	if len(withID.ID) > 0 && withID.ID[0] == '"' {
+		err = json.Unmarshal(withID.ID, &f.ID)
+		if err != nil {
+			return err
+		}
+	} else if len(withID.ID) > 0 && string(withID.ID) != "null" {
+		f.ID = string(withID.ID)
+	}
+
+	raw := struct {
+		Properties map[string]interface{} `json:"properties"`
+	}{}
+	err = json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+	f.RawProperties = raw.Properties
+
+	return nil
+}
+
+// MarshalJSON encodes a feature, preferring the raw properties (where set) over the typed Properties
+// Unset geocoding fields are omitted so features may be submitted to other APIs
+func (f Feature) MarshalJSON() ([]byte, error) {
+	var properties interface{} = f.Properties
+	if f.RawProperties != nil {
+		properties = f.RawProperties
+	}
+
+	featureType := f.Type
+	if featureType == "" {
+		featureType = "Feature"
+	}
+
+	return json.Marshal(struct {
+		ID         string      `json:"id,omitempty"`
+		Type       string      `json:"type"`
+		Text       string      `json:"text,omitempty"`
+		PlaceName  string      `json:"place_name,omitempty"`
+		PlaceType  []string    `json:"place_type,omitempty"`
+		Relevance  float64     `json:"relevance,omitempty"`
+		Properties interface{} `json:"properties"`
+		BBox       BoundingBox `json:"bbox,omitempty"`
+		Center     []float64   `json:"center,omitempty"`
+		Geometry   Geometry    `json:"geometry"`
+		Context    []Context   `json:"context,omitempty"`
+		// RoutablePoints are retained so features round trip
+		RoutablePoints *RoutablePoints `json:"routable_points,omitempty"`
+	}{f.ID, featureType, f.Text, f.PlaceName, f.PlaceType, f.Relevance, properties, f.BBox, f.Center, f.Geometry, f.Context, f.RoutablePoints})
+}
+
+// RoutablePoints contains the points on the road network suitable for navigation to a feature
+// https://docs.mapbox.com/api/search/geocoding-v5/#routable-points
+type RoutablePoints struct {
+	Points []RoutablePoint `json:"points"`
+}
+
+// RoutablePoint is a named point on the road network, with coordinates in longitude, latitude order
+type RoutablePoint struct {
+	Name        string    `json:"name"`
+	Coordinates []float64 `json:"coordinates"`
 }
 
 type FeatureCollection struct {
@@ -0,0 +1,286 @@
+/**
+ * go-mapbox Datasets Module
+ * Wraps the mapbox datasets API for server side use
+ * See https://docs.mapbox.com/api/maps/datasets/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package datasets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-querystring/query"
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const (
+	apiName    = "datasets"
+	apiVersion = "v1"
+
+	scopeList  = "datasets:list"
+	scopeRead  = "datasets:read"
+	scopeWrite = "datasets:write"
+)
+
+// Datasets api wrapper instance
+// Requests are made on behalf of the account that owns the API token
+type Datasets struct {
+	base *base.Base
+}
+
+// NewDatasets Create a new Datasets API wrapper
+func NewDatasets(base *base.Base) *Datasets {
+	return &Datasets{base}
+}
+
+// ListOpts request options for listing datasets
+type ListOpts struct {
+	// Start is the ID of the dataset to start listing after, for pagination
+	Start string `url:"start,omitempty"`
+	// Limit is the maximum number of datasets to return
+	Limit int `url:"limit,omitempty"`
+	// SortBy sorts datasets by their creation or modification time
+	SortBy SortBy `url:"sortby,omitempty"`
+}
+
+// ListFeaturesOpts request options for listing features
+type ListFeaturesOpts struct {
+	// Start is the cursor returned as FeaturePage.Next, for pagination
+	Start string `url:"start,omitempty"`
+	// Limit is the maximum number of features to return
+	Limit int `url:"limit,omitempty"`
+}
+
+// DatasetOpts contains the editable properties of a dataset
+type DatasetOpts struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// queryString builds the path for a dataset query from the token owner and provided path elements
+func (d *Datasets) queryString(elements ...string) (string, error) {
+	username, err := d.base.Username()
+	if err != nil {
+		return "", err
+	}
+
+	for i, e := range elements {
+		elements[i] = url.PathEscape(e)
+	}
+
+	return strings.Join(append([]string{apiName, apiVersion, username}, elements...), "/"), nil
+}
+
+// List the datasets belonging to the token owner
+func (d *Datasets) List(ctx context.Context, opts *ListOpts) ([]Dataset, error) {
+	if opts == nil {
+		opts = &ListOpts{}
+	}
+
+	v, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	queryString, err := d.queryString()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]Dataset, 0)
+
+	err = d.base.QueryBaseContext(ctx, queryString, &v, &resp)
+	if err != nil {
+		return nil, base.WrapScopeError(err, scopeList)
+	}
+
+	return resp, nil
+}
+
+// Create a new empty dataset
+func (d *Datasets) Create(ctx context.Context, opts *DatasetOpts) (*Dataset, error) {
+	if opts == nil {
+		opts = &DatasetOpts{}
+	}
+
+	v := url.Values{}
+
+	queryString, err := d.queryString()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := Dataset{}
+
+	err = d.base.QueryWithBodyBase(ctx, http.MethodPost, queryString, &v, opts, &resp)
+	if err != nil {
+		return nil, base.WrapScopeError(err, scopeWrite)
+	}
+
+	return &resp, nil
+}
+
+// Get the metadata of a dataset
+func (d *Datasets) Get(ctx context.Context, datasetID string) (*Dataset, error) {
+	v := url.Values{}
+
+	queryString, err := d.queryString(datasetID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := Dataset{}
+
+	err = d.base.QueryBaseContext(ctx, queryString, &v, &resp)
+	if err != nil {
+		return nil, base.WrapScopeError(err, scopeRead)
+	}
+
+	return &resp, nil
+}
+
+// Update the name and / or description of a dataset
+func (d *Datasets) Update(ctx context.Context, datasetID string, opts *DatasetOpts) (*Dataset, error) {
+	if opts == nil {
+		opts = &DatasetOpts{}
+	}
+
+	v := url.Values{}
+
+	queryString, err := d.queryString(datasetID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := Dataset{}
+
+	err = d.base.QueryWithBodyBase(ctx, http.MethodPatch, queryString, &v, opts, &resp)
+	if err != nil {
+		return nil, base.WrapScopeError(err, scopeWrite)
+	}
+
+	return &resp, nil
+}
+
+// Delete a dataset and all of its features
+func (d *Datasets) Delete(ctx context.Context, datasetID string) error {
+	v := url.Values{}
+
+	queryString, err := d.queryString(datasetID)
+	if err != nil {
+		return err
+	}
+
+	err = d.base.QueryWithBodyBase(ctx, http.MethodDelete, queryString, &v, nil, nil)
+
+	return base.WrapScopeError(err, scopeWrite)
+}
+
+// ListFeatures lists a page of the features in a dataset
+// Subsequent pages are fetched by passing the returned FeaturePage.Next as ListFeaturesOpts.Start
+func (d *Datasets) ListFeatures(ctx context.Context, datasetID string, opts *ListFeaturesOpts) (*FeaturePage, error) {
+	if opts == nil {
+		opts = &ListFeaturesOpts{}
+	}
+
+	v, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	queryString, err := d.queryString(datasetID, "features")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := d.base.QueryRequestContext(ctx, queryString, &v)
+	if err != nil {
+		return nil, base.WrapScopeError(err, scopeRead)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	page := FeaturePage{}
+	err = json.Unmarshal(body, &page.FeatureCollection)
+	if err != nil {
+		return nil, err
+	}
+
+	page.Next, _ = base.NewPaginator(resp).Next()
+
+	return &page, nil
+}
+
+// GetFeature fetches a single feature from a dataset
+func (d *Datasets) GetFeature(ctx context.Context, datasetID, featureID string) (*base.Feature, error) {
+	v := url.Values{}
+
+	queryString, err := d.queryString(datasetID, "features", featureID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := base.Feature{}
+
+	err = d.base.QueryBaseContext(ctx, queryString, &v, &resp)
+	if err != nil {
+		return nil, base.WrapScopeError(err, scopeRead)
+	}
+
+	return &resp, nil
+}
+
+// PutFeature inserts or updates a feature in a dataset, returning the stored feature
+// The feature ID is set to featureID as required by the API
+func (d *Datasets) PutFeature(ctx context.Context, datasetID, featureID string, feature *base.Feature) (*base.Feature, error) {
+	if feature == nil {
+		return nil, fmt.Errorf("Feature %s must not be nil", featureID)
+	}
+
+	v := url.Values{}
+
+	queryString, err := d.queryString(datasetID, "features", featureID)
+	if err != nil {
+		return nil, err
+	}
+
+	f := *feature
+	f.ID = featureID
+
+	// The API responds with the stored feature (200) or no content (204), in which case the sent feature is returned
+	resp := f
+
+	err = d.base.QueryWithBodyBase(ctx, http.MethodPut, queryString, &v, &f, &resp)
+	if err != nil {
+		return nil, base.WrapScopeError(err, scopeWrite)
+	}
+
+	return &resp, nil
+}
+
+// DeleteFeature removes a feature from a dataset
+func (d *Datasets) DeleteFeature(ctx context.Context, datasetID, featureID string) error {
+	v := url.Values{}
+
+	queryString, err := d.queryString(datasetID, "features", featureID)
+	if err != nil {
+		return err
+	}
+
+	err = d.base.QueryWithBodyBase(ctx, http.MethodDelete, queryString, &v, nil, nil)
+
+	return base.WrapScopeError(err, scopeWrite)
+}
@@ -0,0 +1,227 @@
+/**
+ * go-mapbox Datasets Module Tests
+ * Wraps the mapbox datasets API for server side use
+ * See https://docs.mapbox.com/api/maps/datasets/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package datasets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/internal/basetest"
+)
+
+// testToken is a token belonging to the user "example"
+const testToken = "pk.eyJ1IjoiZXhhbXBsZSIsImEiOiJjamFiYyJ9.signature"
+
+const datasetResponse = `{
+	"owner": "example",
+	"id": "cjdataset",
+	"name": "Parks",
+	"description": "City parks",
+	"created": "2023-01-01T00:00:00.000Z",
+	"modified": "2023-01-02T00:00:00.000Z",
+	"bounds": [-10, -10, 10, 10],
+	"features": 2,
+	"size": 512
+}`
+
+func TestDatasets(t *testing.T) {
+
+	t.Run("Can manage datasets", func(t *testing.T) {
+		d := NewDatasets(basetest.NewBaseWithToken(t, testToken, func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /datasets/v1/example":
+				assert.EqualValues(t, "modified", r.URL.Query().Get("sortby"))
+				w.Write([]byte("[" + datasetResponse + "]"))
+			case "POST /datasets/v1/example", "PATCH /datasets/v1/example/cjdataset":
+				opts := DatasetOpts{}
+				assert.Nil(t, json.NewDecoder(r.Body).Decode(&opts))
+				assert.EqualValues(t, "Parks", opts.Name)
+				w.Write([]byte(datasetResponse))
+			case "GET /datasets/v1/example/cjdataset":
+				w.Write([]byte(datasetResponse))
+			case "DELETE /datasets/v1/example/cjdataset":
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				http.NotFound(w, r)
+			}
+		}))
+
+		ctx := context.Background()
+
+		list, err := d.List(ctx, &ListOpts{SortBy: SortByModified})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.Len(t, list, 1)
+
+		dataset, err := d.Create(ctx, &DatasetOpts{Name: "Parks"})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "cjdataset", dataset.ID)
+		assert.EqualValues(t, 2, dataset.Features)
+
+		_, err = d.Update(ctx, "cjdataset", &DatasetOpts{Name: "Parks"})
+		assert.Nil(t, err)
+
+		dataset, err = d.Get(ctx, "cjdataset")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, base.BoundingBox{-10, -10, 10, 10}, dataset.Bounds)
+
+		assert.Nil(t, d.Delete(ctx, "cjdataset"))
+	})
+
+	t.Run("Can page through features", func(t *testing.T) {
+		d := NewDatasets(basetest.NewBaseWithToken(t, testToken, func(w http.ResponseWriter, r *http.Request) {
+			assert.EqualValues(t, "/datasets/v1/example/cjdataset/features", r.URL.Path)
+
+			switch r.URL.Query().Get("start") {
+			case "":
+				w.Header().Set("Link", `<https://api.mapbox.com/datasets/v1/example/cjdataset/features?start=one&limit=1>; rel="next"`)
+				w.Write([]byte(`{"type":"FeatureCollection","features":[{"id":"one","type":"Feature","properties":{},"geometry":{"type":"Point","coordinates":[1,2]}}]}`))
+			case "one":
+				w.Write([]byte(`{"type":"FeatureCollection","features":[{"id":"two","type":"Feature","properties":{},"geometry":{"type":"LineString","coordinates":[[1,2],[3,4]]}}]}`))
+			}
+		}))
+
+		page, err := d.ListFeatures(context.Background(), "cjdataset", &ListFeaturesOpts{Limit: 1})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "one", page.Features[0].ID)
+		assert.EqualValues(t, "one", page.Next)
+
+		page, err = d.ListFeatures(context.Background(), "cjdataset", &ListFeaturesOpts{Limit: 1, Start: page.Next})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "two", page.Features[0].ID)
+		assert.EqualValues(t, "LineString", page.Features[0].Geometry.Type)
+		assert.EqualValues(t, "", page.Next)
+	})
+
+	t.Run("Can manage features", func(t *testing.T) {
+		feature := `{"id":"park","type":"Feature","properties":{"name":"Central"},"geometry":{"type":"Point","coordinates":[1,2]}}`
+
+		d := NewDatasets(basetest.NewBaseWithToken(t, testToken, func(w http.ResponseWriter, r *http.Request) {
+			assert.EqualValues(t, "/datasets/v1/example/cjdataset/features/park", r.URL.Path)
+
+			switch r.Method {
+			case http.MethodGet:
+				w.Write([]byte(feature))
+			case http.MethodPut:
+				body, _ := ioutil.ReadAll(r.Body)
+				assert.JSONEq(t, feature, string(body))
+				w.Write(body)
+			case http.MethodDelete:
+				w.WriteHeader(http.StatusNoContent)
+			}
+		}))
+
+		ctx := context.Background()
+
+		f, err := d.GetFeature(ctx, "cjdataset", "park")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "Central", f.RawProperties["name"])
+		assert.EqualValues(t, []float64{1, 2}, f.Geometry.Coordinates)
+
+		f.ID = ""
+		stored, err := d.PutFeature(ctx, "cjdataset", "park", f)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "", f.ID)
+		assert.EqualValues(t, "park", stored.ID)
+
+		assert.Nil(t, d.DeleteFeature(ctx, "cjdataset", "park"))
+	})
+
+	t.Run("Round trips features", func(t *testing.T) {
+		stored := make(map[string][]byte)
+		d := NewDatasets(basetest.NewBaseWithToken(t, testToken, func(w http.ResponseWriter, r *http.Request) {
+			id := strings.TrimPrefix(r.URL.Path, "/datasets/v1/example/cjdataset/features/")
+
+			switch r.Method {
+			case http.MethodPut:
+				body, _ := ioutil.ReadAll(r.Body)
+				stored[id] = body
+				w.Write(body)
+			case http.MethodGet:
+				body, ok := stored[id]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"message":"Feature does not exist"}`))
+					return
+				}
+				w.Write(body)
+			case http.MethodDelete:
+				delete(stored, id)
+				w.WriteHeader(http.StatusNoContent)
+			}
+		}))
+
+		ctx := context.Background()
+		f := base.Feature{
+			Geometry:      base.Geometry{Type: "Point", Coordinates: []float64{151.2, -33.8}},
+			RawProperties: map[string]interface{}{"name": "Hyde Park"},
+		}
+
+		created, err := d.PutFeature(ctx, "cjdataset", "hyde-park", &f)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "hyde-park", created.ID)
+
+		read, err := d.GetFeature(ctx, "cjdataset", "hyde-park")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "Hyde Park", read.RawProperties["name"])
+		assert.EqualValues(t, []float64{151.2, -33.8}, read.Geometry.Coordinates)
+
+		assert.Nil(t, d.DeleteFeature(ctx, "cjdataset", "hyde-park"))
+
+		_, err = d.GetFeature(ctx, "cjdataset", "hyde-park")
+		apiErr := &base.APIError{}
+		if assert.True(t, errors.As(err, &apiErr)) {
+			assert.EqualValues(t, http.StatusNotFound, apiErr.StatusCode)
+		}
+	})
+
+	t.Run("Returns scope errors for mutations", func(t *testing.T) {
+		d := NewDatasets(basetest.NewBaseWithToken(t, testToken, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"This endpoint requires a token with datasets:write scope"}`))
+		}))
+
+		_, err := d.PutFeature(context.Background(), "cjdataset", "park", &base.Feature{})
+		scopeErr := &base.ScopeError{}
+		if assert.True(t, errors.As(err, &scopeErr)) {
+			assert.EqualValues(t, "datasets:write", scopeErr.Scope)
+		}
+
+		err = d.DeleteFeature(context.Background(), "cjdataset", "park")
+		if assert.True(t, errors.As(err, &scopeErr)) {
+			assert.EqualValues(t, "datasets:write", scopeErr.Scope)
+		}
+		assert.True(t, errors.Is(err, base.ErrorAPIUnauthorized))
+	})
+}
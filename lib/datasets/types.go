@@ -0,0 +1,45 @@
+/**
+ * go-mapbox Datasets Module Types
+ * Wraps the mapbox datasets API for server side use
+ * See https://docs.mapbox.com/api/maps/datasets/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package datasets
+
+import (
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+// SortBy sort order for dataset listings
+type SortBy string
+
+const (
+	// SortByCreated sorts datasets by creation time
+	SortByCreated SortBy = "created"
+	// SortByModified sorts datasets by modification time
+	SortByModified SortBy = "modified"
+)
+
+// Dataset is the metadata describing a dataset
+// https://docs.mapbox.com/api/maps/datasets/#the-dataset-object
+type Dataset struct {
+	Owner       string           `json:"owner"`
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Created     string           `json:"created"`
+	Modified    string           `json:"modified"`
+	Bounds      base.BoundingBox `json:"bounds"`
+	Features    int              `json:"features"`
+	Size        int              `json:"size"`
+}
+
+// FeaturePage is a single page of features from a dataset
+type FeaturePage struct {
+	base.FeatureCollection
+	// Next is the cursor for the following page, empty where there are no more features
+	Next string
+}
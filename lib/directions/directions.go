@@ -11,7 +11,10 @@ package directions
 
 import (
 	"fmt"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/go-querystring/query"
 	"github.com/ryankurte/go-mapbox/lib/base"
@@ -36,10 +39,21 @@ const (
 	RoutingCycling RoutingProfile = "mapbox/cycling"
 )
 
+// IsValid checks whether the profile is supported by the directions API
+func (p RoutingProfile) IsValid() bool {
+	switch p {
+	case RoutingDrivingTraffic, RoutingDriving, RoutingWalking, RoutingCycling:
+		return true
+	default:
+		return false
+	}
+}
+
 type GeometryType string
 
 const (
 	GeometryGeojson   GeometryType = "geojson"
+	GeometryGeoJSON   GeometryType = GeometryGeojson
 	GeometryPolyline  GeometryType = "polyline"
 	GeometryPolyline6 GeometryType = "polyline6"
 )
@@ -55,17 +69,64 @@ const (
 type AnnotationType string
 
 const (
-	AnnotationDuration AnnotationType = "duration"
-	AnnotationDistance AnnotationType = "distance"
-	AnnotationSpeed    AnnotationType = "speed"
+	AnnotationDuration          AnnotationType = "duration"
+	AnnotationDistance          AnnotationType = "distance"
+	AnnotationSpeed             AnnotationType = "speed"
+	AnnotationCongestion        AnnotationType = "congestion"
+	AnnotationCongestionNumeric AnnotationType = "congestion_numeric"
+	AnnotationMaxSpeed          AnnotationType = "maxspeed"
+)
+
+// VoiceUnitType is the unit system used for voice instructions
+type VoiceUnitType string
+
+const (
+	VoiceUnitsImperial VoiceUnitType = "imperial"
+	VoiceUnitsMetric   VoiceUnitType = "metric"
 )
 
 type RadiusType string
 
 const (
+	// Deprecated: use RadiusUnlimited with RequestOpts.Radiuses
 	RaduisUnlimited RadiusType = "unlimited"
 )
 
+// RadiusUnlimited is a sentinel radius allowing a location to be snapped to a road segment at any distance
+const RadiusUnlimited float64 = -1
+
+// ExcludeType is a type of road or crossing to avoid
+// Exclusions are only supported by some profiles:
+// driving and driving-traffic support all exclusions, cycling supports only ferry, and walking supports none
+type ExcludeType string
+
+const (
+	ExcludeToll            ExcludeType = "toll"
+	ExcludeMotorway        ExcludeType = "motorway"
+	ExcludeFerry           ExcludeType = "ferry"
+	ExcludeUnpaved         ExcludeType = "unpaved"
+	ExcludeCashOnlyTolls   ExcludeType = "cash_only_tolls"
+	ExcludeCountryCrossing ExcludeType = "country_crossing"
+)
+
+// profileExclusions are the exclusions supported by each routing profile
+var profileExclusions = map[RoutingProfile][]ExcludeType{
+	RoutingDriving:        {ExcludeToll, ExcludeMotorway, ExcludeFerry, ExcludeUnpaved, ExcludeCashOnlyTolls, ExcludeCountryCrossing},
+	RoutingDrivingTraffic: {ExcludeToll, ExcludeMotorway, ExcludeFerry, ExcludeUnpaved, ExcludeCashOnlyTolls, ExcludeCountryCrossing},
+	RoutingCycling:        {ExcludeFerry},
+	RoutingWalking:        {},
+}
+
+// ApproachType is the side of the road from which a waypoint is approached
+type ApproachType string
+
+const (
+	// ApproachUnrestricted allows approaching from either side of the road
+	ApproachUnrestricted ApproachType = "unrestricted"
+	// ApproachCurb requires arriving on the driving side of the road
+	ApproachCurb ApproachType = "curb"
+)
+
 // Directions api wrapper instance
 type Directions struct {
 	base *base.Base
@@ -78,64 +139,432 @@ func NewDirections(base *base.Base) *Directions {
 
 // RequestOpts request options for directions api
 type RequestOpts struct {
-	Alternatives       bool          `url:"alternatives,omitempty"`
-	Geometries         *GeometryType `url:"geometries,omitempty"`
-	Overview           *OverviewType `url:"overview,omitempty"`
-	Radiuses           string        `url:"radiuses,omitempty"`
-	Steps              bool          `url:"steps,omitempty"`
-	ContinueStraight   bool          `url:"continue_straight,omitempty"`
-	Bearings           string        `url:"bearings,omitempty"`
-	Annotations        string        `url:"annotations,omitempty"`
-	Language           string        `url:"language,omitempty"`
-	Exclude            string        `url:"exclude,omitempty"`
-	RoundaboutExits    bool          `url:"roundabout_exits,omitempty"`
-	VoiceInstructions  bool          `url:"voice_instructions,omitempty"`
-	BannerInstructions bool          `url:"banner_instructions,omitempty"`
-	VoiceUnits         string        `url:"voice_units,omitempty"`
+	// Alternatives requests alternative routes in addition to the recommended route
+	Alternatives       bool                `url:"alternatives,omitempty"`
+	Geometries         *GeometryType       `url:"geometries,omitempty"`
+	Overview           *OverviewType       `url:"overview,omitempty"`
+	Radiuses           []float64           `url:"-"`
+	Steps              bool                `url:"steps,omitempty"`
+	ContinueStraight   bool                `url:"continue_straight,omitempty"`
+	Bearings           []BearingConstraint `url:"-"`
+	Annotations        []AnnotationType    `url:"annotations,omitempty,comma"`
+	Language           string              `url:"language,omitempty"`
+	Exclude            string              `url:"exclude,omitempty"`
+	RoundaboutExits    bool                `url:"roundabout_exits,omitempty"`
+	VoiceInstructions  bool                `url:"voice_instructions,omitempty"`
+	BannerInstructions bool                `url:"banner_instructions,omitempty"`
+	VoiceUnits         VoiceUnitType       `url:"voice_units,omitempty"`
+	Approaches         []string            `url:"approaches,omitempty,semicolon"`
+	WaypointNames      []string            `url:"waypoint_names,omitempty,semicolon"`
+	// DepartAt is the departure time used to account for predicted traffic, and requires RoutingDrivingTraffic
+	DepartAt time.Time `url:"depart_at,omitempty"`
+	// ArriveBy is the desired arrival time, and requires RoutingDrivingTraffic and a Mapbox enterprise subscription
+	// Only one of DepartAt and ArriveBy may be set
+	ArriveBy time.Time `url:"arrive_by,omitempty"`
+	// MaxHeight and MaxWidth (meters, up to 10) and MaxWeight (metric tonnes, up to 100) restrict routes to those
+	// suitable for the vehicle, and require RoutingDriving or RoutingDrivingTraffic
+	MaxHeight float64 `url:"max_height,omitempty"`
+	MaxWidth  float64 `url:"max_width,omitempty"`
+	MaxWeight float64 `url:"max_weight,omitempty"`
+	// WalkingSpeed (meters per second, 0.14 to 6.94), WalkwayBias and AlleyBias (-1 to 1) tune pedestrian routes,
+	// and require RoutingWalking
+	WalkingSpeed float64 `url:"walking_speed,omitempty"`
+	WalkwayBias  float64 `url:"walkway_bias,omitempty"`
+	AlleyBias    float64 `url:"alley_bias,omitempty"`
+	// Waypoints are the indices of locations that are stops (creating legs), where the remaining locations
+	// are passed through without instructions. The first and last locations must be included
+	Waypoints []int `url:"waypoints,omitempty,semicolon"`
+}
+
+// SetPassThroughWaypoints sets the indices of the locations that are stops, with all other locations passed through
+func (o *RequestOpts) SetPassThroughWaypoints(coords []base.Location, stops []int) error {
+	err := validateStops(len(coords), stops)
+	if err != nil {
+		return err
+	}
+
+	o.Waypoints = stops
+
+	return nil
+}
+
+// validateStops checks stop indices are ordered, in range, and include the first and last locations
+func validateStops(count int, stops []int) error {
+	if len(stops) < 2 {
+		return &base.ValidationError{Field: "waypoints", Message: "a start and end stop are required"}
+	}
+
+	for i, s := range stops {
+		if s < 0 || s >= count {
+			return &base.ValidationError{Field: "waypoints", Message: fmt.Sprintf("index %d out of range (%d locations)", s, count)}
+		}
+		if i > 0 && s <= stops[i-1] {
+			return &base.ValidationError{Field: "waypoints", Message: fmt.Sprintf("index %d is out of order", s)}
+		}
+	}
+
+	if stops[0] != 0 || stops[len(stops)-1] != count-1 {
+		return &base.ValidationError{Field: "waypoints", Message: "the first and last locations must be stops"}
+	}
+
+	return nil
+}
+
+// VehicleProfile describes the dimensions of a vehicle for routing
+type VehicleProfile struct {
+	// Height in meters
+	Height float64
+	// Width in meters
+	Width float64
+	// Weight in metric tonnes
+	Weight float64
+}
+
+// SetVehicleProfile sets the vehicle dimensions used to restrict routes
+func (o *RequestOpts) SetVehicleProfile(vp VehicleProfile) {
+	o.MaxHeight = vp.Height
+	o.MaxWidth = vp.Width
+	o.MaxWeight = vp.Weight
+}
+
+// Vehicle dimension limits documented by the directions API
+const (
+	MaxVehicleHeight = 10.0
+	MaxVehicleWidth  = 10.0
+	MaxVehicleWeight = 100.0
+)
+
+// validateVehicle checks vehicle dimensions are in range and used with a supported profile
+func (o *RequestOpts) validateVehicle(profile RoutingProfile) error {
+	dimensions := []struct {
+		field string
+		value float64
+		max   float64
+	}{
+		{"max_height", o.MaxHeight, MaxVehicleHeight},
+		{"max_width", o.MaxWidth, MaxVehicleWidth},
+		{"max_weight", o.MaxWeight, MaxVehicleWeight},
+	}
+
+	for _, d := range dimensions {
+		if d.value < 0 {
+			return &base.ValidationError{
+				Field:   d.field,
+				Message: "must not be negative",
+			}
+		}
+		if d.value > d.max {
+			return &base.ValidationError{
+				Field:   d.field,
+				Message: fmt.Sprintf("%s out of range (maximum %s)", formatFloat(d.value), formatFloat(d.max)),
+			}
+		}
+		if d.value != 0 && profile != RoutingDriving && profile != RoutingDrivingTraffic {
+			return &base.ValidationError{
+				Field:   d.field,
+				Message: fmt.Sprintf("not supported by profile %s", profile),
+			}
+		}
+	}
+
+	return nil
+}
+
+// Walking option ranges documented by the directions API
+const (
+	MinWalkingSpeed = 0.14
+	MaxWalkingSpeed = 6.94
+)
+
+// validateWalking checks walking options are in range and used with a supported profile
+func (o *RequestOpts) validateWalking(profile RoutingProfile) error {
+	options := []struct {
+		field    string
+		value    float64
+		min, max float64
+	}{
+		{"walking_speed", o.WalkingSpeed, MinWalkingSpeed, MaxWalkingSpeed},
+		{"walkway_bias", o.WalkwayBias, -1, 1},
+		{"alley_bias", o.AlleyBias, -1, 1},
+	}
+
+	for _, opt := range options {
+		if opt.value == 0 {
+			continue
+		}
+		if profile != RoutingWalking {
+			return &base.ValidationError{
+				Field:   opt.field,
+				Message: fmt.Sprintf("not supported by profile %s", profile),
+			}
+		}
+		if opt.value < opt.min || opt.value > opt.max {
+			return &base.ValidationError{
+				Field:   opt.field,
+				Message: fmt.Sprintf("%s out of range [%s, %s]", formatFloat(opt.value), formatFloat(opt.min), formatFloat(opt.max)),
+			}
+		}
+	}
+
+	return nil
 }
 
 // SetRadiuses sets radiuses for the maximum distance any coordinate can move when snapped to  nearby road segment.
-// This must have the same number of radiuses as locations in the GetDirections request
+// This must have the same number of radiuses as locations in the GetDirections request, use RadiusUnlimited for no limit
 func (o *RequestOpts) SetRadiuses(radiuses []float64) {
-	lines := make([]string, len(radiuses))
-	for i, r := range radiuses {
-		lines[i] = fmt.Sprintf("%f", r)
+	o.Radiuses = radiuses
+}
+
+// BearingConstraint restricts the direction of travel at a location
+// The zero value applies no constraint
+type BearingConstraint struct {
+	// Angle is the clockwise angle from true north, from 0 to 360 (exclusive)
+	Angle float64
+	// Deviation is the allowed deviation from the angle, from 0 to 180
+	Deviation float64
+}
+
+// IsZero indicates whether the bearing applies no constraint
+func (b BearingConstraint) IsZero() bool {
+	return b.Angle == 0 && b.Deviation == 0
+}
+
+// validate checks the angle and deviation are in range
+func (b BearingConstraint) validate() error {
+	if b.Angle < 0 || b.Angle >= 360 {
+		return &base.ValidationError{Field: "bearings", Message: fmt.Sprintf("angle %s out of range [0, 360)", formatFloat(b.Angle))}
+	}
+	if b.Deviation < 0 || b.Deviation > 180 {
+		return &base.ValidationError{Field: "bearings", Message: fmt.Sprintf("deviation %s out of range [0, 180]", formatFloat(b.Deviation))}
 	}
-	o.Radiuses = strings.Join(lines, ";")
+	return nil
+}
+
+// SetBearings sets a bearing constraint for each location in the GetDirections request
+func (o *RequestOpts) SetBearings(constraints []BearingConstraint) error {
+	for _, c := range constraints {
+		if err := c.validate(); err != nil {
+			return err
+		}
+	}
+
+	o.Bearings = constraints
+
+	return nil
 }
 
-// SetBearings builds the bearings query argument from an array of angles and deviations
-// Note that this must be used with SetRadiuses and the length of the associated arrays must be the same
-func (o *RequestOpts) SetBearings(angles []float64, deviations []float64) error {
-	if len(angles) != len(deviations) {
-		return fmt.Errorf("RequestOpts.SetBearings error, angle and deviation arrays must have the same length")
+// validateWaypoints checks per-location options have one entry for each location,
+// and waypoint names have one entry for each stop where stops are set
+func (o *RequestOpts) validateWaypoints(count int) error {
+	stops, stopsName := count, "locations"
+	if len(o.Waypoints) > 0 {
+		if err := validateStops(count, o.Waypoints); err != nil {
+			return err
+		}
+		stops, stopsName = len(o.Waypoints), "waypoints"
+	}
+
+	lengths := []struct {
+		field  string
+		length int
+		count  int
+		of     string
+	}{
+		{"approaches", len(o.Approaches), count, "locations"},
+		{"bearings", len(o.Bearings), count, "locations"},
+		{"radiuses", len(o.Radiuses), count, "locations"},
+		{"waypoint_names", len(o.WaypointNames), stops, stopsName},
+	}
+
+	for _, l := range lengths {
+		if l.length > 0 && l.length != l.count {
+			return &base.ValidationError{
+				Field:   l.field,
+				Message: fmt.Sprintf("%d %s provided for %d %s", l.length, l.field, l.count, l.of),
+			}
+		}
+	}
+
+	for _, b := range o.Bearings {
+		if err := b.validate(); err != nil {
+			return err
+		}
 	}
 
-	lines := make([]string, len(angles))
-	for i := range angles {
-		lines[i] = fmt.Sprintf("%f,%f", angles[i], deviations[i])
+	// Empty approaches use the API default for that location
+	for _, a := range o.Approaches {
+		if a != "" && ApproachType(a) != ApproachUnrestricted && ApproachType(a) != ApproachCurb {
+			return &base.ValidationError{
+				Field:   "approaches",
+				Message: fmt.Sprintf("unknown approach %s", a),
+			}
+		}
 	}
-	o.Bearings = strings.Join(lines, ";")
 
 	return nil
 }
 
-// SetAnnotations builds the annotations query argument from an array of annotation types
-func (o *RequestOpts) SetAnnotations(annotations []AnnotationType) {
-	lines := make([]string, len(annotations))
-	for i, a := range annotations {
-		lines[i] = fmt.Sprintf("%s", a)
+// encodeWaypoints adds the bearings and radiuses to the query values
+func (o *RequestOpts) encodeWaypoints(v *url.Values) {
+	if len(o.Bearings) > 0 {
+		bearings := make([]string, len(o.Bearings))
+		for i, b := range o.Bearings {
+			if !b.IsZero() {
+				bearings[i] = formatFloat(b.Angle) + "," + formatFloat(b.Deviation)
+			}
+		}
+		v.Set("bearings", strings.Join(bearings, ";"))
+	}
+
+	if len(o.Radiuses) > 0 {
+		radiuses := make([]string, len(o.Radiuses))
+		for i, r := range o.Radiuses {
+			if r == RadiusUnlimited {
+				radiuses[i] = string(RaduisUnlimited)
+			} else {
+				radiuses[i] = formatFloat(r)
+			}
+		}
+		v.Set("radiuses", strings.Join(radiuses, ";"))
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// SetExclude sets the road types to be avoided, see ExcludeType for profile compatibility
+func (o *RequestOpts) SetExclude(types []ExcludeType) {
+	lines := make([]string, len(types))
+	for i, t := range types {
+		lines[i] = string(t)
+	}
+	o.Exclude = strings.Join(lines, ",")
+}
+
+// validate checks the options are valid for the routing profile and number of locations
+func (o *RequestOpts) validate(profile RoutingProfile, count int) error {
+	if err := o.validateExclude(profile); err != nil {
+		return err
+	}
+	if err := o.validateWaypoints(count); err != nil {
+		return err
+	}
+	if err := o.validateTime(profile); err != nil {
+		return err
+	}
+	if err := o.validateVehicle(profile); err != nil {
+		return err
+	}
+	if err := o.validateWalking(profile); err != nil {
+		return err
+	}
+	if len(o.Annotations) > 0 && o.Overview != nil && *o.Overview != OverviewFull {
+		return &base.ValidationError{Field: "overview", Message: fmt.Sprintf("annotations require overview %s (overview is %s)", OverviewFull, *o.Overview)}
+	}
+	return nil
+}
+
+// validateExclude checks the exclusions are supported by the routing profile
+func (o *RequestOpts) validateExclude(profile RoutingProfile) error {
+	if o.Exclude == "" {
+		return nil
+	}
+
+	supported, ok := profileExclusions[profile]
+	if !ok {
+		return nil
+	}
+
+	for _, e := range strings.Split(o.Exclude, ",") {
+		found := false
+		for _, s := range supported {
+			if ExcludeType(e) == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &base.ValidationError{
+				Field:   "exclude",
+				Message: fmt.Sprintf("%s is not supported by profile %s", e, profile),
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateTime checks departure and arrival times are used with a supported profile
+func (o *RequestOpts) validateTime(profile RoutingProfile) error {
+	if o.DepartAt.IsZero() && o.ArriveBy.IsZero() {
+		return nil
+	}
+	if !o.DepartAt.IsZero() && !o.ArriveBy.IsZero() {
+		return &base.ValidationError{
+			Field:   "depart_at",
+			Message: "depart_at and arrive_by cannot be used together",
+		}
+	}
+	if profile != RoutingDrivingTraffic {
+		field := "depart_at"
+		if o.DepartAt.IsZero() {
+			field = "arrive_by"
+		}
+		return &base.ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("not supported by profile %s", profile),
+		}
+	}
+	return nil
+}
+
+// SetApproaches sets the approach for each location in the GetDirections request
+func (o *RequestOpts) SetApproaches(types []ApproachType) {
+	o.Approaches = make([]string, len(types))
+	for i, t := range types {
+		o.Approaches[i] = string(t)
 	}
-	o.Radiuses = strings.Join(lines, ",")
+}
+
+// SetAnnotations sets the annotations to be returned for each route leg
+func (o *RequestOpts) SetAnnotations(annotations []AnnotationType) {
+	o.Annotations = annotations
 }
 
 // GetDirections between a set of locations using the specified routing profile
+// Annotations require the full overview geometry, which is requested automatically where no overview is set
+// Voice and banner instructions require steps, which are requested automatically
 func (g *Directions) GetDirections(locations []base.Location, profile RoutingProfile, opts *RequestOpts) (*DirectionResponse, error) {
+	if !profile.IsValid() {
+		return nil, &base.ValidationError{Field: "profile", Message: fmt.Sprintf("%q is not a supported routing profile", string(profile))}
+	}
+	if opts != nil {
+		if err := opts.validate(profile, len(locations)); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts != nil && len(opts.Annotations) > 0 {
+		o := *opts
+		overview := OverviewFull
+		o.Overview = &overview
+		opts = &o
+	}
+
+	if opts != nil && (opts.VoiceInstructions || opts.BannerInstructions) && !opts.Steps {
+		o := *opts
+		o.Steps = true
+		opts = &o
+	}
 
 	v, err := query.Values(opts)
 	if err != nil {
 		return nil, err
 	}
+	if opts != nil {
+		opts.encodeWaypoints(&v)
+	}
 
 	coordinateStrings := make([]string, len(locations))
 	for i, l := range locations {
@@ -146,6 +575,18 @@ func (g *Directions) GetDirections(locations []base.Location, profile RoutingPro
 	resp := DirectionResponse{}
 
 	err = g.base.Query(apiName, apiVersion, string(profile), queryString, &v, &resp)
+	if err != nil {
+		return &resp, err
+	}
+
+	geometries := GeometryPolyline
+	if opts != nil && opts.Geometries != nil {
+		geometries = *opts.Geometries
+	}
+	err = resp.DecodeGeometries(geometries)
+	if err != nil {
+		return &resp, err
+	}
 
-	return &resp, err
+	return &resp, nil
 }
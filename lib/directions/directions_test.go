@@ -10,12 +10,20 @@
 package directions
 
 import (
+	"encoding/json"
+	"errors"
+	"net/http"
 	"os"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 import (
 	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/internal/basetest"
+	"github.com/ryankurte/go-mapbox/lib/polyline"
 )
 
 func TestDirections(t *testing.T) {
@@ -45,3 +53,800 @@ func TestDirections(t *testing.T) {
 	})
 
 }
+
+func TestRoutingProfile(t *testing.T) {
+	requests := 0
+	d := NewDirections(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"code":"Ok","waypoints":[],"routes":[]}`))
+	}))
+
+	locs := []base.Location{{Latitude: 37.78, Longitude: -122.42}, {Latitude: 37.79, Longitude: -122.40}}
+
+	for _, p := range []RoutingProfile{RoutingDrivingTraffic, RoutingDriving, RoutingWalking, RoutingCycling} {
+		assert.True(t, p.IsValid(), string(p))
+	}
+
+	for _, p := range []RoutingProfile{"", "driving", "mapbox/flying"} {
+		_, err := d.GetDirections(locs, p, nil)
+		validationErr := &base.ValidationError{}
+		if assert.True(t, errors.As(err, &validationErr), string(p)) {
+			assert.EqualValues(t, "profile", validationErr.Field)
+		}
+	}
+	assert.EqualValues(t, 0, requests)
+}
+
+func TestAlternatives(t *testing.T) {
+	d := NewDirections(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.EqualValues(t, "true", r.URL.Query().Get("alternatives"))
+		w.Write([]byte(`{"code":"Ok","waypoints":[],"routes":[
+			{"distance":4500000,"duration":160000,"geometry":"_p~iF~ps|U_ulLnnqC","legs":[]},
+			{"distance":4400000,"duration":150000,"geometry":"_p~iF~ps|U","legs":[]},
+			{"distance":4300000,"duration":170000,"geometry":"_p~iF~ps|U","legs":[]}
+		]}`))
+	}))
+
+	locs := []base.Location{{Latitude: 37.78, Longitude: -122.42}, {Latitude: 38.91, Longitude: -77.03}}
+
+	res, err := d.GetDirections(locs, RoutingDriving, &RequestOpts{Alternatives: true})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	if !assert.Len(t, res.Routes, 3) {
+		t.FailNow()
+	}
+	assert.EqualValues(t, 4500000, res.Routes[0].Distance)
+
+	assert.True(t, res.Routes[0].IsPrimary())
+	assert.False(t, res.Routes[1].IsPrimary())
+	assert.False(t, res.Routes[2].IsPrimary())
+
+	assert.EqualValues(t, 150000, res.BestRoute(CriteriaTime).Duration)
+	assert.EqualValues(t, 4300000, res.BestRoute(CriteriaDistance).Distance)
+	assert.Nil(t, res.BestRoute("scenic"))
+	assert.Nil(t, (&DirectionResponse{}).BestRoute(CriteriaTime))
+
+	routes := res.RoutesByDuration()
+	assert.EqualValues(t, 4400000, routes[0].Distance)
+	assert.EqualValues(t, 4500000, routes[1].Distance)
+	assert.EqualValues(t, 4300000, routes[2].Distance)
+}
+
+func TestSteps(t *testing.T) {
+	d := NewDirections(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.EqualValues(t, "true", r.URL.Query().Get("steps"))
+		w.Write([]byte(`{"code":"Ok","waypoints":[],"routes":[{"distance":1200,"duration":180,"geometry":"_p~iF~ps|U_ulLnnqC","legs":[
+			{"distance":1200,"duration":180,"summary":"Main St","steps":[
+				{"distance":800,"duration":120,"name":"Main St","mode":"driving","maneuver":{"type":"depart","instruction":"Head north on Main St","bearing_before":0,"bearing_after":12,"location":[-122.42,37.78]}},
+				{"distance":400,"duration":60,"name":"Market St","mode":"driving","maneuver":{"type":"turn","modifier":"right","instruction":"Turn right onto Market St","bearing_before":12,"bearing_after":102,"location":[-122.41,37.79]}},
+				{"distance":0,"duration":0,"name":"Market St","mode":"driving","maneuver":{"type":"arrive","instruction":"You have arrived at your destination","bearing_before":102,"bearing_after":0,"location":[-122.40,37.79]}}
+			]}
+		]}]}`))
+	}))
+
+	locs := []base.Location{{Latitude: 37.78, Longitude: -122.42}, {Latitude: 37.79, Longitude: -122.40}}
+
+	res, err := d.GetDirections(locs, RoutingDriving, &RequestOpts{Steps: true})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	step := res.Routes[0].Legs[0].Steps[1]
+	assert.EqualValues(t, "Market St", step.Name)
+	assert.EqualValues(t, "turn", step.Maneuver.Type)
+	assert.EqualValues(t, StepModifierRight, step.Maneuver.Modifier)
+	assert.EqualValues(t, 12, step.Maneuver.BearingBefore)
+	assert.EqualValues(t, 102, step.Maneuver.BearingAfter)
+	assert.EqualValues(t, []float64{-122.41, 37.79}, step.Maneuver.Location)
+
+	assert.EqualValues(t, []string{
+		"Head north on Main St",
+		"Turn right onto Market St",
+		"You have arrived at your destination",
+	}, res.Routes[0].Instructions())
+}
+
+func TestAnnotations(t *testing.T) {
+	d := NewDirections(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.EqualValues(t, "duration,distance,speed,congestion,congestion_numeric,maxspeed", r.URL.Query().Get("annotations"))
+		assert.EqualValues(t, "full", r.URL.Query().Get("overview"))
+		w.Write([]byte(`{"code":"Ok","waypoints":[],"routes":[{"distance":300,"duration":30,"geometry":"_p~iF~ps|U_ulLnnqC_||FnwH","legs":[{"distance":300,"duration":30,"steps":[],"annotation":{
+			"duration":[10.5,19.5],
+			"distance":[100,200],
+			"speed":[9.5,10.3],
+			"congestion":["low","heavy"],
+			"congestion_numeric":[4,null],
+			"maxspeed":[{"speed":50,"unit":"km/h"},{"unknown":true}]
+		}}]}]}`))
+	}))
+
+	locs := []base.Location{{Latitude: 37.78, Longitude: -122.42}, {Latitude: 37.79, Longitude: -122.40}}
+
+	opts := RequestOpts{}
+	opts.SetAnnotations([]AnnotationType{
+		AnnotationDuration, AnnotationDistance, AnnotationSpeed,
+		AnnotationCongestion, AnnotationCongestionNumeric, AnnotationMaxSpeed,
+	})
+
+	res, err := d.GetDirections(locs, RoutingDrivingTraffic, &opts)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	a := res.Routes[0].Legs[0].Annotation
+	assert.EqualValues(t, []float64{10.5, 19.5}, a.Duration)
+	assert.EqualValues(t, []float64{100, 200}, a.Distance)
+	assert.EqualValues(t, []float64{9.5, 10.3}, a.Speed)
+	assert.EqualValues(t, []string{"low", "heavy"}, a.Congestion)
+	assert.EqualValues(t, []int{4, 0}, a.CongestionNumeric)
+	assert.EqualValues(t, []MaxSpeedAnnotation{{Speed: 50, Unit: "km/h"}, {Unknown: true}}, a.MaxSpeed)
+	assert.Nil(t, opts.Overview)
+
+	// Annotations have one entry per segment of the geometry
+	coordinates := [][]float64{}
+	assert.Nil(t, json.Unmarshal(res.Routes[0].Geometry.RawCoordinates, &coordinates))
+	assert.Len(t, a.Duration, len(coordinates)-1)
+	assert.Len(t, a.Distance, len(coordinates)-1)
+	assert.Len(t, a.Speed, len(coordinates)-1)
+	assert.Len(t, a.Congestion, len(coordinates)-1)
+	assert.Len(t, a.CongestionNumeric, len(coordinates)-1)
+	assert.Len(t, a.MaxSpeed, len(coordinates)-1)
+
+	overview := OverviewFalse
+	opts.Overview = &overview
+	_, err = d.GetDirections(locs, RoutingDrivingTraffic, &opts)
+	validationErr := &base.ValidationError{}
+	if assert.True(t, errors.As(err, &validationErr)) {
+		assert.EqualValues(t, "overview", validationErr.Field)
+	}
+}
+
+func TestGeometries(t *testing.T) {
+	expected := `{"type":"LineString","coordinates":[[-120.2,38.5],[-120.95,40.7],[-126.453,43.252]]}`
+
+	tests := []struct {
+		name       string
+		geometries GeometryType
+		geometry   string
+	}{
+		{"polyline", GeometryPolyline, `"_p~iF~ps|U_ulLnnqC_mqNvxq` + "`" + `@"`},
+		{"polyline6", GeometryPolyline6, `"_izlhA~rlgdF_{geC~ywl@_kwzCn` + "`" + `{nI"`},
+		{"geojson", GeometryGeoJSON, expected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDirections(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.EqualValues(t, string(tt.geometries), r.URL.Query().Get("geometries"))
+				assert.EqualValues(t, "true", r.URL.Query().Get("steps"))
+				w.Write([]byte(`{"code":"Ok","waypoints":[],"routes":[{"distance":1,"duration":1,"geometry":` + tt.geometry +
+					`,"legs":[{"steps":[{"distance":1,"duration":1,"geometry":` + tt.geometry + `}]}]}]}`))
+			}))
+
+			locs := []base.Location{{Latitude: 38.5, Longitude: -120.2}, {Latitude: 43.252, Longitude: -126.453}}
+			geometries := tt.geometries
+
+			res, err := d.GetDirections(locs, RoutingDriving, &RequestOpts{Geometries: &geometries, Steps: true})
+			if !assert.Nil(t, err) {
+				t.FailNow()
+			}
+
+			route := res.Routes[0]
+			assert.EqualValues(t, "LineString", route.Geometry.Type)
+
+			data, err := route.GeoJSON()
+			if !assert.Nil(t, err) {
+				t.FailNow()
+			}
+			assert.JSONEq(t, expected, string(data))
+
+			if tt.geometries != GeometryGeoJSON {
+				assert.EqualValues(t, tt.geometry, `"`+route.EncodedGeometry+`"`)
+			}
+
+			locations, err := route.DecodeGeometry()
+			if !assert.Nil(t, err) {
+				t.FailNow()
+			}
+			if assert.Len(t, locations, 3) {
+				assert.InDelta(t, 43.252, locations[2].Latitude, 1e-9)
+				assert.InDelta(t, -126.453, locations[2].Longitude, 1e-9)
+			}
+
+			step := route.Legs[0].Steps[0]
+			data, err = json.Marshal(&step.Geometry)
+			if !assert.Nil(t, err) {
+				t.FailNow()
+			}
+			assert.JSONEq(t, expected, string(data))
+			assert.EqualValues(t, route.EncodedGeometry, step.EncodedGeometry)
+		})
+	}
+}
+
+func TestRouteCoordinates(t *testing.T) {
+	locs := []base.Location{{Latitude: 37.78, Longitude: -122.42}, {Latitude: 37.785, Longitude: -122.41}, {Latitude: 37.79, Longitude: -122.40}}
+
+	tests := []struct {
+		name       string
+		geometries GeometryType
+		geometry   interface{}
+	}{
+		{"polyline", GeometryPolyline, polyline.EncodeP5(locs)},
+		{"polyline6", GeometryPolyline6, polyline.EncodeP6(locs)},
+		{"geojson", GeometryGeoJSON, map[string]interface{}{"type": "LineString", "coordinates": [][]float64{{-122.42, 37.78}, {-122.41, 37.785}, {-122.40, 37.79}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(map[string]interface{}{"routes": []interface{}{map[string]interface{}{"geometry": tt.geometry}}})
+			if !assert.Nil(t, err) {
+				t.FailNow()
+			}
+
+			resp := DirectionResponse{}
+			if !assert.Nil(t, json.Unmarshal(data, &resp)) {
+				t.FailNow()
+			}
+
+			if tt.geometries != GeometryGeoJSON {
+				// The precision of encoded geometries is unknown until decoded with the requested geometry type
+				_, err = resp.Routes[0].Coordinates()
+				assert.NotNil(t, err)
+			}
+
+			if !assert.Nil(t, resp.DecodeGeometries(tt.geometries)) {
+				t.FailNow()
+			}
+
+			coordinates, err := resp.Routes[0].Coordinates()
+			if !assert.Nil(t, err) {
+				t.FailNow()
+			}
+			if assert.Len(t, coordinates, len(locs)) {
+				for i := range locs {
+					assert.InDelta(t, locs[i].Latitude, coordinates[i].Latitude, 1e-9)
+					assert.InDelta(t, locs[i].Longitude, coordinates[i].Longitude, 1e-9)
+				}
+			}
+		})
+	}
+
+	_, err := (&Route{}).Coordinates()
+	assert.NotNil(t, err)
+}
+
+func TestRouteTotals(t *testing.T) {
+	route := Route{}
+	err := json.Unmarshal([]byte(`{
+		"distance": 3000.5,
+		"duration": 420.5,
+		"legs": [
+			{"distance": 1200.25, "duration": 180.25, "annotation": {"duration": [60, 90.5]}},
+			{"distance": 1800.25, "duration": 240.25, "annotation": {"duration": [100, 150]}}
+		]
+	}`), &route)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.EqualValues(t, 3000.5, route.TotalDistance())
+	assert.EqualValues(t, 420500*time.Millisecond, route.TotalDuration())
+
+	duration, ok := route.DurationWithTraffic()
+	assert.True(t, ok)
+	assert.EqualValues(t, 400500*time.Millisecond, duration)
+
+	route.Legs[1].Annotation.Duration = nil
+	_, ok = route.DurationWithTraffic()
+	assert.False(t, ok)
+}
+
+func TestInstructions(t *testing.T) {
+	d := NewDirections(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.EqualValues(t, "true", r.URL.Query().Get("banner_instructions"))
+		assert.EqualValues(t, "true", r.URL.Query().Get("voice_instructions"))
+		assert.EqualValues(t, "true", r.URL.Query().Get("steps"))
+		assert.EqualValues(t, "metric", r.URL.Query().Get("voice_units"))
+		w.Write([]byte(`{"code":"Ok","waypoints":[],"routes":[{"distance":1,"duration":1,"legs":[{"steps":[{
+			"name":"Market St",
+			"maneuver":{"type":"turn","modifier":"right","instruction":"Turn right onto Market St"},
+			"bannerInstructions":[{
+				"distanceAlongGeometry":120.5,
+				"primary":{"text":"Market St","type":"turn","modifier":"right","driving_side":"right","components":[{"text":"Market St","type":"text","abbr":"Market","abbr_priority":0}]},
+				"secondary":{"text":"Downtown","components":[{"text":"Downtown","type":"text"}]},
+				"sub":{"text":"","components":[{"text":"","type":"lane","directions":["straight","right"],"active":true}]}
+			}],
+			"voiceInstructions":[{
+				"distanceAlongGeometry":120.5,
+				"announcement":"Turn right onto Market St",
+				"ssmlAnnouncement":"<speak>Turn right onto Market St</speak>"
+			}]
+		}]}]}]}`))
+	}))
+
+	locs := []base.Location{{Latitude: 37.78, Longitude: -122.42}, {Latitude: 37.79, Longitude: -122.40}}
+
+	res, err := d.GetDirections(locs, RoutingDrivingTraffic, &RequestOpts{BannerInstructions: true, VoiceInstructions: true, VoiceUnits: VoiceUnitsMetric})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	step := res.Routes[0].Legs[0].Steps[0]
+
+	if !assert.Len(t, step.BannerInstructions, 1) {
+		t.FailNow()
+	}
+	banner := step.BannerInstructions[0]
+	assert.EqualValues(t, 120.5, banner.DistanceAlongGeometry)
+	assert.EqualValues(t, StepModifierRight, banner.Primary.Modifier)
+	assert.EqualValues(t, "right", banner.Primary.DrivingSide)
+	assert.EqualValues(t, "Market", banner.Primary.Components[0].Abbreviation)
+	assert.EqualValues(t, "Downtown", banner.Secondary.Text)
+	assert.EqualValues(t, []string{"straight", "right"}, banner.Sub.Components[0].Directions)
+	assert.True(t, banner.Sub.Components[0].Active)
+
+	if !assert.Len(t, step.VoiceInstructions, 1) {
+		t.FailNow()
+	}
+	assert.EqualValues(t, "Turn right onto Market St", step.VoiceInstructions[0].Announcement)
+	assert.EqualValues(t, "<speak>Turn right onto Market St</speak>", step.VoiceInstructions[0].SSMLAnnouncement)
+}
+
+func TestExclude(t *testing.T) {
+	d := NewDirections(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":"Ok","waypoints":[],"routes":[]}`))
+	}))
+
+	locs := []base.Location{{Latitude: 37.78, Longitude: -122.42}, {Latitude: 37.79, Longitude: -122.40}}
+
+	tests := []struct {
+		name    string
+		profile RoutingProfile
+		exclude []ExcludeType
+		valid   bool
+	}{
+		{"driving supports all exclusions", RoutingDriving, []ExcludeType{ExcludeToll, ExcludeMotorway, ExcludeFerry, ExcludeUnpaved, ExcludeCashOnlyTolls, ExcludeCountryCrossing}, true},
+		{"driving traffic supports tolls", RoutingDrivingTraffic, []ExcludeType{ExcludeToll}, true},
+		{"cycling supports ferries", RoutingCycling, []ExcludeType{ExcludeFerry}, true},
+		{"cycling does not support tolls", RoutingCycling, []ExcludeType{ExcludeFerry, ExcludeToll}, false},
+		{"walking does not support ferries", RoutingWalking, []ExcludeType{ExcludeFerry}, false},
+		{"unknown exclusions are rejected", RoutingDriving, []ExcludeType{"bridge"}, false},
+		{"no exclusions", RoutingWalking, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := RequestOpts{}
+			opts.SetExclude(tt.exclude)
+
+			_, err := d.GetDirections(locs, tt.profile, &opts)
+			if tt.valid {
+				assert.Nil(t, err)
+				return
+			}
+
+			validationErr := &base.ValidationError{}
+			if assert.True(t, errors.As(err, &validationErr)) {
+				assert.EqualValues(t, "exclude", validationErr.Field)
+			}
+		})
+	}
+
+	t.Run("Joins exclusions", func(t *testing.T) {
+		opts := RequestOpts{}
+		opts.SetExclude([]ExcludeType{ExcludeToll, ExcludeFerry})
+		assert.EqualValues(t, "toll,ferry", opts.Exclude)
+	})
+
+	t.Run("Sends exclusions", func(t *testing.T) {
+		d := NewDirections(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Contains(t, r.URL.RawQuery, "exclude=toll%2Cferry")
+			assert.EqualValues(t, "toll,ferry", r.URL.Query().Get("exclude"))
+			w.Write([]byte(`{"code":"Ok","waypoints":[],"routes":[]}`))
+		}))
+
+		opts := RequestOpts{}
+		opts.SetExclude([]ExcludeType{ExcludeToll, ExcludeFerry})
+
+		_, err := d.GetDirections(locs, RoutingDriving, &opts)
+		assert.Nil(t, err)
+
+		opts.SetExclude([]ExcludeType{ExcludeMotorway})
+		_, err = d.GetDirections(locs, RoutingWalking, &opts)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestApproaches(t *testing.T) {
+	var approaches []string
+	d := NewDirections(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		approaches = r.URL.Query()["approaches"]
+		w.Write([]byte(`{"code":"Ok","waypoints":[],"routes":[]}`))
+	}))
+
+	locs := []base.Location{{Latitude: 37.78, Longitude: -122.42}, {Latitude: 37.79, Longitude: -122.40}}
+
+	t.Run("Rejects mismatched approaches", func(t *testing.T) {
+		opts := RequestOpts{}
+		opts.SetApproaches([]ApproachType{ApproachCurb})
+
+		_, err := d.GetDirections(locs, RoutingDriving, &opts)
+		validationErr := &base.ValidationError{}
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.EqualValues(t, "approaches", validationErr.Field)
+		}
+	})
+
+	t.Run("Sends approaches", func(t *testing.T) {
+		opts := RequestOpts{}
+		opts.SetApproaches([]ApproachType{ApproachCurb, ApproachCurb})
+
+		_, err := d.GetDirections(locs, RoutingDriving, &opts)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []string{"curb;curb"}, approaches)
+	})
+
+	t.Run("Rejects unknown approaches", func(t *testing.T) {
+		opts := RequestOpts{Approaches: []string{"curb", "kerb"}}
+
+		_, err := d.GetDirections(locs, RoutingDriving, &opts)
+		validationErr := &base.ValidationError{}
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.EqualValues(t, "approaches", validationErr.Field)
+		}
+	})
+
+	t.Run("Sends default approaches", func(t *testing.T) {
+		opts := RequestOpts{Approaches: []string{"", string(ApproachCurb)}}
+
+		_, err := d.GetDirections(locs, RoutingDriving, &opts)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []string{";curb"}, approaches)
+	})
+
+	t.Run("Omits default approaches", func(t *testing.T) {
+		_, err := d.GetDirections(locs, RoutingDriving, &RequestOpts{})
+		assert.Nil(t, err)
+		assert.Nil(t, approaches)
+	})
+}
+
+func TestBearingsAndRadiuses(t *testing.T) {
+	var query map[string][]string
+	d := NewDirections(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"code":"Ok","waypoints":[],"routes":[]}`))
+	}))
+
+	locs := []base.Location{{Latitude: 37.78, Longitude: -122.42}, {Latitude: 37.79, Longitude: -122.40}}
+
+	t.Run("Sends bearings and radiuses", func(t *testing.T) {
+		opts := RequestOpts{
+			Bearings: []BearingConstraint{{Angle: 45, Deviation: 30}, {}},
+			Radiuses: []float64{12.5, RadiusUnlimited},
+		}
+
+		_, err := d.GetDirections(locs, RoutingDriving, &opts)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []string{"45,30;"}, query["bearings"])
+		assert.EqualValues(t, []string{"12.5;unlimited"}, query["radiuses"])
+	})
+
+	t.Run("Rejects mismatched bearings", func(t *testing.T) {
+		opts := RequestOpts{Bearings: []BearingConstraint{{Angle: 45, Deviation: 30}}}
+
+		_, err := d.GetDirections(locs, RoutingDriving, &opts)
+		validationErr := &base.ValidationError{}
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.EqualValues(t, "bearings", validationErr.Field)
+		}
+	})
+
+	t.Run("Rejects invalid bearings", func(t *testing.T) {
+		opts := RequestOpts{}
+		for _, b := range []BearingConstraint{{Angle: 360, Deviation: 10}, {Angle: -1}, {Angle: 90, Deviation: 181}} {
+			err := opts.SetBearings([]BearingConstraint{{Angle: 0, Deviation: 45}, b})
+			validationErr := &base.ValidationError{}
+			if assert.True(t, errors.As(err, &validationErr)) {
+				assert.EqualValues(t, "bearings", validationErr.Field)
+			}
+		}
+		assert.Nil(t, opts.Bearings)
+
+		_, err := d.GetDirections(locs, RoutingDriving, &RequestOpts{Bearings: []BearingConstraint{{}, {Angle: 400}}})
+		validationErr := &base.ValidationError{}
+		assert.True(t, errors.As(err, &validationErr))
+	})
+
+	t.Run("Rejects mismatched radiuses", func(t *testing.T) {
+		opts := RequestOpts{}
+		opts.SetRadiuses([]float64{10, 20, 30})
+
+		_, err := d.GetDirections(locs, RoutingDriving, &opts)
+		validationErr := &base.ValidationError{}
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.EqualValues(t, "radiuses", validationErr.Field)
+		}
+	})
+}
+
+func TestWaypointNames(t *testing.T) {
+	var names []string
+	d := NewDirections(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		names = r.URL.Query()["waypoint_names"]
+		w.Write([]byte(`{"code":"Ok","waypoints":[{"name":"Home","location":[-122.42,37.78]},{"name":"Work","location":[-122.4,37.79]}],"routes":[]}`))
+	}))
+
+	locs := []base.Location{{Latitude: 37.78, Longitude: -122.42}, {Latitude: 37.79, Longitude: -122.40}}
+
+	t.Run("Sends waypoint names", func(t *testing.T) {
+		opts := RequestOpts{WaypointNames: []string{"Home", "Work"}}
+
+		resp, err := d.GetDirections(locs, RoutingDriving, &opts)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, []string{"Home;Work"}, names)
+		if assert.Len(t, resp.Waypoints, 2) {
+			assert.EqualValues(t, "Work", resp.Waypoints[1].Name)
+		}
+	})
+
+	t.Run("Rejects mismatched waypoint names", func(t *testing.T) {
+		opts := RequestOpts{WaypointNames: []string{"Home"}}
+
+		_, err := d.GetDirections(locs, RoutingDriving, &opts)
+		validationErr := &base.ValidationError{}
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.EqualValues(t, "waypoint_names", validationErr.Field)
+		}
+	})
+}
+
+func TestDepartAt(t *testing.T) {
+	var query map[string][]string
+	d := NewDirections(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"code":"Ok","waypoints":[],"routes":[]}`))
+	}))
+
+	locs := []base.Location{{Latitude: 37.78, Longitude: -122.42}, {Latitude: 37.79, Longitude: -122.40}}
+	departAt := time.Date(2019, 5, 14, 8, 30, 0, 0, time.UTC)
+
+	t.Run("Sends departure time", func(t *testing.T) {
+		_, err := d.GetDirections(locs, RoutingDrivingTraffic, &RequestOpts{DepartAt: departAt})
+		assert.Nil(t, err)
+		assert.EqualValues(t, []string{"2019-05-14T08:30:00Z"}, query["depart_at"])
+		assert.Nil(t, query["arrive_by"])
+	})
+
+	t.Run("Sends arrival time", func(t *testing.T) {
+		_, err := d.GetDirections(locs, RoutingDrivingTraffic, &RequestOpts{ArriveBy: departAt})
+		assert.Nil(t, err)
+		assert.EqualValues(t, []string{"2019-05-14T08:30:00Z"}, query["arrive_by"])
+		assert.Nil(t, query["depart_at"])
+	})
+
+	t.Run("Rejects departure and arrival times", func(t *testing.T) {
+		_, err := d.GetDirections(locs, RoutingDrivingTraffic, &RequestOpts{DepartAt: departAt, ArriveBy: departAt})
+		validationErr := &base.ValidationError{}
+		assert.True(t, errors.As(err, &validationErr))
+	})
+
+	t.Run("Rejects unsupported profiles", func(t *testing.T) {
+		_, err := d.GetDirections(locs, RoutingCycling, &RequestOpts{ArriveBy: departAt})
+		validationErr := &base.ValidationError{}
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.EqualValues(t, "arrive_by", validationErr.Field)
+		}
+	})
+}
+
+func TestManeuvers(t *testing.T) {
+	tests := []struct {
+		maneuver StepManeuver
+		left     bool
+		right    bool
+		uTurn    bool
+	}{
+		{StepManeuver{Type: ManeuverTurn, Modifier: ManeuverModifierSharpLeft}, true, false, false},
+		{StepManeuver{Type: ManeuverOffRamp, Modifier: ManeuverModifierSlightRight}, false, true, false},
+		{StepManeuver{Type: ManeuverContinue, Modifier: ManeuverModifierUTurn}, false, false, true},
+		{StepManeuver{Type: ManeuverContinue, Modifier: StepModifierStraight}, false, false, false},
+		{StepManeuver{Type: ManeuverArrive, Modifier: StepModifierLeft}, false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.maneuver.Type)+" "+string(tt.maneuver.Modifier), func(t *testing.T) {
+			assert.EqualValues(t, tt.left, tt.maneuver.IsLeftTurn())
+			assert.EqualValues(t, tt.right, tt.maneuver.IsRightTurn())
+			assert.EqualValues(t, tt.uTurn, tt.maneuver.IsUTurn())
+		})
+	}
+
+	t.Run("Decodes maneuver types", func(t *testing.T) {
+		m := StepManeuver{}
+		err := json.Unmarshal([]byte(`{"type":"roundabout turn","modifier":"slight left"}`), &m)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, ManeuverRoundaboutTurn, m.Type)
+		assert.True(t, m.IsLeftTurn())
+	})
+}
+
+func TestVehicleProfile(t *testing.T) {
+	var query map[string][]string
+	d := NewDirections(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"code":"Ok","waypoints":[],"routes":[]}`))
+	}))
+
+	locs := []base.Location{{Latitude: 37.78, Longitude: -122.42}, {Latitude: 37.79, Longitude: -122.40}}
+
+	t.Run("Sends vehicle dimensions", func(t *testing.T) {
+		opts := RequestOpts{}
+		opts.SetVehicleProfile(VehicleProfile{Height: 4.1, Width: 2.55, Weight: 12.5})
+
+		_, err := d.GetDirections(locs, RoutingDriving, &opts)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []string{"4.1"}, query["max_height"])
+		assert.EqualValues(t, []string{"2.55"}, query["max_width"])
+		assert.EqualValues(t, []string{"12.5"}, query["max_weight"])
+	})
+
+	t.Run("Omits unset dimensions", func(t *testing.T) {
+		_, err := d.GetDirections(locs, RoutingDriving, &RequestOpts{MaxHeight: 3})
+		assert.Nil(t, err)
+		assert.EqualValues(t, []string{"3"}, query["max_height"])
+		assert.Nil(t, query["max_width"])
+		assert.Nil(t, query["max_weight"])
+	})
+
+	t.Run("Sends dimensions with traffic", func(t *testing.T) {
+		_, err := d.GetDirections(locs, RoutingDrivingTraffic, &RequestOpts{MaxWidth: 2.5})
+		assert.Nil(t, err)
+		assert.EqualValues(t, []string{"2.5"}, query["max_width"])
+	})
+
+	tests := []struct {
+		name    string
+		profile RoutingProfile
+		opts    RequestOpts
+		field   string
+	}{
+		{"Rejects walking", RoutingWalking, RequestOpts{MaxWeight: 3.5}, "max_weight"},
+		{"Rejects cycling", RoutingCycling, RequestOpts{MaxHeight: 2}, "max_height"},
+		{"Rejects negative dimensions", RoutingDriving, RequestOpts{MaxWidth: -1}, "max_width"},
+		{"Rejects tall vehicles", RoutingDriving, RequestOpts{MaxHeight: 10.5}, "max_height"},
+		{"Rejects wide vehicles", RoutingDriving, RequestOpts{MaxWidth: 11}, "max_width"},
+		{"Rejects heavy vehicles", RoutingDrivingTraffic, RequestOpts{MaxWeight: 101}, "max_weight"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := d.GetDirections(locs, tt.profile, &tt.opts)
+			validationErr := &base.ValidationError{}
+			if assert.True(t, errors.As(err, &validationErr)) {
+				assert.EqualValues(t, tt.field, validationErr.Field)
+			}
+		})
+	}
+}
+
+func TestWalkingOptions(t *testing.T) {
+	var query map[string][]string
+	d := NewDirections(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"code":"Ok","waypoints":[],"routes":[]}`))
+	}))
+
+	locs := []base.Location{{Latitude: 37.78, Longitude: -122.42}, {Latitude: 37.79, Longitude: -122.40}}
+
+	t.Run("Sends walking options", func(t *testing.T) {
+		_, err := d.GetDirections(locs, RoutingWalking, &RequestOpts{WalkingSpeed: 1.2, WalkwayBias: 0.5, AlleyBias: -1})
+		assert.Nil(t, err)
+		assert.EqualValues(t, []string{"1.2"}, query["walking_speed"])
+		assert.EqualValues(t, []string{"0.5"}, query["walkway_bias"])
+		assert.EqualValues(t, []string{"-1"}, query["alley_bias"])
+	})
+
+	tests := []struct {
+		name    string
+		profile RoutingProfile
+		opts    RequestOpts
+		field   string
+	}{
+		{"Rejects slow walking speeds", RoutingWalking, RequestOpts{WalkingSpeed: 0.1}, "walking_speed"},
+		{"Rejects fast walking speeds", RoutingWalking, RequestOpts{WalkingSpeed: 7}, "walking_speed"},
+		{"Rejects walkway biases", RoutingWalking, RequestOpts{WalkwayBias: 1.5}, "walkway_bias"},
+		{"Rejects alley biases", RoutingWalking, RequestOpts{AlleyBias: -2}, "alley_bias"},
+		{"Rejects unsupported profiles", RoutingCycling, RequestOpts{WalkingSpeed: 1.2}, "walking_speed"},
+		{"Rejects biases for unsupported profiles", RoutingDriving, RequestOpts{AlleyBias: 0.5}, "alley_bias"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := d.GetDirections(locs, tt.profile, &tt.opts)
+			validationErr := &base.ValidationError{}
+			if assert.True(t, errors.As(err, &validationErr)) {
+				assert.EqualValues(t, tt.field, validationErr.Field)
+			}
+		})
+	}
+}
+
+func TestPassThroughWaypoints(t *testing.T) {
+	var waypoints []string
+	d := NewDirections(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		waypoints = r.URL.Query()["waypoints"]
+		w.Write([]byte(`{"code":"Ok","waypoints":[],"routes":[]}`))
+	}))
+
+	locs := []base.Location{
+		{Latitude: 37.78, Longitude: -122.42},
+		{Latitude: 37.785, Longitude: -122.41},
+		{Latitude: 37.79, Longitude: -122.40},
+		{Latitude: 37.80, Longitude: -122.39},
+	}
+
+	t.Run("Sends stops", func(t *testing.T) {
+		opts := RequestOpts{}
+		err := opts.SetPassThroughWaypoints(locs, []int{0, 2, 3})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		_, err = d.GetDirections(locs, RoutingDriving, &opts)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []string{"0;2;3"}, waypoints)
+	})
+
+	t.Run("Sends one name per stop", func(t *testing.T) {
+		opts := RequestOpts{WaypointNames: []string{"Home", "Cafe", "Work"}}
+		err := opts.SetPassThroughWaypoints(locs, []int{0, 2, 3})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		_, err = d.GetDirections(locs, RoutingDriving, &opts)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []string{"0;2;3"}, waypoints)
+
+		opts.WaypointNames = []string{"Home", "Corner", "Cafe", "Work"}
+		_, err = d.GetDirections(locs, RoutingDriving, &opts)
+		validationErr := &base.ValidationError{}
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.EqualValues(t, "waypoint_names", validationErr.Field)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		stops []int
+	}{
+		{"out of order", []int{0, 2, 1, 3}},
+		{"out of range", []int{0, 4}},
+		{"missing start", []int{1, 3}},
+		{"missing end", []int{0, 2}},
+		{"single stop", []int{0}},
+	}
+
+	for _, tt := range tests {
+		t.Run("Rejects stops "+tt.name, func(t *testing.T) {
+			opts := RequestOpts{}
+			err := opts.SetPassThroughWaypoints(locs, tt.stops)
+			validationErr := &base.ValidationError{}
+			if assert.True(t, errors.As(err, &validationErr)) {
+				assert.EqualValues(t, "waypoints", validationErr.Field)
+			}
+			assert.Nil(t, opts.Waypoints)
+
+			_, err = d.GetDirections(locs, RoutingDriving, &RequestOpts{Waypoints: tt.stops})
+			assert.True(t, errors.As(err, &validationErr))
+		})
+	}
+}
@@ -9,6 +9,16 @@
 
 package directions
 
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/polyline"
+)
+
 // DirectionResponse is the response from GetDirections
 // https://www.mapbox.com/api-documentation/#directions-response-object
 type DirectionResponse struct {
@@ -17,13 +27,251 @@ type DirectionResponse struct {
 	Routes    []Route
 }
 
+// UnmarshalJSON decodes a response, marking the first route as the primary route
+func (r *DirectionResponse) UnmarshalJSON(data []byte) error {
+	type response DirectionResponse
+	err := json.Unmarshal(data, (*response)(r))
+	if err != nil {
+		return err
+	}
+
+	if len(r.Routes) > 0 {
+		r.Routes[0].primary = true
+	}
+
+	return nil
+}
+
+// DecodeGeometries decodes the encoded polyline geometries of the routes and their steps into LineStrings,
+// with the precision of the geometry type the response was requested with (polyline where unspecified).
+// This is called by GetDirections, and is required for responses decoded by other means
+func (r *DirectionResponse) DecodeGeometries(geometries GeometryType) error {
+	precision := 5
+	switch geometries {
+	case GeometryGeoJSON:
+		return nil
+	case GeometryPolyline6:
+		precision = 6
+	}
+
+	for i := range r.Routes {
+		err := r.Routes[i].decodeGeometry(precision)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RouteCriteria selects how BestRoute compares routes
+type RouteCriteria string
+
+const (
+	// CriteriaTime selects the route with the shortest duration
+	CriteriaTime RouteCriteria = "time"
+	// CriteriaDistance selects the route with the shortest distance
+	CriteriaDistance RouteCriteria = "distance"
+)
+
+// BestRoute returns the route (including alternatives) with the shortest duration or distance
+// nil is returned where there are no routes or the criteria is unknown
+func (r *DirectionResponse) BestRoute(criteria RouteCriteria) *Route {
+	var best *Route
+	for i := range r.Routes {
+		route := &r.Routes[i]
+		switch {
+		case best == nil:
+			best = route
+		case criteria == CriteriaTime && route.Duration < best.Duration:
+			best = route
+		case criteria == CriteriaDistance && route.Distance < best.Distance:
+			best = route
+		}
+	}
+
+	if criteria != CriteriaTime && criteria != CriteriaDistance {
+		return nil
+	}
+
+	return best
+}
+
+// RoutesByDuration returns all routes (including alternatives) sorted from fastest to slowest
+// The Routes field retains the order returned by the API, in which the first route is recommended
+func (r *DirectionResponse) RoutesByDuration() []Route {
+	routes := make([]Route, len(r.Routes))
+	copy(routes, r.Routes)
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		return routes[i].Duration < routes[j].Duration
+	})
+
+	return routes
+}
+
 // Route A route through (potentially multiple) waypoints.
 // https://www.mapbox.com/api-documentation/#route-object
 type Route struct {
 	Distance float64
 	Duration float64
-	Geometry string
+	// Geometry is the route geometry, encoded polylines are decoded into a LineString by GetDirections
+	// or DirectionResponse.DecodeGeometries
+	Geometry base.Geometry
 	Legs     []RouteLeg
+
+	// EncodedGeometry is the encoded polyline geometry, where polyline or polyline6 geometries were requested
+	EncodedGeometry string `json:"-"`
+
+	// precision is the precision of the encoded polyline geometry, zero where unknown
+	precision int
+	// primary is set for the recommended (first) route of a response
+	primary bool
+}
+
+// IsPrimary indicates whether this is the recommended route, rather than an alternative
+func (r *Route) IsPrimary() bool {
+	return r.primary
+}
+
+// UnmarshalJSON decodes a route, keeping encoded polyline geometries in EncodedGeometry
+// as their precision depends on the requested geometry type
+func (r *Route) UnmarshalJSON(data []byte) error {
+	type route Route
+	raw := struct {
+		*route
+		Geometry json.RawMessage
+	}{route: (*route)(r)}
+
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	r.EncodedGeometry = ""
+	r.Geometry = base.Geometry{}
+
+	if len(raw.Geometry) == 0 || string(raw.Geometry) == "null" {
+		return nil
+	}
+	if raw.Geometry[0] == '"' {
+		return json.Unmarshal(raw.Geometry, &r.EncodedGeometry)
+	}
+
+	return json.Unmarshal(raw.Geometry, &r.Geometry)
+}
+
+// decodeGeometry decodes the encoded polyline geometries (if any) of the route and its steps with the provided precision
+func (r *Route) decodeGeometry(precision int) error {
+	for i := range r.Legs {
+		for j := range r.Legs[i].Steps {
+			err := r.Legs[i].Steps[j].decodeGeometry(precision)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if r.EncodedGeometry == "" {
+		return nil
+	}
+
+	r.precision = precision
+	locations, err := r.DecodeGeometry()
+	if err != nil {
+		return err
+	}
+
+	r.Geometry, err = lineStringGeometry(locations)
+
+	return err
+}
+
+// lineStringGeometry creates a LineString geometry from the provided locations
+func lineStringGeometry(locations []base.Location) (base.Geometry, error) {
+	coordinates := make([][]float64, len(locations))
+	for i, l := range locations {
+		coordinates[i] = []float64{l.Longitude, l.Latitude}
+	}
+
+	data, err := json.Marshal(coordinates)
+	if err != nil {
+		return base.Geometry{}, err
+	}
+
+	return base.Geometry{Type: "LineString", RawCoordinates: data}, nil
+}
+
+// DecodeGeometry returns the locations of the route geometry
+// Encoded polylines are decoded with the precision of the requested geometry type, so routes
+// not returned by GetDirections must first be decoded with DirectionResponse.DecodeGeometries
+func (r *Route) DecodeGeometry() ([]base.Location, error) {
+	if r.EncodedGeometry != "" {
+		if r.precision == 0 {
+			return nil, errors.New("Route polyline precision is unknown, see DirectionResponse.DecodeGeometries")
+		}
+		return polyline.Decode(r.EncodedGeometry, r.precision)
+	}
+
+	if r.Geometry.Type != "LineString" {
+		return nil, errors.New("Route has no LineString geometry")
+	}
+
+	coordinates := make([][]float64, 0)
+	err := json.Unmarshal(r.Geometry.RawCoordinates, &coordinates)
+	if err != nil {
+		return nil, err
+	}
+
+	locations := make([]base.Location, len(coordinates))
+	for i, c := range coordinates {
+		if len(c) < 2 {
+			return nil, errors.New("Route geometry has an invalid coordinate")
+		}
+		locations[i] = base.Location{Latitude: c[1], Longitude: c[0]}
+	}
+
+	return locations, nil
+}
+
+// Coordinates returns the locations of the route shape, whether polyline or GeoJSON geometries were requested
+// Encoded polylines are decoded as for DecodeGeometry
+func (r *Route) Coordinates() ([]base.Location, error) {
+	if r.EncodedGeometry != "" {
+		return r.DecodeGeometry()
+	}
+
+	line, err := r.Geometry.AsLineString()
+	if err != nil {
+		return nil, err
+	}
+
+	locations := make([]base.Location, len(line))
+	for i, p := range line {
+		locations[i] = p.Location()
+	}
+
+	return locations, nil
+}
+
+// GeoJSON returns the route geometry as a GeoJSON geometry object, regardless of the requested geometry format
+func (r *Route) GeoJSON() ([]byte, error) {
+	if r.Geometry.Type == "" && r.EncodedGeometry != "" {
+		locations, err := r.DecodeGeometry()
+		if err != nil {
+			return nil, err
+		}
+		geometry, err := lineStringGeometry(locations)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(&geometry)
+	}
+	if r.Geometry.Type == "" {
+		return nil, errors.New("Route has no geometry")
+	}
+
+	return json.Marshal(&r.Geometry)
 }
 
 // Waypoint is an input point snapped to the road network
@@ -44,25 +292,195 @@ type RouteLeg struct {
 }
 
 // Annotation conains additional details about each line segment
+// Each array has one entry per segment between consecutive coordinates of the leg geometry,
+// so is one shorter than the number of coordinates (and spans all steps of the leg)
+// Only the annotations requested with RequestOpts.Annotations are populated
 // https://www.mapbox.com/api-documentation/#routeleg-object
 type Annotation struct {
-	Distance []float64
-	Duration []float64
-	Speed    []float64
+	Distance   []float64 `json:"distance"`
+	Duration   []float64 `json:"duration"`
+	Speed      []float64 `json:"speed"`
+	Congestion []string  `json:"congestion"`
+	// CongestionNumeric is congestion from 0 (free flow) to 100, segments without data are zero
+	CongestionNumeric []int                `json:"congestion_numeric"`
+	MaxSpeed          []MaxSpeedAnnotation `json:"maxspeed"`
+}
+
+// MaxSpeedAnnotation is the posted speed limit of a segment
+type MaxSpeedAnnotation struct {
+	Speed float64 `json:"speed"`
+	Unit  string  `json:"unit"`
+	// Unknown indicates the speed limit is not known
+	Unknown bool `json:"unknown"`
+	// None indicates there is no speed limit
+	None bool `json:"none"`
 }
 
 // RouteStep Includes one StepManeuver object and travel to the following RouteStep.
 // https://www.mapbox.com/api-documentation/#routestep-object
 type RouteStep struct {
-	Distance      float64
-	Duration      float64
-	Geometry      string
+	Distance float64
+	Duration float64
+	// Geometry is the step geometry, encoded polylines are decoded into a LineString as for Route.Geometry
+	Geometry      base.Geometry
 	Name          string
 	Ref           string
 	Destinations  string
 	Mode          TransportationMode
 	Maneuver      StepManeuver
 	Intersections []Intersection
+	// BannerInstructions are only included with RequestOpts.BannerInstructions set
+	BannerInstructions []BannerInstruction `json:"bannerInstructions"`
+	// VoiceInstructions are only included with RequestOpts.VoiceInstructions set
+	VoiceInstructions []VoiceInstruction `json:"voiceInstructions"`
+
+	// EncodedGeometry is the encoded polyline geometry, where polyline or polyline6 geometries were requested
+	EncodedGeometry string `json:"-"`
+}
+
+// UnmarshalJSON decodes a step, keeping encoded polyline geometries in EncodedGeometry
+// as their precision depends on the requested geometry type
+func (s *RouteStep) UnmarshalJSON(data []byte) error {
+	type step RouteStep
+	raw := struct {
+		*step
+		Geometry json.RawMessage
+	}{step: (*step)(s)}
+
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	s.EncodedGeometry = ""
+	s.Geometry = base.Geometry{}
+
+	if len(raw.Geometry) == 0 || string(raw.Geometry) == "null" {
+		return nil
+	}
+	if raw.Geometry[0] == '"' {
+		return json.Unmarshal(raw.Geometry, &s.EncodedGeometry)
+	}
+
+	return json.Unmarshal(raw.Geometry, &s.Geometry)
+}
+
+// decodeGeometry decodes the encoded polyline geometry (if any) with the provided precision
+func (s *RouteStep) decodeGeometry(precision int) error {
+	if s.EncodedGeometry == "" {
+		return nil
+	}
+
+	locations, err := polyline.Decode(s.EncodedGeometry, precision)
+	if err != nil {
+		return err
+	}
+
+	s.Geometry, err = lineStringGeometry(locations)
+
+	return err
+}
+
+// BannerInstruction is visual guidance to be displayed from a point along a step
+// https://docs.mapbox.com/api/navigation/directions/#banner-instruction-object
+type BannerInstruction struct {
+	// DistanceAlongGeometry is the distance (in meters) before the end of the step at which to display the banner
+	DistanceAlongGeometry float64    `json:"distanceAlongGeometry"`
+	Primary               BannerText `json:"primary"`
+	// Secondary contains additional information, for example an exit destination
+	Secondary *BannerText `json:"secondary"`
+	// Sub contains the lane guidance or following maneuver
+	Sub *BannerText `json:"sub"`
+}
+
+// BannerText is the text and maneuver of a banner instruction
+type BannerText struct {
+	Text        string            `json:"text"`
+	Type        string            `json:"type"`
+	Modifier    StepModifier      `json:"modifier"`
+	Degrees     float64           `json:"degrees"`
+	DrivingSide string            `json:"driving_side"`
+	Components  []BannerComponent `json:"components"`
+}
+
+// BannerComponent is a part of a banner text, such as a road name, shield or lane
+type BannerComponent struct {
+	Text                 string   `json:"text"`
+	Type                 string   `json:"type"`
+	Abbreviation         string   `json:"abbr"`
+	AbbreviationPriority int      `json:"abbr_priority"`
+	ImageBaseURL         string   `json:"imageBaseURL"`
+	Directions           []string `json:"directions"`
+	Active               bool     `json:"active"`
+}
+
+// VoiceInstruction is spoken guidance to be announced from a point along a step
+// https://docs.mapbox.com/api/navigation/directions/#voice-instruction-object
+type VoiceInstruction struct {
+	// DistanceAlongGeometry is the distance (in meters) before the end of the step at which to announce the instruction
+	DistanceAlongGeometry float64 `json:"distanceAlongGeometry"`
+	Announcement          string  `json:"announcement"`
+	SSMLAnnouncement      string  `json:"ssmlAnnouncement"`
+}
+
+// Step is a single turn-by-turn step of a route leg
+type Step = RouteStep
+
+// Instructions returns the maneuver instructions of every step of the route in order
+// Steps are only included in responses to requests with RequestOpts.Steps set
+func (r *Route) Instructions() []string {
+	instructions := make([]string, 0)
+	for _, leg := range r.Legs {
+		for _, step := range leg.Steps {
+			if step.Maneuver.Instruction != "" {
+				instructions = append(instructions, step.Maneuver.Instruction)
+			}
+		}
+	}
+	return instructions
+}
+
+// TotalDistance returns the distance of the route in meters, summed across legs
+func (r *Route) TotalDistance() float64 {
+	distance := 0.0
+	for _, leg := range r.Legs {
+		distance += leg.Distance
+	}
+	return distance
+}
+
+// TotalDuration returns the duration of the route, summed across legs
+func (r *Route) TotalDuration() time.Duration {
+	duration := 0.0
+	for _, leg := range r.Legs {
+		duration += leg.Duration
+	}
+	return seconds(duration)
+}
+
+// DurationWithTraffic returns the route duration summed from the duration annotations of each leg,
+// which account for traffic with RoutingDrivingTraffic. False is returned where any leg has no
+// duration annotation, see AnnotationDuration
+func (r *Route) DurationWithTraffic() (time.Duration, bool) {
+	if len(r.Legs) == 0 {
+		return 0, false
+	}
+
+	duration := 0.0
+	for _, leg := range r.Legs {
+		if len(leg.Annotation.Duration) == 0 {
+			return 0, false
+		}
+		for _, d := range leg.Annotation.Duration {
+			duration += d
+		}
+	}
+	return seconds(duration), true
+}
+
+// seconds converts a duration in seconds to a time.Duration
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
 }
 
 // TransportationMode indicates the mode of transportation
@@ -98,14 +516,76 @@ type Lane struct {
 // StepManeuver
 // https://www.mapbox.com/api-documentation/#stepmaneuver-object
 type StepManeuver struct {
-	Location      []float64
-	BearingBefore float64
-	BearingAfter  float64
-	Instruction   string
-	Type          string
-	Modifier      StepModifier
+	Location      []float64    `json:"location"`
+	BearingBefore float64      `json:"bearing_before"`
+	BearingAfter  float64      `json:"bearing_after"`
+	Instruction   string       `json:"instruction"`
+	Type          ManeuverType `json:"type"`
+	Modifier      StepModifier `json:"modifier"`
+	Exit          int          `json:"exit"`
+}
+
+// IsLeftTurn indicates whether the maneuver changes direction to the left
+// Departures and arrivals are excluded, as their modifier indicates the side of the road
+func (m *StepManeuver) IsLeftTurn() bool {
+	if m.Type == ManeuverDepart || m.Type == ManeuverArrive {
+		return false
+	}
+	return m.Modifier == StepModifierLeft || m.Modifier == StepModifierSlightLeft || m.Modifier == StepModifierSharpLeft
+}
+
+// IsRightTurn indicates whether the maneuver changes direction to the right
+// Departures and arrivals are excluded, as their modifier indicates the side of the road
+func (m *StepManeuver) IsRightTurn() bool {
+	if m.Type == ManeuverDepart || m.Type == ManeuverArrive {
+		return false
+	}
+	return m.Modifier == StepModifierRight || m.Modifier == StepModifierSlightRight || m.Modifier == StepModifierSharpRight
 }
 
+// IsUTurn indicates whether the maneuver reverses direction
+func (m *StepManeuver) IsUTurn() bool {
+	return m.Modifier == StepModifierUTurn
+}
+
+// ManeuverType indicates the type of maneuver
+// https://docs.mapbox.com/api/navigation/directions/#maneuver-types
+type ManeuverType string
+
+const (
+	ManeuverTurn           ManeuverType = "turn"
+	ManeuverNewName        ManeuverType = "new name"
+	ManeuverDepart         ManeuverType = "depart"
+	ManeuverArrive         ManeuverType = "arrive"
+	ManeuverMerge          ManeuverType = "merge"
+	ManeuverOnRamp         ManeuverType = "on ramp"
+	ManeuverOffRamp        ManeuverType = "off ramp"
+	ManeuverFork           ManeuverType = "fork"
+	ManeuverEndOfRoad      ManeuverType = "end of road"
+	ManeuverContinue       ManeuverType = "continue"
+	ManeuverRoundabout     ManeuverType = "roundabout"
+	ManeuverRotary         ManeuverType = "rotary"
+	ManeuverRoundaboutTurn ManeuverType = "roundabout turn"
+	ManeuverNotification   ManeuverType = "notification"
+	ManeuverExitRoundabout ManeuverType = "exit roundabout"
+	ManeuverExitRotary     ManeuverType = "exit rotary"
+)
+
+// ManeuverModifier indicates the direction change of a maneuver
+// It is the same type as StepModifier, so the ManeuverModifier and StepModifier constants are interchangeable
+type ManeuverModifier = StepModifier
+
+const (
+	ManeuverModifierUTurn       ManeuverModifier = StepModifierUTurn
+	ManeuverModifierSharpRight  ManeuverModifier = StepModifierSharpRight
+	ManeuverModifierRight       ManeuverModifier = StepModifierRight
+	ManeuverModifierSlightRight ManeuverModifier = StepModifierSlightRight
+	ManeuverModifierStraight    ManeuverModifier = StepModifierStraight
+	ManeuverModifierSharpLeft   ManeuverModifier = StepModifierSharpLeft
+	ManeuverModifierLeft        ManeuverModifier = StepModifierLeft
+	ManeuverModifierSlightLeft  ManeuverModifier = StepModifierSlightLeft
+)
+
 // StepModifier indicates the direction change of the maneuver
 // https://www.mapbox.com/api-documentation/#stepmaneuver-object
 type StepModifier string
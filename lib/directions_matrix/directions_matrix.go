@@ -10,6 +10,8 @@
 package directionsmatrix
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -20,6 +22,11 @@ import (
 const (
 	apiName    = "directions-matrix"
 	apiVersion = "v1"
+
+	// MaxCoordinates is the maximum number of coordinates accepted in a single matrix request
+	MaxCoordinates = 25
+	// MaxCoordinatesTraffic is the maximum number of coordinates accepted using RoutingDrivingTraffic
+	MaxCoordinatesTraffic = 10
 )
 
 // DirectionsMatrix api wrapper instance
@@ -36,6 +43,8 @@ func NewDirectionsMatrix(base *base.Base) *DirectionsMatrix {
 type RoutingProfile string
 
 const (
+	// RoutingDrivingTraffic mode for automotive routing takes into account current and historic traffic
+	RoutingDrivingTraffic RoutingProfile = "mapbox/driving-traffic"
 	// RoutingDriving mode for for automovide routing
 	RoutingDriving RoutingProfile = "mapbox/driving"
 	// RoutingWalking mode for Pedestrian routing
@@ -48,11 +57,57 @@ const (
 // https://www.mapbox.com/api-documentation/#matrix-response-format
 type DirectionMatrixResponse struct {
 	Code         string
-	Durations    [][]float64
+	Durations    Matrix
+	Sources      []Waypoint
+	Destinations []Waypoint
+}
+
+// MatrixResponse is the response from Get
+// https://www.mapbox.com/api-documentation/#matrix-response-format
+type MatrixResponse struct {
+	Code         string
+	Durations    Matrix
+	Distances    Matrix
 	Sources      []Waypoint
 	Destinations []Waypoint
 }
 
+// Unroutable is the value of matrix entries for pairs that could not be routed
+// The API returns null for these entries unless a fallback speed is set, in which case
+// an estimate based on the straight line distance is returned instead
+const Unroutable float64 = -1
+
+// Matrix is a matrix of values with one row per source and one column per destination
+type Matrix [][]float64
+
+// UnmarshalJSON decodes a matrix, replacing null entries with Unroutable
+func (m *Matrix) UnmarshalJSON(data []byte) error {
+	raw := make([][]*float64, 0)
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	if raw == nil {
+		*m = nil
+		return nil
+	}
+
+	*m = make(Matrix, len(raw))
+	for i, row := range raw {
+		(*m)[i] = make([]float64, len(row))
+		for j, v := range row {
+			if v == nil {
+				(*m)[i][j] = Unroutable
+			} else {
+				(*m)[i][j] = *v
+			}
+		}
+	}
+
+	return nil
+}
+
 // Waypoint is an input point snapped to the road network
 // https://www.mapbox.com/api-documentation/#waypoint-object
 type Waypoint struct {
@@ -77,6 +132,9 @@ const (
 type RequestOpts struct {
 	Sources      string `url:"sources,omitempty"`
 	Destinations string `url:"destinations,omitempty"`
+	// FallbackSpeed (in km/h) is used to estimate durations for pairs that cannot be routed,
+	// where unset these pairs are Unroutable
+	FallbackSpeed float64 `url:"fallback_speed,omitempty"`
 }
 
 // SetSources The points which will act as the starting point.
@@ -125,3 +183,195 @@ func (d *DirectionsMatrix) GetDirectionsMatrix(locations []base.Location, profil
 
 	return &resp, err
 }
+
+// MatrixOpts request options for the matrix Get method
+type MatrixOpts struct {
+	// Profile is the routing profile to use, defaulting to RoutingDriving
+	Profile RoutingProfile `url:"-"`
+	// Sources is the subset of coordinate indices to use as sources, defaulting to all
+	Sources []int `url:"sources,omitempty,semicolon"`
+	// Destinations is the subset of coordinate indices to use as destinations, defaulting to all
+	Destinations []int `url:"destinations,omitempty,semicolon"`
+	// Annotations selects the returned matrices (duration, distance, speed), defaulting to duration
+	Annotations []string `url:"annotations,omitempty,comma"`
+	// FallbackSpeed (in km/h) is used to estimate values for pairs that cannot be routed,
+	// where unset these pairs are Unroutable
+	FallbackSpeed float64 `url:"fallback_speed,omitempty"`
+}
+
+// profile returns the selected routing profile or the default
+func (o *MatrixOpts) profile() RoutingProfile {
+	if o.Profile == "" {
+		return RoutingDriving
+	}
+	return o.Profile
+}
+
+// maxCoordinates returns the maximum number of coordinates for the selected routing profile
+func (o *MatrixOpts) maxCoordinates() int {
+	if o.profile() == RoutingDrivingTraffic {
+		return MaxCoordinatesTraffic
+	}
+	return MaxCoordinates
+}
+
+// MatrixSizeError is returned when a request exceeds the coordinate limit of the routing profile
+type MatrixSizeError struct {
+	Profile   RoutingProfile
+	Requested int
+	Max       int
+}
+
+func (e *MatrixSizeError) Error() string {
+	return fmt.Sprintf("Matrix requests using profile %s are limited to %d coordinates (received %d)", e.Profile, e.Max, e.Requested)
+}
+
+// validate checks the coordinates and indices against the API limits
+func (o *MatrixOpts) validate(coordinates []base.Location) error {
+	if len(coordinates) < 2 {
+		return &base.ValidationError{Field: "coordinates", Message: fmt.Sprintf("%d coordinates provided, at least 2 are required", len(coordinates))}
+	}
+	if max := o.maxCoordinates(); len(coordinates) > max {
+		return &MatrixSizeError{Profile: o.profile(), Requested: len(coordinates), Max: max}
+	}
+
+	fields := []string{"sources", "destinations"}
+	for f, indices := range [][]int{o.Sources, o.Destinations} {
+		for _, i := range indices {
+			if i < 0 || i >= len(coordinates) {
+				return &base.ValidationError{Field: fields[f], Message: fmt.Sprintf("index %d out of range (%d coordinates)", i, len(coordinates))}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Get fetches the duration (and optionally distance) matrix between a set of coordinates
+func (d *DirectionsMatrix) Get(ctx context.Context, coordinates []base.Location, opts *MatrixOpts) (*MatrixResponse, error) {
+	if opts == nil {
+		opts = &MatrixOpts{}
+	}
+
+	err := opts.validate(coordinates)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	coordinateStrings := make([]string, len(coordinates))
+	for i, l := range coordinates {
+		coordinateStrings[i] = fmt.Sprintf("%f,%f", l.Longitude, l.Latitude)
+	}
+	queryString := fmt.Sprintf("%s/%s/%s/%s", apiName, apiVersion, opts.profile(), strings.Join(coordinateStrings, ";"))
+
+	resp := MatrixResponse{}
+
+	err = d.base.QueryBaseContext(ctx, queryString, &v, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// GetMatrix fetches the matrix from each of the sources to each of the destinations using the specified routing profile
+// Sources and destinations are combined into a single coordinate list, overriding any indices set in the options,
+// so the resulting matrix has one row per source and one column per destination
+func (d *DirectionsMatrix) GetMatrix(sources, destinations []base.Location, profile RoutingProfile, opts *MatrixOpts) (*MatrixResponse, error) {
+	o := MatrixOpts{}
+	if opts != nil {
+		o = *opts
+	}
+	o.Profile = profile
+
+	return d.getMatrix(context.Background(), sources, destinations, &o)
+}
+
+// getMatrix fetches the matrix from each of the sources to each of the destinations
+func (d *DirectionsMatrix) getMatrix(ctx context.Context, sources, destinations []base.Location, opts *MatrixOpts) (*MatrixResponse, error) {
+	o := *opts
+
+	coordinates := make([]base.Location, 0, len(sources)+len(destinations))
+	coordinates = append(coordinates, sources...)
+	coordinates = append(coordinates, destinations...)
+
+	o.Sources = make([]int, len(sources))
+	for i := range sources {
+		o.Sources[i] = i
+	}
+	o.Destinations = make([]int, len(destinations))
+	for i := range destinations {
+		o.Destinations[i] = len(sources) + i
+	}
+
+	return d.Get(ctx, coordinates, &o)
+}
+
+// BatchMatrix fetches the duration matrix from each of the sources to each of the destinations,
+// splitting matrices that exceed the coordinate limit of the routing profile into multiple requests
+// The returned matrix has one row per source and one column per destination
+func (d *DirectionsMatrix) BatchMatrix(ctx context.Context, sources, destinations []base.Location, opts *MatrixOpts) ([][]float64, error) {
+	o := MatrixOpts{}
+	if opts != nil {
+		o = *opts
+	}
+	o.Annotations = []string{"duration"}
+
+	sourceChunk, destinationChunk := batchChunkSizes(o.maxCoordinates(), len(sources), len(destinations))
+
+	durations := make([][]float64, len(sources))
+	for i := range durations {
+		durations[i] = make([]float64, len(destinations))
+	}
+
+	for s := 0; s < len(sources); s += sourceChunk {
+		sourceEnd := minInt(s+sourceChunk, len(sources))
+
+		for t := 0; t < len(destinations); t += destinationChunk {
+			destinationEnd := minInt(t+destinationChunk, len(destinations))
+
+			resp, err := d.getMatrix(ctx, sources[s:sourceEnd], destinations[t:destinationEnd], &o)
+			if err != nil {
+				return nil, err
+			}
+			if len(resp.Durations) != sourceEnd-s {
+				return nil, fmt.Errorf("Matrix response has %d rows (expected %d)", len(resp.Durations), sourceEnd-s)
+			}
+
+			for i, row := range resp.Durations {
+				if len(row) != destinationEnd-t {
+					return nil, fmt.Errorf("Matrix response has %d columns (expected %d)", len(row), destinationEnd-t)
+				}
+				copy(durations[s+i][t:destinationEnd], row)
+			}
+		}
+	}
+
+	return durations, nil
+}
+
+// batchChunkSizes splits the coordinate limit between sources and destinations
+func batchChunkSizes(max, sources, destinations int) (int, int) {
+	switch {
+	case sources+destinations <= max:
+		return max, max
+	case sources < max/2:
+		return sources, max - sources
+	case destinations < max-max/2:
+		return max - destinations, destinations
+	default:
+		return max / 2, max - max/2
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
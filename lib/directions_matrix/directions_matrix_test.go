@@ -10,12 +10,21 @@
 package directionsmatrix
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 import (
 	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/internal/basetest"
 )
 
 func TestDirectionsMatrix(t *testing.T) {
@@ -49,3 +58,232 @@ func TestDirectionsMatrix(t *testing.T) {
 	})
 
 }
+
+const matrixResponse = `{
+	"code": "Ok",
+	"durations": [[0, 573.8], [579.1, 0]],
+	"distances": [[0, 2934.4], [2941.7, 0]],
+	"sources": [
+		{"name": "Mission Street", "location": [-122.418408, 37.751668]},
+		{"name": "22nd Street", "location": [-122.422959, 37.755184]}
+	],
+	"destinations": [
+		{"name": "Mission Street", "location": [-122.418408, 37.751668]},
+		{"name": "22nd Street", "location": [-122.422959, 37.755184]}
+	]
+}`
+
+func TestMatrixGet(t *testing.T) {
+
+	locs := []base.Location{
+		{Latitude: 37.751668, Longitude: -122.418408},
+		{Latitude: 37.755184, Longitude: -122.422959},
+	}
+
+	t.Run("Fetches a matrix", func(t *testing.T) {
+		b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.EqualValues(t, "/directions-matrix/v1/mapbox/walking/-122.418408,37.751668;-122.422959,37.755184", r.URL.Path)
+			assert.EqualValues(t, "0;1", r.URL.Query().Get("sources"))
+			assert.EqualValues(t, "duration,distance", r.URL.Query().Get("annotations"))
+			assert.EqualValues(t, "", r.URL.Query().Get("destinations"))
+			w.Write([]byte(matrixResponse))
+		})
+
+		opts := MatrixOpts{
+			Profile:     RoutingWalking,
+			Sources:     []int{0, 1},
+			Annotations: []string{"duration", "distance"},
+		}
+
+		res, err := NewDirectionsMatrix(b).Get(context.Background(), locs, &opts)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.EqualValues(t, CodeOK, res.Code)
+		assert.EqualValues(t, [][]float64{{0, 573.8}, {579.1, 0}}, res.Durations)
+		assert.EqualValues(t, [][]float64{{0, 2934.4}, {2941.7, 0}}, res.Distances)
+		assert.Len(t, res.Sources, 2)
+		assert.EqualValues(t, "22nd Street", res.Destinations[1].Name)
+	})
+
+	t.Run("Validates coordinates before querying", func(t *testing.T) {
+		b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("Unexpected request: %s", r.URL)
+		})
+		m := NewDirectionsMatrix(b)
+
+		many := make([]base.Location, MaxCoordinates+1)
+		_, err := m.Get(context.Background(), many, nil)
+		assert.NotNil(t, err)
+
+		_, err = m.Get(context.Background(), many[:MaxCoordinatesTraffic+1], &MatrixOpts{Profile: RoutingDrivingTraffic})
+		assert.NotNil(t, err)
+
+		_, err = m.Get(context.Background(), locs, &MatrixOpts{Destinations: []int{2}})
+		validationErr := &base.ValidationError{}
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.EqualValues(t, "destinations", validationErr.Field)
+		}
+
+		_, err = m.Get(context.Background(), locs[:1], nil)
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.EqualValues(t, "coordinates", validationErr.Field)
+		}
+	})
+}
+
+func TestGetMatrix(t *testing.T) {
+	b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.EqualValues(t, "/directions-matrix/v1/mapbox/driving/-122.418408,37.751668;-122.422959,37.755184;-122.426410,37.759680;-122.418408,37.751668;-122.422959,37.755184;-122.426410,37.759680", r.URL.Path)
+		assert.EqualValues(t, "0;1;2", r.URL.Query().Get("sources"))
+		assert.EqualValues(t, "3;4;5", r.URL.Query().Get("destinations"))
+		assert.EqualValues(t, "duration,distance", r.URL.Query().Get("annotations"))
+		w.Write([]byte(`{
+			"code": "Ok",
+			"durations": [[0, 573.8, 802.2], [579.1, 0, 421.5], [811.4, 430.9, 0]],
+			"distances": [[0, 2934.4, 4120.7], [2941.7, 0, 2210.3], [4150.2, 2231.8, 0]]
+		}`))
+	})
+
+	locs := []base.Location{
+		{Latitude: 37.751668, Longitude: -122.418408},
+		{Latitude: 37.755184, Longitude: -122.422959},
+		{Latitude: 37.759680, Longitude: -122.426410},
+	}
+
+	res, err := NewDirectionsMatrix(b).GetMatrix(locs, locs, RoutingDriving, &MatrixOpts{Annotations: []string{"duration", "distance"}})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	for _, matrix := range [][][]float64{res.Durations, res.Distances} {
+		if !assert.Len(t, matrix, 3) {
+			t.FailNow()
+		}
+		for i, row := range matrix {
+			if assert.Len(t, row, 3) {
+				assert.EqualValues(t, 0, row[i])
+			}
+		}
+	}
+}
+
+func TestFallbackSpeed(t *testing.T) {
+	b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fallback_speed") == "" {
+			w.Write([]byte(`{"code":"Ok","durations":[[0,null],[null,0]]}`))
+			return
+		}
+		assert.EqualValues(t, "50", r.URL.Query().Get("fallback_speed"))
+		w.Write([]byte(`{"code":"Ok","durations":[[0,1432.6],[1432.6,0]]}`))
+	})
+	m := NewDirectionsMatrix(b)
+
+	locs := []base.Location{
+		{Latitude: 37.751668, Longitude: -122.418408},
+		{Latitude: 37.825010, Longitude: -122.422959},
+	}
+
+	t.Run("Marks unroutable pairs", func(t *testing.T) {
+		res, err := m.Get(context.Background(), locs, nil)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, [][]float64{{0, Unroutable}, {Unroutable, 0}}, res.Durations)
+	})
+
+	t.Run("Estimates unroutable pairs with a fallback speed", func(t *testing.T) {
+		res, err := m.Get(context.Background(), locs, &MatrixOpts{FallbackSpeed: 50})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, [][]float64{{0, 1432.6}, {1432.6, 0}}, res.Durations)
+	})
+}
+
+func TestBatchMatrix(t *testing.T) {
+	grid := func(n int, lat float64) []base.Location {
+		locs := make([]base.Location, n)
+		for i := range locs {
+			locs[i] = base.Location{Latitude: lat, Longitude: float64(i)}
+		}
+		return locs
+	}
+
+	sources, destinations := grid(30, 1), grid(30, 2)
+
+	requests := 0
+	b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		coordinates := strings.Split(strings.TrimPrefix(r.URL.Path, "/directions-matrix/v1/mapbox/driving/"), ";")
+		assert.True(t, len(coordinates) <= MaxCoordinates)
+		assert.EqualValues(t, "duration", r.URL.Query().Get("annotations"))
+
+		// Durations encode the source and destination longitudes as source * 100 + destination
+		longitude := func(index string) float64 {
+			i, _ := strconv.Atoi(index)
+			lng, _ := strconv.ParseFloat(strings.Split(coordinates[i], ",")[0], 64)
+			return lng
+		}
+		durations := make([][]float64, 0)
+		for _, s := range strings.Split(r.URL.Query().Get("sources"), ";") {
+			row := make([]float64, 0)
+			for _, d := range strings.Split(r.URL.Query().Get("destinations"), ";") {
+				row = append(row, longitude(s)*100+longitude(d))
+			}
+			durations = append(durations, row)
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": "Ok", "durations": durations})
+	})
+	m := NewDirectionsMatrix(b)
+
+	t.Run("Assembles chunked matrices", func(t *testing.T) {
+		durations, err := m.BatchMatrix(context.Background(), sources, destinations, nil)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.EqualValues(t, 9, requests)
+		if !assert.Len(t, durations, 30) {
+			t.FailNow()
+		}
+		for i, row := range durations {
+			if !assert.Len(t, row, 30) {
+				t.FailNow()
+			}
+			for j, v := range row {
+				assert.EqualValues(t, i*100+j, v)
+			}
+		}
+	})
+
+	t.Run("Chunks within the coordinate limit", func(t *testing.T) {
+		tests := []struct {
+			max, sources, destinations int
+		}{
+			{25, 30, 30},
+			{25, 3, 100},
+			{25, 100, 2},
+			{10, 30, 30},
+			{25, 10, 10},
+		}
+		for _, tt := range tests {
+			s, d := batchChunkSizes(tt.max, tt.sources, tt.destinations)
+			assert.True(t, minInt(s, tt.sources)+minInt(d, tt.destinations) <= tt.max)
+			assert.True(t, s > 0 && d > 0)
+		}
+	})
+
+	t.Run("Returns size errors", func(t *testing.T) {
+		_, err := m.Get(context.Background(), grid(11, 0), &MatrixOpts{Profile: RoutingDrivingTraffic})
+		sizeErr := &MatrixSizeError{}
+		if assert.True(t, errors.As(err, &sizeErr)) {
+			assert.EqualValues(t, RoutingDrivingTraffic, sizeErr.Profile)
+			assert.EqualValues(t, 11, sizeErr.Requested)
+			assert.EqualValues(t, MaxCoordinatesTraffic, sizeErr.Max)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+/**
+ * go-mapbox Elevation Module
+ * Provides point elevations using the mapbox terrain tilesets
+ * See https://docs.mapbox.com/data/tilesets/reference/mapbox-terrain-v2/ for tileset information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package elevation
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/tilequery"
+)
+
+const (
+	// TerrainTileset is the vector terrain tileset queried for elevations
+	TerrainTileset = "mapbox.mapbox-terrain-v2"
+	// contourLayer is the tileset layer containing elevation contours
+	contourLayer = "contour"
+)
+
+// ErrNoElevation is returned when no terrain data is available at a location
+var ErrNoElevation = errors.New("No elevation data at location")
+
+// Elevation api wrapper instance
+type Elevation struct {
+	tilequery *tilequery.Tilequery
+}
+
+// NewElevation Create a new Elevation API wrapper
+func NewElevation(base *base.Base) *Elevation {
+	return &Elevation{tilequery.NewTilequery(base)}
+}
+
+// DecodeRGB decodes a Terrain-RGB pixel into an elevation in meters
+// https://docs.mapbox.com/data/tilesets/reference/mapbox-terrain-rgb-v1/
+func DecodeRGB(r, g, b uint8) float64 {
+	return -10000 + float64(uint32(r)*256*256+uint32(g)*256+uint32(b))*0.1
+}
+
+// GetElevation fetches the elevation (meters) at a location
+// Elevations are resolved to the highest contour containing the location, in 10m intervals
+func (e *Elevation) GetElevation(loc base.Location) (float64, error) {
+	res, err := e.tilequery.Query(TerrainTileset, loc, &tilequery.TilequeryOpts{
+		Limit:  tilequery.MaxLimit,
+		Layers: []string{contourLayer},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	elevation, found := math.Inf(-1), false
+	for _, f := range res.Features {
+		ele, ok := f.RawProperties["ele"].(float64)
+		if ok && ele > elevation {
+			elevation, found = ele, true
+		}
+	}
+
+	if !found {
+		return 0, ErrNoElevation
+	}
+
+	return elevation, nil
+}
+
+// GetElevations fetches the elevations (meters) at a list of locations, in the same order
+func (e *Elevation) GetElevations(locs []base.Location) ([]float64, error) {
+	elevations := make([]float64, len(locs))
+	for i, loc := range locs {
+		elevation, err := e.GetElevation(loc)
+		if err != nil {
+			return nil, err
+		}
+		elevations[i] = elevation
+	}
+
+	return elevations, nil
+}
@@ -0,0 +1,89 @@
+/**
+ * go-mapbox Elevation Module Tests
+ * Provides point elevations using the mapbox terrain tilesets
+ * See https://docs.mapbox.com/data/tilesets/reference/mapbox-terrain-v2/ for tileset information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package elevation
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/internal/basetest"
+)
+
+func TestDecodeRGB(t *testing.T) {
+	tests := []struct {
+		r, g, b   uint8
+		elevation float64
+	}{
+		{1, 134, 160, 0},
+		{0, 0, 0, -10000},
+		{1, 135, 19, 11.5},
+		{1, 161, 196, 694.8},
+		{1, 143, 20, 216.4},
+		{255, 255, 255, 1667721.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("Decodes %d,%d,%d", tt.r, tt.g, tt.b), func(t *testing.T) {
+			assert.InDelta(t, tt.elevation, DecodeRGB(tt.r, tt.g, tt.b), 1e-6)
+		})
+	}
+}
+
+func TestElevation(t *testing.T) {
+	b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.EqualValues(t, "contour", r.URL.Query().Get("layers"))
+		assert.EqualValues(t, "50", r.URL.Query().Get("limit"))
+
+		switch r.URL.Path {
+		case "/v4/mapbox.mapbox-terrain-v2/tilequery/-105.010080,39.750030.json":
+			w.Write([]byte(`{"type":"FeatureCollection","features":[
+				{"type":"Feature","id":1,"geometry":{"type":"Point","coordinates":[-105.01008,39.75003]},"properties":{"ele":1580,"index":10,"tilequery":{"distance":0,"geometry":"polygon","layer":"contour"}}},
+				{"type":"Feature","id":2,"geometry":{"type":"Point","coordinates":[-105.01008,39.75003]},"properties":{"ele":1600,"index":5,"tilequery":{"distance":0,"geometry":"polygon","layer":"contour"}}},
+				{"type":"Feature","id":3,"geometry":{"type":"Point","coordinates":[-105.01008,39.75003]},"properties":{"ele":1590,"index":1,"tilequery":{"distance":0,"geometry":"polygon","layer":"contour"}}}
+			]}`))
+		case "/v4/mapbox.mapbox-terrain-v2/tilequery/-105.270500,40.015000.json":
+			w.Write([]byte(`{"type":"FeatureCollection","features":[
+				{"type":"Feature","id":4,"geometry":{"type":"Point","coordinates":[-105.2705,40.015]},"properties":{"ele":1650,"index":5,"tilequery":{"distance":0,"geometry":"polygon","layer":"contour"}}}
+			]}`))
+		default:
+			w.Write([]byte(`{"type":"FeatureCollection","features":[]}`))
+		}
+	})
+	e := NewElevation(b)
+
+	denver := base.Location{Latitude: 39.75003, Longitude: -105.01008}
+	boulder := base.Location{Latitude: 40.015, Longitude: -105.2705}
+
+	t.Run("Gets the highest contour elevation", func(t *testing.T) {
+		elevation, err := e.GetElevation(denver)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, 1600, elevation)
+	})
+
+	t.Run("Gets batch elevations in order", func(t *testing.T) {
+		elevations, err := e.GetElevations([]base.Location{boulder, denver})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, []float64{1650, 1600}, elevations)
+	})
+
+	t.Run("Returns an error without terrain data", func(t *testing.T) {
+		_, err := e.GetElevations([]base.Location{denver, {Latitude: 0, Longitude: -140}})
+		assert.True(t, errors.Is(err, ErrNoElevation))
+	})
+}
@@ -0,0 +1,293 @@
+/**
+ * go-mapbox Geocoding Module Batch Requests
+ * Wraps the mapbox batch geocoding API for server side use
+ * See https://docs.mapbox.com/api/search/geocoding/#batch-geocoding for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package geocode
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-querystring/query"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const (
+	apiBatchName    = "search/geocode"
+	apiBatchVersion = "v6"
+	apiBatchMode    = "batch"
+
+	// MaxBatchQueries is the maximum number of queries accepted in a single batch request
+	MaxBatchQueries = 1000
+)
+
+// BatchQuery is a single forward or reverse query within a batch request
+// Forward queries set Q, reverse queries set Longitude and Latitude
+type BatchQuery struct {
+	Q            string           `json:"q,omitempty"`
+	Longitude    *float64         `json:"longitude,omitempty"`
+	Latitude     *float64         `json:"latitude,omitempty"`
+	Types        Types            `json:"types,omitempty"`
+	Limit        uint             `json:"limit,omitempty"`
+	Country      string           `json:"country,omitempty"`
+	Language     Language         `json:"language,omitempty"`
+	Worldview    Worldview        `json:"worldview,omitempty"`
+	BBox         base.BoundingBox `json:"bbox,omitempty"`
+	Proximity    []float64        `json:"proximity,omitempty"`
+	Autocomplete bool             `json:"autocomplete,omitempty"`
+}
+
+// BatchRequestOpts request options for batch geocoding
+type BatchRequestOpts struct {
+	Permanent bool `url:"permanent,omitempty"`
+	// DisableAutoChunk sends oversized batches in a single request rather than splitting them
+	DisableAutoChunk bool `url:"-"`
+}
+
+// BatchResponse is the response to a batch geocode request
+// Batch contains one FeatureCollection per query, in the order the queries were provided
+type BatchResponse struct {
+	Batch []base.FeatureCollection `json:"batch"`
+}
+
+// BatchError indicates a range of queries within a batch could not be completed
+// Start and End are the (inclusive, exclusive) indices of the failed queries
+type BatchError struct {
+	Start int
+	End   int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("Batch geocode of queries %d to %d failed (%s)", e.Start, e.End, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// Batch geocode forward or reverse queries
+// Batches of more than MaxBatchQueries are split into multiple requests, dispatched sequentially
+// (or concurrently where base.WithMaxConcurrency is set), with results returned in query order.
+// Set DisableAutoChunk to send the batch in a single request and receive the API error instead.
+func (g *Geocode) Batch(ctx context.Context, queries []BatchQuery, opts *BatchRequestOpts) (*BatchResponse, error) {
+	if opts == nil {
+		opts = &BatchRequestOpts{}
+	}
+
+	if len(queries) <= MaxBatchQueries || opts.DisableAutoChunk {
+		if err := validateBatch(queries); err != nil {
+			return nil, err
+		}
+		return g.batch(ctx, queries, opts)
+	}
+	if n := g.base.MaxConcurrency(); n > 0 {
+		return g.BatchConcurrent(ctx, queries, opts, n)
+	}
+	return g.BatchAll(ctx, queries, opts)
+}
+
+// validateBatch checks each query in a batch before any requests are sent
+func validateBatch(queries []BatchQuery) error {
+	for i := range queries {
+		if err := queries[i].Worldview.Validate(); err != nil {
+			return fmt.Errorf("Batch query %d invalid (%w)", i, err)
+		}
+	}
+	return nil
+}
+
+// batch sends the queries in a single batch request
+func (g *Geocode) batch(ctx context.Context, queries []BatchQuery, opts *BatchRequestOpts) (*BatchResponse, error) {
+	if opts == nil {
+		opts = &BatchRequestOpts{}
+	}
+
+	v, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := BatchResponse{}
+
+	queryString := fmt.Sprintf("%s/%s/%s", apiBatchName, apiBatchVersion, apiBatchMode)
+
+	err = g.base.QueryWithBodyBase(ctx, http.MethodPost, queryString, &v, queries, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// ForwardBatch forward geocodes places using batch requests
+// The common options are applied to every query, the batch options (which may be nil) to the request,
+// and results are returned in place order. Options not supported by batch queries (IPProximity,
+// FuzzyMatch, Routing and SessionToken) are rejected, as are more than MaxBatchQueries places where
+// auto chunking is disabled
+func (g *Geocode) ForwardBatch(ctx context.Context, places []string, opts *ForwardRequestOpts, batchOpts *BatchRequestOpts) (*BatchResponse, error) {
+	if len(places) == 0 {
+		return nil, &base.ValidationError{Field: "places", Message: "at least one place is required"}
+	}
+	if batchOpts != nil && batchOpts.DisableAutoChunk && len(places) > MaxBatchQueries {
+		return nil, &base.ValidationError{Field: "places", Message: fmt.Sprintf("%d places provided, maximum %d without auto chunking", len(places), MaxBatchQueries)}
+	}
+	if opts == nil {
+		opts = &ForwardRequestOpts{}
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	unsupported := []struct {
+		field string
+		set   bool
+	}{
+		{"proximity", opts.IPProximity},
+		{"fuzzyMatch", opts.FuzzyMatch},
+		{"routing", opts.Routing},
+		{"session_token", opts.SessionToken != ""},
+	}
+	for _, u := range unsupported {
+		if u.set {
+			return nil, &base.ValidationError{Field: u.field, Message: "not supported by batch queries"}
+		}
+	}
+
+	queries := make([]BatchQuery, len(places))
+	for i, place := range places {
+		queries[i] = BatchQuery{
+			Q:            place,
+			Types:        opts.Types,
+			Limit:        opts.Limit,
+			Country:      opts.Country,
+			Language:     opts.Language,
+			Worldview:    opts.Worldview,
+			BBox:         opts.BBox,
+			Proximity:    opts.Proximity,
+			Autocomplete: opts.Autocomplete,
+		}
+	}
+
+	return g.Batch(ctx, queries, batchOpts)
+}
+
+// ReverseBatch reverse geocodes locations using batch requests
+// The common options are applied to every query, the batch options (which may be nil) to the request,
+// and results are returned in location order
+func (g *Geocode) ReverseBatch(ctx context.Context, locs []base.Location, opts *ReverseRequestOpts, batchOpts *BatchRequestOpts) (*BatchResponse, error) {
+	if len(locs) == 0 {
+		return nil, &base.ValidationError{Field: "locations", Message: "at least one location is required"}
+	}
+	if opts == nil {
+		opts = &ReverseRequestOpts{}
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	queries := make([]BatchQuery, len(locs))
+	for i := range locs {
+		queries[i] = BatchQuery{
+			Longitude: &locs[i].Longitude,
+			Latitude:  &locs[i].Latitude,
+			Types:     opts.Types,
+			Limit:     opts.Limit,
+			Country:   opts.Country,
+			Language:  opts.Language,
+			Worldview: opts.Worldview,
+		}
+	}
+
+	return g.Batch(ctx, queries, batchOpts)
+}
+
+// BatchAll geocodes an arbitrary number of queries, splitting them into sequential batch requests
+// If a batch request fails the results of all preceding requests are returned along with a
+// BatchError identifying the range of queries that failed, subsequent queries are not attempted
+func (g *Geocode) BatchAll(ctx context.Context, queries []BatchQuery, opts *BatchRequestOpts) (*BatchResponse, error) {
+	if err := validateBatch(queries); err != nil {
+		return nil, err
+	}
+
+	merged := BatchResponse{Batch: make([]base.FeatureCollection, 0, len(queries))}
+
+	for start := 0; start < len(queries); start += MaxBatchQueries {
+		end := start + MaxBatchQueries
+		if end > len(queries) {
+			end = len(queries)
+		}
+
+		resp, err := g.batch(ctx, queries[start:end], opts)
+		if err == nil && len(resp.Batch) != end-start {
+			err = fmt.Errorf("Unexpected number of results (expected %d received %d)", end-start, len(resp.Batch))
+		}
+		if err != nil {
+			return &merged, &BatchError{Start: start, End: end, Err: err}
+		}
+
+		merged.Batch = append(merged.Batch, resp.Batch...)
+	}
+
+	return &merged, nil
+}
+
+// BatchConcurrent geocodes an arbitrary number of queries, splitting them into batch requests
+// and dispatching up to concurrency requests in parallel. Results are returned in query order.
+// The first failing request cancels any outstanding requests and is returned as a BatchError
+func (g *Geocode) BatchConcurrent(ctx context.Context, queries []BatchQuery, opts *BatchRequestOpts, concurrency int) (*BatchResponse, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if err := validateBatch(queries); err != nil {
+		return nil, err
+	}
+
+	chunks := (len(queries) + MaxBatchQueries - 1) / MaxBatchQueries
+	results := make([][]base.FeatureCollection, chunks)
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(concurrency)
+
+	for i := 0; i < chunks; i++ {
+		i := i
+		start := i * MaxBatchQueries
+		end := start + MaxBatchQueries
+		if end > len(queries) {
+			end = len(queries)
+		}
+
+		group.Go(func() error {
+			resp, err := g.batch(groupCtx, queries[start:end], opts)
+			if err == nil && len(resp.Batch) != end-start {
+				err = fmt.Errorf("Unexpected number of results (expected %d received %d)", end-start, len(resp.Batch))
+			}
+			if err != nil {
+				return &BatchError{Start: start, End: end, Err: err}
+			}
+
+			results[i] = resp.Batch
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	merged := BatchResponse{Batch: make([]base.FeatureCollection, 0, len(queries))}
+	for _, r := range results {
+		merged.Batch = append(merged.Batch, r...)
+	}
+
+	return &merged, nil
+}
@@ -0,0 +1,795 @@
+/**
+ * go-mapbox Geocoding Module Countries
+ * Defines ISO 3166-1 alpha-2 country codes used to filter geocoding results
+ * See https://docs.mapbox.com/api/search/geocoding/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package geocode
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CountryCode is an ISO 3166-1 alpha-2 country code
+type CountryCode string
+
+// ISO 3166-1 alpha-2 country codes
+const (
+	// CountryAD Andorra
+	CountryAD CountryCode = "ad"
+	// CountryAE United Arab Emirates
+	CountryAE CountryCode = "ae"
+	// CountryAF Afghanistan
+	CountryAF CountryCode = "af"
+	// CountryAG Antigua and Barbuda
+	CountryAG CountryCode = "ag"
+	// CountryAI Anguilla
+	CountryAI CountryCode = "ai"
+	// CountryAL Albania
+	CountryAL CountryCode = "al"
+	// CountryAM Armenia
+	CountryAM CountryCode = "am"
+	// CountryAO Angola
+	CountryAO CountryCode = "ao"
+	// CountryAQ Antarctica
+	CountryAQ CountryCode = "aq"
+	// CountryAR Argentina
+	CountryAR CountryCode = "ar"
+	// CountryAS American Samoa
+	CountryAS CountryCode = "as"
+	// CountryAT Austria
+	CountryAT CountryCode = "at"
+	// CountryAU Australia
+	CountryAU CountryCode = "au"
+	// CountryAW Aruba
+	CountryAW CountryCode = "aw"
+	// CountryAX Aland Islands
+	CountryAX CountryCode = "ax"
+	// CountryAZ Azerbaijan
+	CountryAZ CountryCode = "az"
+	// CountryBA Bosnia and Herzegovina
+	CountryBA CountryCode = "ba"
+	// CountryBB Barbados
+	CountryBB CountryCode = "bb"
+	// CountryBD Bangladesh
+	CountryBD CountryCode = "bd"
+	// CountryBE Belgium
+	CountryBE CountryCode = "be"
+	// CountryBF Burkina Faso
+	CountryBF CountryCode = "bf"
+	// CountryBG Bulgaria
+	CountryBG CountryCode = "bg"
+	// CountryBH Bahrain
+	CountryBH CountryCode = "bh"
+	// CountryBI Burundi
+	CountryBI CountryCode = "bi"
+	// CountryBJ Benin
+	CountryBJ CountryCode = "bj"
+	// CountryBL Saint Barthelemy
+	CountryBL CountryCode = "bl"
+	// CountryBM Bermuda
+	CountryBM CountryCode = "bm"
+	// CountryBN Brunei Darussalam
+	CountryBN CountryCode = "bn"
+	// CountryBO Bolivia
+	CountryBO CountryCode = "bo"
+	// CountryBQ Bonaire, Sint Eustatius and Saba
+	CountryBQ CountryCode = "bq"
+	// CountryBR Brazil
+	CountryBR CountryCode = "br"
+	// CountryBS Bahamas
+	CountryBS CountryCode = "bs"
+	// CountryBT Bhutan
+	CountryBT CountryCode = "bt"
+	// CountryBV Bouvet Island
+	CountryBV CountryCode = "bv"
+	// CountryBW Botswana
+	CountryBW CountryCode = "bw"
+	// CountryBY Belarus
+	CountryBY CountryCode = "by"
+	// CountryBZ Belize
+	CountryBZ CountryCode = "bz"
+	// CountryCA Canada
+	CountryCA CountryCode = "ca"
+	// CountryCC Cocos (Keeling) Islands
+	CountryCC CountryCode = "cc"
+	// CountryCD Congo, Democratic Republic of the
+	CountryCD CountryCode = "cd"
+	// CountryCF Central African Republic
+	CountryCF CountryCode = "cf"
+	// CountryCG Congo
+	CountryCG CountryCode = "cg"
+	// CountryCH Switzerland
+	CountryCH CountryCode = "ch"
+	// CountryCI Cote d'Ivoire
+	CountryCI CountryCode = "ci"
+	// CountryCK Cook Islands
+	CountryCK CountryCode = "ck"
+	// CountryCL Chile
+	CountryCL CountryCode = "cl"
+	// CountryCM Cameroon
+	CountryCM CountryCode = "cm"
+	// CountryCN China
+	CountryCN CountryCode = "cn"
+	// CountryCO Colombia
+	CountryCO CountryCode = "co"
+	// CountryCR Costa Rica
+	CountryCR CountryCode = "cr"
+	// CountryCU Cuba
+	CountryCU CountryCode = "cu"
+	// CountryCV Cabo Verde
+	CountryCV CountryCode = "cv"
+	// CountryCW Curacao
+	CountryCW CountryCode = "cw"
+	// CountryCX Christmas Island
+	CountryCX CountryCode = "cx"
+	// CountryCY Cyprus
+	CountryCY CountryCode = "cy"
+	// CountryCZ Czechia
+	CountryCZ CountryCode = "cz"
+	// CountryDE Germany
+	CountryDE CountryCode = "de"
+	// CountryDJ Djibouti
+	CountryDJ CountryCode = "dj"
+	// CountryDK Denmark
+	CountryDK CountryCode = "dk"
+	// CountryDM Dominica
+	CountryDM CountryCode = "dm"
+	// CountryDO Dominican Republic
+	CountryDO CountryCode = "do"
+	// CountryDZ Algeria
+	CountryDZ CountryCode = "dz"
+	// CountryEC Ecuador
+	CountryEC CountryCode = "ec"
+	// CountryEE Estonia
+	CountryEE CountryCode = "ee"
+	// CountryEG Egypt
+	CountryEG CountryCode = "eg"
+	// CountryEH Western Sahara
+	CountryEH CountryCode = "eh"
+	// CountryER Eritrea
+	CountryER CountryCode = "er"
+	// CountryES Spain
+	CountryES CountryCode = "es"
+	// CountryET Ethiopia
+	CountryET CountryCode = "et"
+	// CountryFI Finland
+	CountryFI CountryCode = "fi"
+	// CountryFJ Fiji
+	CountryFJ CountryCode = "fj"
+	// CountryFK Falkland Islands (Malvinas)
+	CountryFK CountryCode = "fk"
+	// CountryFM Micronesia
+	CountryFM CountryCode = "fm"
+	// CountryFO Faroe Islands
+	CountryFO CountryCode = "fo"
+	// CountryFR France
+	CountryFR CountryCode = "fr"
+	// CountryGA Gabon
+	CountryGA CountryCode = "ga"
+	// CountryGB United Kingdom
+	CountryGB CountryCode = "gb"
+	// CountryGD Grenada
+	CountryGD CountryCode = "gd"
+	// CountryGE Georgia
+	CountryGE CountryCode = "ge"
+	// CountryGF French Guiana
+	CountryGF CountryCode = "gf"
+	// CountryGG Guernsey
+	CountryGG CountryCode = "gg"
+	// CountryGH Ghana
+	CountryGH CountryCode = "gh"
+	// CountryGI Gibraltar
+	CountryGI CountryCode = "gi"
+	// CountryGL Greenland
+	CountryGL CountryCode = "gl"
+	// CountryGM Gambia
+	CountryGM CountryCode = "gm"
+	// CountryGN Guinea
+	CountryGN CountryCode = "gn"
+	// CountryGP Guadeloupe
+	CountryGP CountryCode = "gp"
+	// CountryGQ Equatorial Guinea
+	CountryGQ CountryCode = "gq"
+	// CountryGR Greece
+	CountryGR CountryCode = "gr"
+	// CountryGS South Georgia and the South Sandwich Islands
+	CountryGS CountryCode = "gs"
+	// CountryGT Guatemala
+	CountryGT CountryCode = "gt"
+	// CountryGU Guam
+	CountryGU CountryCode = "gu"
+	// CountryGW Guinea-Bissau
+	CountryGW CountryCode = "gw"
+	// CountryGY Guyana
+	CountryGY CountryCode = "gy"
+	// CountryHK Hong Kong
+	CountryHK CountryCode = "hk"
+	// CountryHM Heard Island and McDonald Islands
+	CountryHM CountryCode = "hm"
+	// CountryHN Honduras
+	CountryHN CountryCode = "hn"
+	// CountryHR Croatia
+	CountryHR CountryCode = "hr"
+	// CountryHT Haiti
+	CountryHT CountryCode = "ht"
+	// CountryHU Hungary
+	CountryHU CountryCode = "hu"
+	// CountryID Indonesia
+	CountryID CountryCode = "id"
+	// CountryIE Ireland
+	CountryIE CountryCode = "ie"
+	// CountryIL Israel
+	CountryIL CountryCode = "il"
+	// CountryIM Isle of Man
+	CountryIM CountryCode = "im"
+	// CountryIN India
+	CountryIN CountryCode = "in"
+	// CountryIO British Indian Ocean Territory
+	CountryIO CountryCode = "io"
+	// CountryIQ Iraq
+	CountryIQ CountryCode = "iq"
+	// CountryIR Iran
+	CountryIR CountryCode = "ir"
+	// CountryIS Iceland
+	CountryIS CountryCode = "is"
+	// CountryIT Italy
+	CountryIT CountryCode = "it"
+	// CountryJE Jersey
+	CountryJE CountryCode = "je"
+	// CountryJM Jamaica
+	CountryJM CountryCode = "jm"
+	// CountryJO Jordan
+	CountryJO CountryCode = "jo"
+	// CountryJP Japan
+	CountryJP CountryCode = "jp"
+	// CountryKE Kenya
+	CountryKE CountryCode = "ke"
+	// CountryKG Kyrgyzstan
+	CountryKG CountryCode = "kg"
+	// CountryKH Cambodia
+	CountryKH CountryCode = "kh"
+	// CountryKI Kiribati
+	CountryKI CountryCode = "ki"
+	// CountryKM Comoros
+	CountryKM CountryCode = "km"
+	// CountryKN Saint Kitts and Nevis
+	CountryKN CountryCode = "kn"
+	// CountryKP Korea, Democratic People's Republic of
+	CountryKP CountryCode = "kp"
+	// CountryKR Korea, Republic of
+	CountryKR CountryCode = "kr"
+	// CountryKW Kuwait
+	CountryKW CountryCode = "kw"
+	// CountryKY Cayman Islands
+	CountryKY CountryCode = "ky"
+	// CountryKZ Kazakhstan
+	CountryKZ CountryCode = "kz"
+	// CountryLA Lao People's Democratic Republic
+	CountryLA CountryCode = "la"
+	// CountryLB Lebanon
+	CountryLB CountryCode = "lb"
+	// CountryLC Saint Lucia
+	CountryLC CountryCode = "lc"
+	// CountryLI Liechtenstein
+	CountryLI CountryCode = "li"
+	// CountryLK Sri Lanka
+	CountryLK CountryCode = "lk"
+	// CountryLR Liberia
+	CountryLR CountryCode = "lr"
+	// CountryLS Lesotho
+	CountryLS CountryCode = "ls"
+	// CountryLT Lithuania
+	CountryLT CountryCode = "lt"
+	// CountryLU Luxembourg
+	CountryLU CountryCode = "lu"
+	// CountryLV Latvia
+	CountryLV CountryCode = "lv"
+	// CountryLY Libya
+	CountryLY CountryCode = "ly"
+	// CountryMA Morocco
+	CountryMA CountryCode = "ma"
+	// CountryMC Monaco
+	CountryMC CountryCode = "mc"
+	// CountryMD Moldova
+	CountryMD CountryCode = "md"
+	// CountryME Montenegro
+	CountryME CountryCode = "me"
+	// CountryMF Saint Martin (French part)
+	CountryMF CountryCode = "mf"
+	// CountryMG Madagascar
+	CountryMG CountryCode = "mg"
+	// CountryMH Marshall Islands
+	CountryMH CountryCode = "mh"
+	// CountryMK North Macedonia
+	CountryMK CountryCode = "mk"
+	// CountryML Mali
+	CountryML CountryCode = "ml"
+	// CountryMM Myanmar
+	CountryMM CountryCode = "mm"
+	// CountryMN Mongolia
+	CountryMN CountryCode = "mn"
+	// CountryMO Macao
+	CountryMO CountryCode = "mo"
+	// CountryMP Northern Mariana Islands
+	CountryMP CountryCode = "mp"
+	// CountryMQ Martinique
+	CountryMQ CountryCode = "mq"
+	// CountryMR Mauritania
+	CountryMR CountryCode = "mr"
+	// CountryMS Montserrat
+	CountryMS CountryCode = "ms"
+	// CountryMT Malta
+	CountryMT CountryCode = "mt"
+	// CountryMU Mauritius
+	CountryMU CountryCode = "mu"
+	// CountryMV Maldives
+	CountryMV CountryCode = "mv"
+	// CountryMW Malawi
+	CountryMW CountryCode = "mw"
+	// CountryMX Mexico
+	CountryMX CountryCode = "mx"
+	// CountryMY Malaysia
+	CountryMY CountryCode = "my"
+	// CountryMZ Mozambique
+	CountryMZ CountryCode = "mz"
+	// CountryNA Namibia
+	CountryNA CountryCode = "na"
+	// CountryNC New Caledonia
+	CountryNC CountryCode = "nc"
+	// CountryNE Niger
+	CountryNE CountryCode = "ne"
+	// CountryNF Norfolk Island
+	CountryNF CountryCode = "nf"
+	// CountryNG Nigeria
+	CountryNG CountryCode = "ng"
+	// CountryNI Nicaragua
+	CountryNI CountryCode = "ni"
+	// CountryNL Netherlands
+	CountryNL CountryCode = "nl"
+	// CountryNO Norway
+	CountryNO CountryCode = "no"
+	// CountryNP Nepal
+	CountryNP CountryCode = "np"
+	// CountryNR Nauru
+	CountryNR CountryCode = "nr"
+	// CountryNU Niue
+	CountryNU CountryCode = "nu"
+	// CountryNZ New Zealand
+	CountryNZ CountryCode = "nz"
+	// CountryOM Oman
+	CountryOM CountryCode = "om"
+	// CountryPA Panama
+	CountryPA CountryCode = "pa"
+	// CountryPE Peru
+	CountryPE CountryCode = "pe"
+	// CountryPF French Polynesia
+	CountryPF CountryCode = "pf"
+	// CountryPG Papua New Guinea
+	CountryPG CountryCode = "pg"
+	// CountryPH Philippines
+	CountryPH CountryCode = "ph"
+	// CountryPK Pakistan
+	CountryPK CountryCode = "pk"
+	// CountryPL Poland
+	CountryPL CountryCode = "pl"
+	// CountryPM Saint Pierre and Miquelon
+	CountryPM CountryCode = "pm"
+	// CountryPN Pitcairn
+	CountryPN CountryCode = "pn"
+	// CountryPR Puerto Rico
+	CountryPR CountryCode = "pr"
+	// CountryPS Palestine, State of
+	CountryPS CountryCode = "ps"
+	// CountryPT Portugal
+	CountryPT CountryCode = "pt"
+	// CountryPW Palau
+	CountryPW CountryCode = "pw"
+	// CountryPY Paraguay
+	CountryPY CountryCode = "py"
+	// CountryQA Qatar
+	CountryQA CountryCode = "qa"
+	// CountryRE Reunion
+	CountryRE CountryCode = "re"
+	// CountryRO Romania
+	CountryRO CountryCode = "ro"
+	// CountryRS Serbia
+	CountryRS CountryCode = "rs"
+	// CountryRU Russian Federation
+	CountryRU CountryCode = "ru"
+	// CountryRW Rwanda
+	CountryRW CountryCode = "rw"
+	// CountrySA Saudi Arabia
+	CountrySA CountryCode = "sa"
+	// CountrySB Solomon Islands
+	CountrySB CountryCode = "sb"
+	// CountrySC Seychelles
+	CountrySC CountryCode = "sc"
+	// CountrySD Sudan
+	CountrySD CountryCode = "sd"
+	// CountrySE Sweden
+	CountrySE CountryCode = "se"
+	// CountrySG Singapore
+	CountrySG CountryCode = "sg"
+	// CountrySH Saint Helena, Ascension and Tristan da Cunha
+	CountrySH CountryCode = "sh"
+	// CountrySI Slovenia
+	CountrySI CountryCode = "si"
+	// CountrySJ Svalbard and Jan Mayen
+	CountrySJ CountryCode = "sj"
+	// CountrySK Slovakia
+	CountrySK CountryCode = "sk"
+	// CountrySL Sierra Leone
+	CountrySL CountryCode = "sl"
+	// CountrySM San Marino
+	CountrySM CountryCode = "sm"
+	// CountrySN Senegal
+	CountrySN CountryCode = "sn"
+	// CountrySO Somalia
+	CountrySO CountryCode = "so"
+	// CountrySR Suriname
+	CountrySR CountryCode = "sr"
+	// CountrySS South Sudan
+	CountrySS CountryCode = "ss"
+	// CountryST Sao Tome and Principe
+	CountryST CountryCode = "st"
+	// CountrySV El Salvador
+	CountrySV CountryCode = "sv"
+	// CountrySX Sint Maarten (Dutch part)
+	CountrySX CountryCode = "sx"
+	// CountrySY Syrian Arab Republic
+	CountrySY CountryCode = "sy"
+	// CountrySZ Eswatini
+	CountrySZ CountryCode = "sz"
+	// CountryTC Turks and Caicos Islands
+	CountryTC CountryCode = "tc"
+	// CountryTD Chad
+	CountryTD CountryCode = "td"
+	// CountryTF French Southern Territories
+	CountryTF CountryCode = "tf"
+	// CountryTG Togo
+	CountryTG CountryCode = "tg"
+	// CountryTH Thailand
+	CountryTH CountryCode = "th"
+	// CountryTJ Tajikistan
+	CountryTJ CountryCode = "tj"
+	// CountryTK Tokelau
+	CountryTK CountryCode = "tk"
+	// CountryTL Timor-Leste
+	CountryTL CountryCode = "tl"
+	// CountryTM Turkmenistan
+	CountryTM CountryCode = "tm"
+	// CountryTN Tunisia
+	CountryTN CountryCode = "tn"
+	// CountryTO Tonga
+	CountryTO CountryCode = "to"
+	// CountryTR Turkiye
+	CountryTR CountryCode = "tr"
+	// CountryTT Trinidad and Tobago
+	CountryTT CountryCode = "tt"
+	// CountryTV Tuvalu
+	CountryTV CountryCode = "tv"
+	// CountryTW Taiwan
+	CountryTW CountryCode = "tw"
+	// CountryTZ Tanzania
+	CountryTZ CountryCode = "tz"
+	// CountryUA Ukraine
+	CountryUA CountryCode = "ua"
+	// CountryUG Uganda
+	CountryUG CountryCode = "ug"
+	// CountryUM United States Minor Outlying Islands
+	CountryUM CountryCode = "um"
+	// CountryUS United States
+	CountryUS CountryCode = "us"
+	// CountryUY Uruguay
+	CountryUY CountryCode = "uy"
+	// CountryUZ Uzbekistan
+	CountryUZ CountryCode = "uz"
+	// CountryVA Holy See
+	CountryVA CountryCode = "va"
+	// CountryVC Saint Vincent and the Grenadines
+	CountryVC CountryCode = "vc"
+	// CountryVE Venezuela
+	CountryVE CountryCode = "ve"
+	// CountryVG Virgin Islands (British)
+	CountryVG CountryCode = "vg"
+	// CountryVI Virgin Islands (U.S.)
+	CountryVI CountryCode = "vi"
+	// CountryVN Viet Nam
+	CountryVN CountryCode = "vn"
+	// CountryVU Vanuatu
+	CountryVU CountryCode = "vu"
+	// CountryWF Wallis and Futuna
+	CountryWF CountryCode = "wf"
+	// CountryWS Samoa
+	CountryWS CountryCode = "ws"
+	// CountryYE Yemen
+	CountryYE CountryCode = "ye"
+	// CountryYT Mayotte
+	CountryYT CountryCode = "yt"
+	// CountryZA South Africa
+	CountryZA CountryCode = "za"
+	// CountryZM Zambia
+	CountryZM CountryCode = "zm"
+	// CountryZW Zimbabwe
+	CountryZW CountryCode = "zw"
+)
+
+// countryCodes is the set of ISO 3166-1 alpha-2 country codes
+var countryCodes = map[CountryCode]bool{
+	CountryAD: true,
+	CountryAE: true,
+	CountryAF: true,
+	CountryAG: true,
+	CountryAI: true,
+	CountryAL: true,
+	CountryAM: true,
+	CountryAO: true,
+	CountryAQ: true,
+	CountryAR: true,
+	CountryAS: true,
+	CountryAT: true,
+	CountryAU: true,
+	CountryAW: true,
+	CountryAX: true,
+	CountryAZ: true,
+	CountryBA: true,
+	CountryBB: true,
+	CountryBD: true,
+	CountryBE: true,
+	CountryBF: true,
+	CountryBG: true,
+	CountryBH: true,
+	CountryBI: true,
+	CountryBJ: true,
+	CountryBL: true,
+	CountryBM: true,
+	CountryBN: true,
+	CountryBO: true,
+	CountryBQ: true,
+	CountryBR: true,
+	CountryBS: true,
+	CountryBT: true,
+	CountryBV: true,
+	CountryBW: true,
+	CountryBY: true,
+	CountryBZ: true,
+	CountryCA: true,
+	CountryCC: true,
+	CountryCD: true,
+	CountryCF: true,
+	CountryCG: true,
+	CountryCH: true,
+	CountryCI: true,
+	CountryCK: true,
+	CountryCL: true,
+	CountryCM: true,
+	CountryCN: true,
+	CountryCO: true,
+	CountryCR: true,
+	CountryCU: true,
+	CountryCV: true,
+	CountryCW: true,
+	CountryCX: true,
+	CountryCY: true,
+	CountryCZ: true,
+	CountryDE: true,
+	CountryDJ: true,
+	CountryDK: true,
+	CountryDM: true,
+	CountryDO: true,
+	CountryDZ: true,
+	CountryEC: true,
+	CountryEE: true,
+	CountryEG: true,
+	CountryEH: true,
+	CountryER: true,
+	CountryES: true,
+	CountryET: true,
+	CountryFI: true,
+	CountryFJ: true,
+	CountryFK: true,
+	CountryFM: true,
+	CountryFO: true,
+	CountryFR: true,
+	CountryGA: true,
+	CountryGB: true,
+	CountryGD: true,
+	CountryGE: true,
+	CountryGF: true,
+	CountryGG: true,
+	CountryGH: true,
+	CountryGI: true,
+	CountryGL: true,
+	CountryGM: true,
+	CountryGN: true,
+	CountryGP: true,
+	CountryGQ: true,
+	CountryGR: true,
+	CountryGS: true,
+	CountryGT: true,
+	CountryGU: true,
+	CountryGW: true,
+	CountryGY: true,
+	CountryHK: true,
+	CountryHM: true,
+	CountryHN: true,
+	CountryHR: true,
+	CountryHT: true,
+	CountryHU: true,
+	CountryID: true,
+	CountryIE: true,
+	CountryIL: true,
+	CountryIM: true,
+	CountryIN: true,
+	CountryIO: true,
+	CountryIQ: true,
+	CountryIR: true,
+	CountryIS: true,
+	CountryIT: true,
+	CountryJE: true,
+	CountryJM: true,
+	CountryJO: true,
+	CountryJP: true,
+	CountryKE: true,
+	CountryKG: true,
+	CountryKH: true,
+	CountryKI: true,
+	CountryKM: true,
+	CountryKN: true,
+	CountryKP: true,
+	CountryKR: true,
+	CountryKW: true,
+	CountryKY: true,
+	CountryKZ: true,
+	CountryLA: true,
+	CountryLB: true,
+	CountryLC: true,
+	CountryLI: true,
+	CountryLK: true,
+	CountryLR: true,
+	CountryLS: true,
+	CountryLT: true,
+	CountryLU: true,
+	CountryLV: true,
+	CountryLY: true,
+	CountryMA: true,
+	CountryMC: true,
+	CountryMD: true,
+	CountryME: true,
+	CountryMF: true,
+	CountryMG: true,
+	CountryMH: true,
+	CountryMK: true,
+	CountryML: true,
+	CountryMM: true,
+	CountryMN: true,
+	CountryMO: true,
+	CountryMP: true,
+	CountryMQ: true,
+	CountryMR: true,
+	CountryMS: true,
+	CountryMT: true,
+	CountryMU: true,
+	CountryMV: true,
+	CountryMW: true,
+	CountryMX: true,
+	CountryMY: true,
+	CountryMZ: true,
+	CountryNA: true,
+	CountryNC: true,
+	CountryNE: true,
+	CountryNF: true,
+	CountryNG: true,
+	CountryNI: true,
+	CountryNL: true,
+	CountryNO: true,
+	CountryNP: true,
+	CountryNR: true,
+	CountryNU: true,
+	CountryNZ: true,
+	CountryOM: true,
+	CountryPA: true,
+	CountryPE: true,
+	CountryPF: true,
+	CountryPG: true,
+	CountryPH: true,
+	CountryPK: true,
+	CountryPL: true,
+	CountryPM: true,
+	CountryPN: true,
+	CountryPR: true,
+	CountryPS: true,
+	CountryPT: true,
+	CountryPW: true,
+	CountryPY: true,
+	CountryQA: true,
+	CountryRE: true,
+	CountryRO: true,
+	CountryRS: true,
+	CountryRU: true,
+	CountryRW: true,
+	CountrySA: true,
+	CountrySB: true,
+	CountrySC: true,
+	CountrySD: true,
+	CountrySE: true,
+	CountrySG: true,
+	CountrySH: true,
+	CountrySI: true,
+	CountrySJ: true,
+	CountrySK: true,
+	CountrySL: true,
+	CountrySM: true,
+	CountrySN: true,
+	CountrySO: true,
+	CountrySR: true,
+	CountrySS: true,
+	CountryST: true,
+	CountrySV: true,
+	CountrySX: true,
+	CountrySY: true,
+	CountrySZ: true,
+	CountryTC: true,
+	CountryTD: true,
+	CountryTF: true,
+	CountryTG: true,
+	CountryTH: true,
+	CountryTJ: true,
+	CountryTK: true,
+	CountryTL: true,
+	CountryTM: true,
+	CountryTN: true,
+	CountryTO: true,
+	CountryTR: true,
+	CountryTT: true,
+	CountryTV: true,
+	CountryTW: true,
+	CountryTZ: true,
+	CountryUA: true,
+	CountryUG: true,
+	CountryUM: true,
+	CountryUS: true,
+	CountryUY: true,
+	CountryUZ: true,
+	CountryVA: true,
+	CountryVC: true,
+	CountryVE: true,
+	CountryVG: true,
+	CountryVI: true,
+	CountryVN: true,
+	CountryVU: true,
+	CountryWF: true,
+	CountryWS: true,
+	CountryYE: true,
+	CountryYT: true,
+	CountryZA: true,
+	CountryZM: true,
+	CountryZW: true,
+}
+
+// ValidCountryCode checks whether a string is an ISO 3166-1 alpha-2 country code, ignoring case
+func ValidCountryCode(s string) bool {
+	return countryCodes[CountryCode(strings.ToLower(s))]
+}
+
+// ParseCountryCode parses an ISO 3166-1 alpha-2 country code, ignoring case
+func ParseCountryCode(s string) (CountryCode, error) {
+	if !ValidCountryCode(s) {
+		return "", fmt.Errorf("Unknown country code %q", s)
+	}
+	return CountryCode(strings.ToLower(s)), nil
+}
+
+// TypedCountry formats country codes as a comma separated list for request options
+func TypedCountry(codes ...CountryCode) string {
+	s := make([]string, len(codes))
+	for i, c := range codes {
+		s[i] = string(c)
+	}
+	return strings.Join(s, ",")
+}
@@ -10,7 +10,9 @@
 package geocode
 
 import (
+	"crypto/rand"
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/google/go-querystring/query"
@@ -48,6 +50,26 @@ const (
 	POI Type = "poi"
 )
 
+// Types is a list of location response types, encoded as a comma separated query parameter
+type Types []Type
+
+// EncodeValues encodes the types as a single comma separated parameter
+func (t Types) EncodeValues(key string, v *url.Values) error {
+	if len(t) > 0 {
+		v.Set(key, typesToString(t))
+	}
+	return nil
+}
+
+// typesToString joins types into the comma separated form expected by the API
+func typesToString(types []Type) string {
+	s := make([]string, len(types))
+	for i, t := range types {
+		s[i] = string(t)
+	}
+	return strings.Join(s, ",")
+}
+
 // Geocode api wrapper instance
 type Geocode struct {
 	base *base.Base
@@ -60,14 +82,48 @@ func NewGeocode(base *base.Base) *Geocode {
 
 // ForwardRequestOpts request options fo forward geocoding
 type ForwardRequestOpts struct {
-	Country      string           `url:"country,omitempty"`
-	Proximity    []float64        `url:"proximity,omitempty"`
-	Types        []Type           `url:"types,omitempty"`
+	// Country restricts results to a comma separated list of ISO 3166-1 alpha-2 codes, see TypedCountry
+	Country string `url:"country,omitempty"`
+	// Proximity biases results towards a lng,lat location, see SetProximity
+	Proximity []float64 `url:"proximity,omitempty,comma"`
+	// IPProximity biases results towards the location of the requester's IP address, in place of Proximity
+	IPProximity  bool             `url:"-"`
+	Types        Types            `url:"types,omitempty"`
 	Autocomplete bool             `url:"autocomplete,omitempty"`
 	BBox         base.BoundingBox `url:"bbox,omitempty"`
 	Limit        uint             `url:"limit,omitempty"`
 	FuzzyMatch   bool             `url:"fuzzyMatch,omitempty"`
 	Routing      bool             `url:"routing,omitempty"`
+	// SessionToken groups autocomplete requests into a single billing session, see NewSessionToken
+	SessionToken string `url:"session_token,omitempty"`
+	// Worldview selects the boundaries returned for disputed areas
+	Worldview Worldview `url:"worldview,omitempty"`
+	// Language selects the language of feature names
+	Language Language `url:"language,omitempty"`
+}
+
+// ProximityIP is the proximity value used for IPProximity
+const ProximityIP = "ip"
+
+// SetProximity biases results towards the provided location
+func (o *ForwardRequestOpts) SetProximity(lng, lat float64) {
+	o.Proximity = []float64{lng, lat}
+}
+
+// NewSessionToken generates a random (UUIDv4) session token for autocomplete billing
+// The same token should be reused for every keystroke of a search, then replaced with
+// a new token once the user has selected a result
+func NewSessionToken() (string, error) {
+	u := make([]byte, 16)
+	if _, err := rand.Read(u); err != nil {
+		return "", fmt.Errorf("Session token generation failed (%w)", err)
+	}
+
+	// Set version (4) and variant (RFC4122) bits
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16]), nil
 }
 
 // ForwardResponse is the response from a forward geocode lookup
@@ -79,11 +135,19 @@ type ForwardResponse struct {
 // Forward geocode lookup
 // Finds locations from a place name
 func (g *Geocode) Forward(place string, req *ForwardRequestOpts, permanent ...bool) (*ForwardResponse, error) {
+	if req != nil {
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+	}
 
 	v, err := query.Values(req)
 	if err != nil {
 		return nil, err
 	}
+	if req != nil && req.IPProximity {
+		v.Set("proximity", ProximityIP)
+	}
 
 	resp := ForwardResponse{}
 
@@ -97,10 +161,164 @@ func (g *Geocode) Forward(place string, req *ForwardRequestOpts, permanent ...bo
 	return &resp, err
 }
 
+// ForwardWithProximity performs a forward geocode lookup biased towards the provided location
+func (g *Geocode) ForwardWithProximity(place string, req *ForwardRequestOpts, lng, lat float64) (*ForwardResponse, error) {
+	opts := ForwardRequestOpts{}
+	if req != nil {
+		opts = *req
+	}
+	opts.SetProximity(lng, lat)
+
+	return g.Forward(place, &opts)
+}
+
+// ForwardWithBBox performs a forward geocode lookup limited to results within the provided bounding box
+func (g *Geocode) ForwardWithBBox(place string, req *ForwardRequestOpts, bbox base.BoundingBox) (*ForwardResponse, error) {
+	opts := ForwardRequestOpts{}
+	if req != nil {
+		opts = *req
+	}
+	opts.BBox = bbox
+
+	return g.Forward(place, &opts)
+}
+
+// ForwardWithIPProximity performs a forward geocode lookup biased towards the location of the requester's IP address
+// This is useful for locally relevant results where the user's location is not known
+func (g *Geocode) ForwardWithIPProximity(place string, req *ForwardRequestOpts) (*ForwardResponse, error) {
+	opts := ForwardRequestOpts{}
+	if req != nil {
+		opts = *req
+	}
+	opts.Proximity = nil
+	opts.IPProximity = true
+
+	return g.Forward(place, &opts)
+}
+
+// FirstLocation returns the location of the first (most relevant) feature in the response
+func (r *ForwardResponse) FirstLocation() (*base.Location, error) {
+	if r.FeatureCollection == nil || len(r.Features) == 0 {
+		return nil, fmt.Errorf("Forward response contains no features")
+	}
+
+	loc, err := FeatureLocation(r.Features[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &loc, nil
+}
+
+// RoutablePoint returns the first routable point of the feature at idx, suitable for use with directions
+// Where the feature has no routable points the feature location is returned along with false,
+// and nil is returned where the feature does not exist or has no point geometry
+func (r *ForwardResponse) RoutablePoint(idx int) (*base.Location, bool) {
+	return routablePoint(r.FeatureCollection, idx)
+}
+
+// Each calls fn for each feature in the response in order, stopping if fn returns false
+func (r *ForwardResponse) Each(fn func(f *GeocodingFeature) bool) {
+	eachFeature(r.FeatureCollection, fn)
+}
+
+// First returns the first (most relevant) feature in the response
+func (r *ForwardResponse) First() (*GeocodingFeature, bool) {
+	return firstFeature(r.FeatureCollection)
+}
+
+// PickBest returns the single most relevant feature in the response, or false where there are no features
+// Features are ranked by highest relevance (the relevance property, falling back to the v5 relevance field),
+// then by type (address features before place features, and both before other types), then by whether the
+// match code is reliable (exact or high confidence), with remaining ties resolved in favour of the earlier
+// feature. Match codes are only returned by v6 geocoding (for example batch results), so the v5 features
+// returned by Forward are ranked by relevance and type alone. Callers needing different semantics should
+// iterate the features with Each
+func (r *ForwardResponse) PickBest() (*GeocodingFeature, bool) {
+	if r.FeatureCollection == nil || len(r.Features) == 0 {
+		return nil, false
+	}
+
+	best := 0
+	for i := 1; i < len(r.Features); i++ {
+		if betterFeature(&r.Features[i], &r.Features[best]) {
+			best = i
+		}
+	}
+
+	return &GeocodingFeature{r.Features[best]}, true
+}
+
+// betterFeature checks whether feature a ranks strictly above feature b, see PickBest
+func betterFeature(a, b *base.Feature) bool {
+	if ra, rb := featureRelevance(a), featureRelevance(b); ra != rb {
+		return ra > rb
+	}
+	if ta, tb := featureTypeRank(a), featureTypeRank(b); ta != tb {
+		return ta > tb
+	}
+	return featureReliable(a) && !featureReliable(b)
+}
+
+// featureRelevance returns the relevance property, falling back to the v5 relevance field
+func featureRelevance(f *base.Feature) float64 {
+	if r, ok := f.RawProperties["relevance"].(float64); ok {
+		return r
+	}
+	return f.Relevance
+}
+
+// featureTypeRank ranks address features above place features, and both above other types
+func featureTypeRank(f *base.Feature) int {
+	t, _ := f.RawProperties["feature_type"].(string)
+	if t == "" && len(f.PlaceType) > 0 {
+		t = f.PlaceType[0]
+	}
+
+	switch Type(t) {
+	case Address:
+		return 2
+	case Place:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// featureReliable checks whether the feature has an exact or high confidence match code
+func featureReliable(f *base.Feature) bool {
+	code, err := ParseMatchCode(f.RawProperties)
+	return err == nil && code.IsReliable()
+}
+
+// MatchCodes returns the match code of each feature in the response, indexed as Features
+// Features without a (decodable) match code have a zero value entry, with an empty Confidence.
+// Match codes are only returned by v6 geocoding such as ForwardStructured, v5 Forward responses
+// never include them and so always return zero value entries
+func (r *ForwardResponse) MatchCodes() []MatchCode {
+	if r.FeatureCollection == nil {
+		return nil
+	}
+
+	codes := make([]MatchCode, len(r.Features))
+	for i, f := range r.Features {
+		code, err := ParseMatchCode(f.RawProperties)
+		if err == nil {
+			codes[i] = *code
+		}
+	}
+
+	return codes
+}
+
 // ReverseRequestOpts request options fo reverse geocoding
 type ReverseRequestOpts struct {
-	Types []Type
-	Limit uint
+	Types Types `url:"types,omitempty"`
+	Limit uint  `url:"limit,omitempty"`
+	// Country restricts results to a comma separated list of ISO 3166-1 alpha-2 codes, see TypedCountry
+	Country   string    `url:"country,omitempty"`
+	Worldview Worldview `url:"worldview,omitempty"`
+	Language  Language  `url:"language,omitempty"`
 }
 
 // ReverseResponse is the response to a reverse geocode request
@@ -109,9 +327,31 @@ type ReverseResponse struct {
 	Query []float64
 }
 
+// RoutablePoint returns the first routable point of the feature at idx, suitable for use with directions
+// Where the feature has no routable points the feature location is returned along with false,
+// and nil is returned where the feature does not exist or has no point geometry
+func (r *ReverseResponse) RoutablePoint(idx int) (*base.Location, bool) {
+	return routablePoint(r.FeatureCollection, idx)
+}
+
+// Each calls fn for each feature in the response in order, stopping if fn returns false
+func (r *ReverseResponse) Each(fn func(f *GeocodingFeature) bool) {
+	eachFeature(r.FeatureCollection, fn)
+}
+
+// First returns the first (most specific) feature in the response
+func (r *ReverseResponse) First() (*GeocodingFeature, bool) {
+	return firstFeature(r.FeatureCollection)
+}
+
 // Reverse geocode lookup
 // Finds place names from a location
 func (g *Geocode) Reverse(loc *base.Location, req *ReverseRequestOpts) (*ReverseResponse, error) {
+	if req != nil {
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+	}
 
 	v, err := query.Values(req)
 	if err != nil {
@@ -10,14 +10,24 @@
 package geocode
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
 	"os"
 	"reflect"
 	"strings"
+	"sync/atomic"
 	"testing"
+
+	"github.com/google/go-querystring/query"
+	"github.com/stretchr/testify/assert"
 )
 
 import (
 	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/internal/basetest"
 )
 
 func TestGeocoder(t *testing.T) {
@@ -69,3 +79,1066 @@ func TestGeocoder(t *testing.T) {
 	})
 
 }
+
+const addressFeature = `{
+	"type": "Feature",
+	"id": "dXJuOm1ieGFkcjo0YTNmZTUzMS0yNDgwLTRlMzItYTE1Yy00YzE2ZjFlMzdjMDk",
+	"geometry": {
+		"type": "Point",
+		"coordinates": [-77.050119, 38.889859]
+	},
+	"properties": {
+		"mapbox_id": "dXJuOm1ieGFkcjo0YTNmZTUzMS0yNDgwLTRlMzItYTE1Yy00YzE2ZjFlMzdjMDk",
+		"feature_type": "address",
+		"full_address": "2 Lincoln Memorial Circle Northwest, Washington, District of Columbia 20037, United States",
+		"name": "2 Lincoln Memorial Circle Northwest",
+		"name_preferred": "2 Lincoln Memorial Circle Northwest",
+		"coordinates": {
+			"longitude": -77.050119,
+			"latitude": 38.889859,
+			"accuracy": "rooftop",
+			"routable_points": [
+				{"name": "default", "latitude": 38.889755, "longitude": -77.049645}
+			]
+		},
+		"place_formatted": "Washington, District of Columbia 20037, United States",
+		"match_code": {
+			"address_number": "matched",
+			"street": "matched",
+			"postcode": "unmatched",
+			"place": "matched",
+			"region": "matched",
+			"locality": "not_applicable",
+			"country": "inferred",
+			"confidence": "exact"
+		},
+		"context": {
+			"address": {
+				"mapbox_id": "dXJuOm1ieGFkcjo0YTNmZTUzMS0yNDgwLTRlMzItYTE1Yy00YzE2ZjFlMzdjMDk",
+				"address_number": "2",
+				"street_name": "Lincoln Memorial Circle Northwest",
+				"name": "2 Lincoln Memorial Circle Northwest"
+			},
+			"street": {
+				"mapbox_id": "dXJuOm1ieGFkcjo0YTNmZTUzMS0yNDgwLTRlMzItYTE1Yy00YzE2ZjFlMzdjMDk",
+				"name": "Lincoln Memorial Circle Northwest"
+			},
+			"postcode": {
+				"mapbox_id": "dXJuOm1ieHBsYzpBMU5P",
+				"name": "20037"
+			},
+			"place": {
+				"mapbox_id": "dXJuOm1ieHBsYzpGSmlvN0E",
+				"name": "Washington",
+				"wikidata_id": "Q61"
+			},
+			"region": {
+				"mapbox_id": "dXJuOm1ieHBsYzpCUVRz",
+				"name": "District of Columbia",
+				"wikidata_id": "Q3551781",
+				"region_code": "DC",
+				"region_code_full": "US-DC"
+			},
+			"country": {
+				"mapbox_id": "dXJuOm1ieHBsYzpJdXc",
+				"name": "United States",
+				"wikidata_id": "Q30",
+				"country_code": "US",
+				"country_code_alpha_3": "USA"
+			}
+		}
+	}
+}`
+
+func TestParseProperties(t *testing.T) {
+	f := base.Feature{}
+	err := json.Unmarshal([]byte(addressFeature), &f)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	props, err := ParseProperties(f)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.EqualValues(t, "address", props.FeatureType)
+	assert.EqualValues(t, "2 Lincoln Memorial Circle Northwest", props.Name)
+	assert.EqualValues(t, "Washington, District of Columbia 20037, United States", props.PlaceFormatted)
+	assert.EqualValues(t, -77.050119, props.Coordinates.Longitude)
+	assert.EqualValues(t, 38.889859, props.Coordinates.Latitude)
+	assert.EqualValues(t, "rooftop", props.Coordinates.Accuracy)
+	assert.Len(t, props.Coordinates.RoutablePoints, 1)
+	assert.EqualValues(t, "exact", props.MatchCode.Confidence)
+	assert.EqualValues(t, "unmatched", props.MatchCode.Postcode)
+	if assert.NotNil(t, props.Context.Country) {
+		assert.EqualValues(t, "US", props.Context.Country.CountryCode)
+		assert.EqualValues(t, "United States", props.Context.Country.Name)
+	}
+	if assert.NotNil(t, props.Context.Region) {
+		assert.EqualValues(t, "US-DC", props.Context.Region.RegionCodeFull)
+	}
+	if assert.NotNil(t, props.Context.Address) {
+		assert.EqualValues(t, "2", props.Context.Address.AddressNumber)
+	}
+	assert.Nil(t, props.Context.Locality)
+
+	// Raw properties are retained for fields not yet modelled
+	assert.EqualValues(t, "address", f.RawProperties["feature_type"])
+}
+
+func TestGeocodingProperties(t *testing.T) {
+	f := GeocodingFeature{}
+	err := json.Unmarshal([]byte(addressFeature), &f.Feature)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	props, err := f.GeocodingProperties()
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.EqualValues(t, "2 Lincoln Memorial Circle Northwest, Washington, District of Columbia 20037, United States", props.FullAddress)
+	assert.EqualValues(t, "Lincoln Memorial Circle Northwest", props.Context.Street.Name)
+	assert.EqualValues(t, "20037", props.Context.Postcode.Name)
+}
+
+func TestContextMap(t *testing.T) {
+	f := base.Feature{}
+	err := json.Unmarshal([]byte(addressFeature), &f)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	props, err := ParseProperties(f)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	m := props.ContextMap()
+	assert.Len(t, m, 6)
+	assert.EqualValues(t, ContextEntry{MapboxID: "dXJuOm1ieHBsYzpJdXc", Name: "United States", WikidataID: "Q30"}, m["country"])
+	assert.EqualValues(t, "Q3551781", m["region"].WikidataID)
+	assert.NotContains(t, m, "locality")
+
+	crumbs := []string{}
+	for _, level := range ContextLevels {
+		if e, ok := m[level]; ok && level != "postcode" {
+			crumbs = append(crumbs, e.Name)
+		}
+	}
+	assert.EqualValues(t, []string{"United States", "District of Columbia", "Washington", "Lincoln Memorial Circle Northwest", "2 Lincoln Memorial Circle Northwest"}, crumbs)
+}
+
+func TestFeatureLocation(t *testing.T) {
+
+	t.Run("Extracts point locations", func(t *testing.T) {
+		f := base.Feature{}
+		err := json.Unmarshal([]byte(addressFeature), &f)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		loc, err := FeatureLocation(f)
+		assert.Nil(t, err)
+		assert.EqualValues(t, base.Location{Latitude: 38.889859, Longitude: -77.050119}, loc)
+
+		res := ForwardResponse{FeatureCollection: &base.FeatureCollection{Features: []base.Feature{f}}}
+		first, err := res.FirstLocation()
+		assert.Nil(t, err)
+		assert.EqualValues(t, &loc, first)
+	})
+
+	t.Run("Rejects missing and non-point geometries", func(t *testing.T) {
+		_, err := FeatureLocation(base.Feature{})
+		assert.NotNil(t, err)
+
+		_, err = FeatureLocation(base.Feature{Geometry: base.Geometry{Type: "LineString"}})
+		assert.NotNil(t, err)
+
+		_, err = FeatureLocation(base.Feature{Geometry: base.Geometry{Type: "Point", Coordinates: []float64{1}}})
+		assert.NotNil(t, err)
+
+		res := ForwardResponse{FeatureCollection: &base.FeatureCollection{}}
+		_, err = res.FirstLocation()
+		assert.NotNil(t, err)
+	})
+}
+
+func TestSessionToken(t *testing.T) {
+	token, err := NewSessionToken()
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, token)
+
+	other, err := NewSessionToken()
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.NotEqual(t, token, other)
+
+	b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.EqualValues(t, token, r.URL.Query().Get("session_token"))
+		w.Write([]byte(`{"type":"FeatureCollection","features":[]}`))
+	})
+
+	_, err = NewGeocode(b).Forward("lincoln memorial", &ForwardRequestOpts{Autocomplete: true, SessionToken: token})
+	assert.Nil(t, err)
+}
+
+// batchHandler creates a mock batch endpoint echoing each query as a feature, failing the numbered requests
+func batchHandler(t *testing.T, requests *int32, fail ...int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(requests, 1)
+
+		assert.EqualValues(t, http.MethodPost, r.Method)
+		assert.EqualValues(t, "/search/geocode/v6/batch", r.URL.Path)
+
+		for _, f := range fail {
+			if n == f {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+
+		queries := []BatchQuery{}
+		err := json.NewDecoder(r.Body).Decode(&queries)
+		assert.Nil(t, err)
+
+		resp := BatchResponse{}
+		for _, q := range queries {
+			resp.Batch = append(resp.Batch, base.FeatureCollection{
+				Type:     "FeatureCollection",
+				Features: []base.Feature{{Text: q.Q}},
+			})
+		}
+		json.NewEncoder(w).Encode(&resp)
+	}
+}
+
+func batchQueries(n int) []BatchQuery {
+	queries := make([]BatchQuery, n)
+	for i := range queries {
+		queries[i].Q = fmt.Sprintf("query %d", i)
+	}
+	return queries
+}
+
+func TestBatch(t *testing.T) {
+
+	t.Run("Automatically chunks oversized batches", func(t *testing.T) {
+		var requests int32
+		b := basetest.NewBase(t, batchHandler(t, &requests))
+
+		res, err := NewGeocode(b).Batch(context.Background(), batchQueries(MaxBatchQueries+1), nil)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.EqualValues(t, 2, requests)
+		assert.Len(t, res.Batch, MaxBatchQueries+1)
+		for i, fc := range res.Batch {
+			assert.EqualValues(t, fmt.Sprintf("query %d", i), fc.Features[0].Text)
+		}
+	})
+
+	t.Run("Automatically chunks concurrently where limited", func(t *testing.T) {
+		var requests int32
+		b := basetest.NewBase(t, batchHandler(t, &requests), base.WithMaxConcurrency(2))
+
+		res, err := NewGeocode(b).Batch(context.Background(), batchQueries(2500), nil)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.EqualValues(t, 3, requests)
+		assert.Len(t, res.Batch, 2500)
+		for i, fc := range res.Batch {
+			assert.EqualValues(t, fmt.Sprintf("query %d", i), fc.Features[0].Text)
+		}
+	})
+
+	t.Run("Sends oversized batches when auto chunking is disabled", func(t *testing.T) {
+		var received int
+		b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			queries := []BatchQuery{}
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(&queries))
+			received = len(queries)
+
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message":"Batch requests are limited to 1000 queries"}`))
+		})
+
+		_, err := NewGeocode(b).Batch(context.Background(), batchQueries(MaxBatchQueries+1), &BatchRequestOpts{DisableAutoChunk: true})
+
+		apiErr := &base.APIError{}
+		if assert.True(t, errors.As(err, &apiErr)) {
+			assert.EqualValues(t, http.StatusUnprocessableEntity, apiErr.StatusCode)
+		}
+		assert.EqualValues(t, MaxBatchQueries+1, received)
+	})
+
+	t.Run("Splits large batches in order", func(t *testing.T) {
+		var requests int32
+		b := basetest.NewBase(t, batchHandler(t, &requests))
+
+		res, err := NewGeocode(b).BatchAll(context.Background(), batchQueries(1500), nil)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.EqualValues(t, 2, requests)
+		assert.Len(t, res.Batch, 1500)
+		for i, fc := range res.Batch {
+			assert.EqualValues(t, fmt.Sprintf("query %d", i), fc.Features[0].Text)
+		}
+	})
+
+	t.Run("Surfaces partial failures", func(t *testing.T) {
+		var requests int32
+		b := basetest.NewBase(t, batchHandler(t, &requests, 2))
+
+		res, err := NewGeocode(b).BatchAll(context.Background(), batchQueries(2500), nil)
+
+		batchErr := &BatchError{}
+		if !assert.True(t, errors.As(err, &batchErr)) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, 1000, batchErr.Start)
+		assert.EqualValues(t, 2000, batchErr.End)
+		assert.Len(t, res.Batch, 1000)
+		assert.EqualValues(t, 2, requests)
+	})
+
+	t.Run("Dispatches concurrent batches in order", func(t *testing.T) {
+		var requests int32
+		b := basetest.NewBase(t, batchHandler(t, &requests))
+
+		res, err := NewGeocode(b).BatchConcurrent(context.Background(), batchQueries(4500), nil, 3)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.EqualValues(t, 5, requests)
+		assert.Len(t, res.Batch, 4500)
+		for i, fc := range res.Batch {
+			assert.EqualValues(t, fmt.Sprintf("query %d", i), fc.Features[0].Text)
+		}
+	})
+
+	t.Run("Aborts concurrent batches on failure", func(t *testing.T) {
+		var requests int32
+		b := basetest.NewBase(t, batchHandler(t, &requests, 1))
+
+		res, err := NewGeocode(b).BatchConcurrent(context.Background(), batchQueries(4500), nil, 2)
+
+		batchErr := &BatchError{}
+		assert.True(t, errors.As(err, &batchErr))
+		assert.Nil(t, res)
+	})
+
+	t.Run("Forward geocodes places in order", func(t *testing.T) {
+		places := []string{"Sydney", "Melbourne", "Brisbane"}
+
+		var requests int32
+		b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+
+			queries := []BatchQuery{}
+			err := json.NewDecoder(r.Body).Decode(&queries)
+			assert.Nil(t, err)
+
+			assert.EqualValues(t, "true", r.URL.Query().Get("permanent"))
+
+			resp := BatchResponse{}
+			for i, q := range queries {
+				assert.EqualValues(t, places[i], q.Q)
+				assert.EqualValues(t, "au", q.Country)
+				assert.EqualValues(t, []float64{151.2, -33.8}, q.Proximity)
+
+				resp.Batch = append(resp.Batch, base.FeatureCollection{
+					Type:     "FeatureCollection",
+					Features: []base.Feature{{Text: q.Q}},
+				})
+			}
+			json.NewEncoder(w).Encode(&resp)
+		})
+		g := NewGeocode(b)
+
+		opts := ForwardRequestOpts{Country: "au"}
+		opts.SetProximity(151.2, -33.8)
+
+		res, err := g.ForwardBatch(context.Background(), places, &opts, &BatchRequestOpts{Permanent: true})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.Len(t, res.Batch, len(places))
+		for i, fc := range res.Batch {
+			assert.EqualValues(t, places[i], fc.Features[0].Text)
+		}
+
+		_, err = g.ForwardBatch(context.Background(), nil, nil, nil)
+		assert.NotNil(t, err)
+
+		rejected := []struct {
+			opts      ForwardRequestOpts
+			batchOpts *BatchRequestOpts
+			places    []string
+			field     string
+		}{
+			{ForwardRequestOpts{IPProximity: true}, nil, places, "proximity"},
+			{ForwardRequestOpts{FuzzyMatch: true}, nil, places, "fuzzyMatch"},
+			{ForwardRequestOpts{Routing: true}, nil, places, "routing"},
+			{ForwardRequestOpts{SessionToken: "token"}, nil, places, "session_token"},
+			{ForwardRequestOpts{}, &BatchRequestOpts{DisableAutoChunk: true}, make([]string, MaxBatchQueries+1), "places"},
+		}
+		for _, r := range rejected {
+			_, err = g.ForwardBatch(context.Background(), r.places, &r.opts, r.batchOpts)
+			validationErr := &base.ValidationError{}
+			if assert.True(t, errors.As(err, &validationErr)) {
+				assert.EqualValues(t, r.field, validationErr.Field)
+			}
+		}
+		assert.EqualValues(t, 1, requests)
+	})
+
+	t.Run("Reverse geocodes locations in order", func(t *testing.T) {
+		locs := make([]base.Location, 25)
+		for i := range locs {
+			locs[i] = base.Location{Latitude: -33 - float64(i)/100, Longitude: 151 + float64(i)/100}
+		}
+
+		b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			queries := []BatchQuery{}
+			err := json.NewDecoder(r.Body).Decode(&queries)
+			assert.Nil(t, err)
+			assert.Len(t, queries, len(locs))
+
+			resp := BatchResponse{}
+			for _, q := range queries {
+				assert.EqualValues(t, "", q.Q)
+				assert.EqualValues(t, []Type{Address}, q.Types)
+				assert.EqualValues(t, 1, q.Limit)
+				assert.EqualValues(t, "au", q.Country)
+				assert.EqualValues(t, base.LanguageEN, q.Language)
+
+				resp.Batch = append(resp.Batch, base.FeatureCollection{
+					Type:     "FeatureCollection",
+					Features: []base.Feature{{Center: []float64{*q.Longitude, *q.Latitude}}},
+				})
+			}
+			json.NewEncoder(w).Encode(&resp)
+		})
+
+		opts := ReverseRequestOpts{Types: []Type{Address}, Limit: 1, Country: "au", Language: base.LanguageEN}
+		res, err := NewGeocode(b).ReverseBatch(context.Background(), locs, &opts, nil)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.Len(t, res.Batch, len(locs))
+		for i, fc := range res.Batch {
+			assert.EqualValues(t, []float64{locs[i].Longitude, locs[i].Latitude}, fc.Features[0].Center)
+		}
+
+		_, err = NewGeocode(b).ReverseBatch(context.Background(), nil, nil, nil)
+		assert.NotNil(t, err)
+		_, err = NewGeocode(b).ReverseBatch(context.Background(), []base.Location{}, &opts, nil)
+		validationErr := &base.ValidationError{}
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.EqualValues(t, "locations", validationErr.Field)
+		}
+	})
+}
+
+func TestTypes(t *testing.T) {
+	types := []Type{Address, POI, Place}
+	expected := typesToString(types)
+	assert.EqualValues(t, "address,poi,place", expected)
+
+	t.Run("Encodes types as comma separated parameters", func(t *testing.T) {
+		for _, opts := range []interface{}{
+			&ForwardRequestOpts{Types: types},
+			&ReverseRequestOpts{Types: types},
+			&SuggestOpts{Types: types},
+		} {
+			v, err := query.Values(opts)
+			if !assert.Nil(t, err) {
+				t.FailNow()
+			}
+			assert.EqualValues(t, []string{expected}, v["types"])
+		}
+	})
+
+	t.Run("Omits empty types", func(t *testing.T) {
+		v, err := query.Values(&ReverseRequestOpts{Limit: 1})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "limit=1", v.Encode())
+	})
+
+	t.Run("Encodes batch types as arrays", func(t *testing.T) {
+		data, err := json.Marshal(BatchQuery{Q: "Sydney", Types: types})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.JSONEq(t, `{"q":"Sydney","types":["address","poi","place"]}`, string(data))
+	})
+}
+
+func TestValidation(t *testing.T) {
+	requests := 0
+	b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"type":"FeatureCollection","features":[]}`))
+	})
+	g := NewGeocode(b)
+
+	t.Run("Accepts valid options", func(t *testing.T) {
+		_, err := g.Forward("sydney", &ForwardRequestOpts{
+			Limit:     10,
+			Language:  "en,zh_Hans,zh-Hant",
+			Country:   "au,nz",
+			BBox:      base.BoundingBox{150, -34, 152, -33},
+			Proximity: []float64{151.2, -33.8},
+		})
+		assert.Nil(t, err)
+		_, err = g.Reverse(&base.Location{Latitude: -33.8, Longitude: 151.2}, &ReverseRequestOpts{Limit: 5, Country: "AU"})
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, requests)
+	})
+
+	tests := []struct {
+		name  string
+		opts  ForwardRequestOpts
+		field string
+	}{
+		{"Rejects large limits", ForwardRequestOpts{Limit: 11}, "limit"},
+		{"Rejects malformed languages", ForwardRequestOpts{Language: "english"}, "language"},
+		{"Rejects empty language subtags", ForwardRequestOpts{Language: "en--US"}, "language"},
+		{"Rejects unsupported languages", ForwardRequestOpts{Language: "en,xx"}, "language"},
+		{"Rejects malformed countries", ForwardRequestOpts{Country: "au,aus"}, "country"},
+		{"Rejects short bounding boxes", ForwardRequestOpts{BBox: base.BoundingBox{1, 2, 3}}, "bbox"},
+		{"Rejects inverted bounding boxes", ForwardRequestOpts{BBox: base.BoundingBox{152, -33, 150, -34}}, "bbox"},
+		{"Rejects out of range bounding boxes", ForwardRequestOpts{BBox: base.BoundingBox{-190, -33, 150, -34}}, "bbox"},
+		{"Rejects malformed proximity", ForwardRequestOpts{Proximity: []float64{151.2}}, "proximity"},
+		{"Rejects conflicting proximity", ForwardRequestOpts{Proximity: []float64{151.2, -33.8}, IPProximity: true}, "proximity"},
+		{"Rejects unknown worldviews", ForwardRequestOpts{Worldview: "xx"}, "worldview"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = 0
+			_, err := g.Forward("sydney", &tt.opts)
+			validationErr := &base.ValidationError{}
+			if assert.True(t, errors.As(err, &validationErr)) {
+				assert.EqualValues(t, tt.field, validationErr.Field)
+			}
+			assert.EqualValues(t, 0, requests)
+		})
+	}
+
+	t.Run("Rejects large reverse limits", func(t *testing.T) {
+		_, err := g.Reverse(&base.Location{}, &ReverseRequestOpts{Limit: 6})
+		validationErr := &base.ValidationError{}
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.EqualValues(t, "limit", validationErr.Field)
+		}
+	})
+}
+
+func TestCountryCodes(t *testing.T) {
+
+	t.Run("Accepts all country codes", func(t *testing.T) {
+		assert.Len(t, countryCodes, 249)
+		for c := range countryCodes {
+			assert.True(t, ValidCountryCode(string(c)), string(c))
+			assert.True(t, ValidCountryCode(strings.ToUpper(string(c))), string(c))
+
+			parsed, err := ParseCountryCode(strings.ToUpper(string(c)))
+			assert.Nil(t, err)
+			assert.EqualValues(t, c, parsed)
+		}
+	})
+
+	t.Run("Rejects invalid country codes", func(t *testing.T) {
+		for _, c := range []string{"", "x", "xx", "uk", "usa", "aus", "zz", "a1", "u s", "eu"} {
+			assert.False(t, ValidCountryCode(c), c)
+
+			_, err := ParseCountryCode(c)
+			assert.NotNil(t, err)
+		}
+	})
+
+	t.Run("Formats typed countries", func(t *testing.T) {
+		assert.EqualValues(t, "au,nz", TypedCountry(CountryAU, CountryNZ))
+		assert.Nil(t, (&ForwardRequestOpts{Country: TypedCountry(CountryUS, CountryGB)}).validate())
+
+		err := (&ReverseRequestOpts{Country: "us,uk"}).validate()
+		validationErr := &base.ValidationError{}
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.EqualValues(t, "country", validationErr.Field)
+		}
+	})
+}
+
+func TestWorldview(t *testing.T) {
+
+	t.Run("Validates worldviews", func(t *testing.T) {
+		assert.Nil(t, WorldviewDefault.Validate())
+		assert.Nil(t, WorldviewJP.Validate())
+		assert.NotNil(t, Worldview("jpn").Validate())
+	})
+
+	t.Run("Encodes worldview and language", func(t *testing.T) {
+		v, err := query.Values(&ForwardRequestOpts{Worldview: WorldviewIN, Language: base.LanguageZHHans})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "language=zh-Hans&worldview=in", v.Encode())
+	})
+
+	t.Run("Rejects unknown worldviews before requesting", func(t *testing.T) {
+		var requests int32
+		b := basetest.NewBase(t, batchHandler(t, &requests))
+		g := NewGeocode(b)
+
+		_, err := g.Forward("Sydney", &ForwardRequestOpts{Worldview: "xx"})
+		assert.NotNil(t, err)
+
+		_, err = g.Reverse(&base.Location{Latitude: -33.8, Longitude: 151.2}, &ReverseRequestOpts{Worldview: "xx"})
+		assert.NotNil(t, err)
+
+		_, err = g.Batch(context.Background(), []BatchQuery{{Q: "Sydney", Worldview: "xx"}}, nil)
+		validationErr := &base.ValidationError{}
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.EqualValues(t, "worldview", validationErr.Field)
+		}
+
+		assert.EqualValues(t, 0, requests)
+	})
+}
+
+func TestProximity(t *testing.T) {
+	var proximity string
+	b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		proximity = r.URL.Query().Get("proximity")
+		w.Write([]byte(`{"type":"FeatureCollection","features":[]}`))
+	})
+	g := NewGeocode(b)
+
+	t.Run("Biases towards the requester IP", func(t *testing.T) {
+		req := ForwardRequestOpts{Limit: 1}
+
+		_, err := g.ForwardWithIPProximity("coffee", &req)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "ip", proximity)
+		assert.False(t, req.IPProximity)
+
+		_, err = g.Forward("coffee", &ForwardRequestOpts{IPProximity: true})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "ip", proximity)
+	})
+
+	t.Run("Biases towards locations", func(t *testing.T) {
+		_, err := g.ForwardWithProximity("coffee", nil, 151.2093, -33.8688)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "151.2093,-33.8688", proximity)
+	})
+}
+
+func TestSearchBoxSuggest(t *testing.T) {
+	b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.EqualValues(t, "/search/searchbox/v1/suggest", r.URL.Path)
+
+		q := r.URL.Query()
+		assert.EqualValues(t, "blue bottle", q.Get("q"))
+		assert.EqualValues(t, "session", q.Get("session_token"))
+		assert.EqualValues(t, "poi,address", q.Get("types"))
+		assert.EqualValues(t, "-122.5,37.7,-122.3,37.8", q.Get("bbox"))
+
+		w.Write([]byte(`{"suggestions":[{"name":"Blue Bottle Coffee","mapbox_id":"dXJuOm1ieHBvaTo","feature_type":"poi","place_formatted":"San Francisco, California, United States"}],"attribution":"(c) Mapbox"}`))
+	})
+	g := NewGeocode(b)
+
+	_, err := g.SearchBoxSuggest(context.Background(), "blue bottle", &SuggestOpts{})
+	assert.NotNil(t, err)
+
+	res, err := g.SearchBoxSuggest(context.Background(), "blue bottle", &SuggestOpts{
+		SessionToken: "session",
+		BBox:         base.BoundingBox{-122.5, 37.7, -122.3, 37.8},
+		Types:        []Type{POI, Address},
+	})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.Len(t, res.Suggestions, 1)
+	assert.EqualValues(t, "dXJuOm1ieHBvaTo", res.Suggestions[0].MapboxID)
+	assert.EqualValues(t, "poi", res.Suggestions[0].FeatureType)
+}
+
+func TestMatchCodes(t *testing.T) {
+	data := `{"type":"FeatureCollection","features":[` + addressFeature + `,{"type":"Feature","properties":{"name":"Washington"},"geometry":{"type":"Point","coordinates":[-77.03,38.89]}}]}`
+
+	resp := ForwardResponse{}
+	err := json.Unmarshal([]byte(data), &resp)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	codes := resp.MatchCodes()
+	if !assert.Len(t, codes, 2) {
+		t.FailNow()
+	}
+	assert.EqualValues(t, "exact", codes[0].Confidence)
+	assert.EqualValues(t, "unmatched", codes[0].Postcode)
+	assert.EqualValues(t, MatchCode{}, codes[1])
+}
+
+func TestForwardStructured(t *testing.T) {
+	b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.EqualValues(t, "/search/geocode/v6/forward", r.URL.Path)
+		assert.EqualValues(t, "2", r.URL.Query().Get("address_number"))
+		assert.EqualValues(t, "Lincoln Memorial Circle NW", r.URL.Query().Get("street"))
+		assert.EqualValues(t, "us", r.URL.Query().Get("country"))
+
+		w.Write([]byte(`{"type":"FeatureCollection","features":[` + addressFeature + `]}`))
+	})
+	g := NewGeocode(b)
+
+	t.Run("Returns match codes", func(t *testing.T) {
+		resp, err := g.ForwardStructured(context.Background(), &StructuredInputOpts{
+			AddressNumber: "2",
+			Street:        "Lincoln Memorial Circle NW",
+			Country:       string(CountryUS),
+		})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		codes := resp.MatchCodes()
+		if assert.Len(t, codes, 1) {
+			assert.EqualValues(t, "exact", codes[0].Confidence)
+		}
+	})
+
+	t.Run("Requires an address component", func(t *testing.T) {
+		_, err := g.ForwardStructured(context.Background(), &StructuredInputOpts{Limit: 1})
+		validationErr := &base.ValidationError{}
+		assert.True(t, errors.As(err, &validationErr))
+	})
+
+	t.Run("Returns no match codes for v5 responses", func(t *testing.T) {
+		resp := ForwardResponse{}
+		err := json.Unmarshal([]byte(v5RoutingResponse), &resp)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, []MatchCode{{}}, resp.MatchCodes())
+	})
+}
+
+func TestRoutablePoint(t *testing.T) {
+	data := `{"type":"FeatureCollection","features":[` + addressFeature + `,{"type":"Feature","properties":{"name":"Washington"},"geometry":{"type":"Point","coordinates":[-77.03,38.89]}}]}`
+
+	resp := ReverseResponse{}
+	err := json.Unmarshal([]byte(data), &resp)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	t.Run("Returns routable points", func(t *testing.T) {
+		loc, ok := resp.RoutablePoint(0)
+		assert.True(t, ok)
+		assert.EqualValues(t, &base.Location{Latitude: 38.889755, Longitude: -77.049645}, loc)
+	})
+
+	t.Run("Falls back to feature locations", func(t *testing.T) {
+		loc, ok := resp.RoutablePoint(1)
+		assert.False(t, ok)
+		assert.EqualValues(t, &base.Location{Latitude: 38.89, Longitude: -77.03}, loc)
+	})
+
+	t.Run("Handles missing features", func(t *testing.T) {
+		loc, ok := resp.RoutablePoint(2)
+		assert.False(t, ok)
+		assert.Nil(t, loc)
+
+		fwd := ForwardResponse{FeatureCollection: resp.FeatureCollection}
+		loc, ok = fwd.RoutablePoint(0)
+		assert.True(t, ok)
+		assert.NotNil(t, loc)
+	})
+
+	t.Run("Returns v5 routable points", func(t *testing.T) {
+		b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.EqualValues(t, "true", r.URL.Query().Get("routing"))
+			w.Write([]byte(v5RoutingResponse))
+		})
+
+		fwd, err := NewGeocode(b).Forward("2 lincoln memorial circle nw", &ForwardRequestOpts{Routing: true})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		loc, ok := fwd.RoutablePoint(0)
+		assert.True(t, ok)
+		assert.EqualValues(t, &base.Location{Latitude: 38.88931, Longitude: -77.04983}, loc)
+	})
+}
+
+// v5RoutingResponse is a v5 forward geocoding response with routing requested
+const v5RoutingResponse = `{
+	"type": "FeatureCollection",
+	"query": ["2", "lincoln", "memorial", "circle", "nw"],
+	"features": [{
+		"id": "address.1832548289578232",
+		"type": "Feature",
+		"place_type": ["address"],
+		"relevance": 1,
+		"properties": {"accuracy": "point"},
+		"text": "Lincoln Memorial Circle Northwest",
+		"place_name": "2 Lincoln Memorial Circle Northwest, Washington, District of Columbia 20037, United States",
+		"center": [-77.050171, 38.889356],
+		"geometry": {"type": "Point", "coordinates": [-77.050171, 38.889356]},
+		"address": "2",
+		"routable_points": {"points": [{"name": "default_routable_point", "coordinates": [-77.04983, 38.88931]}]},
+		"context": [
+			{"id": "postcode.13903677306297990", "text": "20037"},
+			{"id": "place.15061242304557200", "wikidata": "Q61", "text": "Washington"},
+			{"id": "region.14064402149979320", "short_code": "US-DC", "wikidata": "Q3551", "text": "District of Columbia"},
+			{"id": "country.9053006287256050", "short_code": "us", "wikidata": "Q30", "text": "United States"}
+		]
+	}],
+	"attribution": "NOTICE: © 2024 Mapbox and its suppliers. All rights reserved."
+}`
+
+func TestSearchBoxRetrieve(t *testing.T) {
+	token, err := NewSessionToken()
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.EqualValues(t, "/search/searchbox/v1/retrieve/dXJuOm1ieGFkcjo", r.URL.Path)
+		assert.EqualValues(t, token, r.URL.Query().Get("session_token"))
+
+		w.Write([]byte(`{"type":"FeatureCollection","features":[` + addressFeature + `]}`))
+	})
+	g := NewGeocode(b)
+
+	_, err = g.SearchBoxRetrieve(context.Background(), "dXJuOm1ieGFkcjo", "")
+	assert.NotNil(t, err)
+
+	res, err := g.SearchBoxRetrieve(context.Background(), "dXJuOm1ieGFkcjo", token)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	loc, err := res.Location()
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.EqualValues(t, &base.Location{Latitude: 38.889859, Longitude: -77.050119}, loc)
+
+	coords, err := res.Coordinates()
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.Len(t, coords.RoutablePoints, 1)
+
+	address, err := res.AddressComponents()
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.EqualValues(t, &AddressComponents{
+		AddressNumber: "2",
+		Street:        "Lincoln Memorial Circle Northwest",
+		Postcode:      "20037",
+		Place:         "Washington",
+		Region:        "District of Columbia",
+		Country:       "United States",
+	}, address)
+}
+
+func TestForwardWithBBox(t *testing.T) {
+	b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.EqualValues(t, []string{"-122.5,37.7,-122.3,37.8"}, r.URL.Query()["bbox"])
+		w.Write([]byte(`{"type":"FeatureCollection","features":[]}`))
+	})
+
+	bbox, err := base.NewBoundingBox(-122.5, 37.7, -122.3, 37.8)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	_, err = NewGeocode(b).ForwardWithBBox("coffee", nil, bbox)
+	assert.Nil(t, err)
+}
+
+func TestParseMatchCode(t *testing.T) {
+	f := base.Feature{}
+	err := json.Unmarshal([]byte(addressFeature), &f)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	code, err := ParseMatchCode(f.RawProperties)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.EqualValues(t, ConfidenceExact, code.Confidence)
+	assert.True(t, code.IsReliable())
+
+	code, err = ParseMatchCode(map[string]interface{}{"name": "Washington"})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.EqualValues(t, &MatchCode{}, code)
+	assert.False(t, code.IsReliable())
+
+	assert.False(t, (&MatchCode{Confidence: ConfidenceMedium}).IsReliable())
+
+	_, err = ParseMatchCode(map[string]interface{}{"match_code": "exact"})
+	assert.NotNil(t, err)
+}
+
+func TestPickBest(t *testing.T) {
+	tests := []struct {
+		name     string
+		features string
+		id       string
+	}{
+		{"Prefers relevance", `[
+			{"id":"a","type":"Feature","properties":{"feature_type":"address","relevance":0.8}},
+			{"id":"b","type":"Feature","properties":{"feature_type":"place","relevance":0.95}}
+		]`, "b"},
+		{"Falls back to the relevance field", `[
+			{"id":"a","type":"Feature","place_type":["poi"],"relevance":0.5,"properties":{}},
+			{"id":"b","type":"Feature","place_type":["poi"],"relevance":0.9,"properties":{}}
+		]`, "b"},
+		{"Prefers addresses over places", `[
+			{"id":"a","type":"Feature","properties":{"feature_type":"poi","relevance":1}},
+			{"id":"b","type":"Feature","properties":{"feature_type":"place","relevance":1}},
+			{"id":"c","type":"Feature","properties":{"feature_type":"address","relevance":1}}
+		]`, "c"},
+		{"Prefers reliable matches", `[
+			{"id":"a","type":"Feature","properties":{"feature_type":"address","relevance":1,"match_code":{"confidence":"medium"}}},
+			{"id":"b","type":"Feature","properties":{"feature_type":"address","relevance":1,"match_code":{"confidence":"exact"}}}
+		]`, "b"},
+		{"Keeps the first of equal features", `[
+			{"id":"a","type":"Feature","properties":{"feature_type":"address","relevance":1,"match_code":{"confidence":"high"}}},
+			{"id":"b","type":"Feature","properties":{"feature_type":"address","relevance":1,"match_code":{"confidence":"exact"}}}
+		]`, "a"},
+		{"Ranks v5 features by relevance", `[
+			{"id":"address.1","type":"Feature","place_type":["address"],"relevance":0.7,"properties":{"accuracy":"point"}},
+			{"id":"place.2","type":"Feature","place_type":["place"],"relevance":0.99,"properties":{"wikidata":"Q61"}}
+		]`, "place.2"},
+		{"Ranks v5 features by place type", `[
+			{"id":"poi.1","type":"Feature","place_type":["poi"],"relevance":1,"properties":{"category":"monument"}},
+			{"id":"place.2","type":"Feature","place_type":["place"],"relevance":1,"properties":{"wikidata":"Q61"}},
+			{"id":"address.3","type":"Feature","place_type":["address"],"relevance":1,"properties":{"accuracy":"point"}}
+		]`, "address.3"},
+		{"Keeps the first of equal v5 features", `[
+			{"id":"address.1","type":"Feature","place_type":["address"],"relevance":1,"properties":{"accuracy":"street"}},
+			{"id":"address.2","type":"Feature","place_type":["address"],"relevance":1,"properties":{"accuracy":"point"}}
+		]`, "address.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := ForwardResponse{}
+			err := json.Unmarshal([]byte(`{"type":"FeatureCollection","features":`+tt.features+`}`), &resp)
+			if !assert.Nil(t, err) {
+				t.FailNow()
+			}
+
+			best, ok := resp.PickBest()
+			if assert.True(t, ok) {
+				assert.EqualValues(t, tt.id, best.ID)
+			}
+		})
+	}
+
+	t.Run("Handles empty responses", func(t *testing.T) {
+		_, ok := (&ForwardResponse{}).PickBest()
+		assert.False(t, ok)
+		_, ok = (&ForwardResponse{FeatureCollection: &base.FeatureCollection{}}).PickBest()
+		assert.False(t, ok)
+	})
+}
+
+func TestGeocodingFeature(t *testing.T) {
+	v5Feature := `{"id":"place.123","type":"Feature","text":"Sydney","place_name":"Sydney, New South Wales, Australia","place_type":["place"],"properties":{"wikidata":"Q3130"},"geometry":{"type":"Point","coordinates":[151.2,-33.8]}}`
+	data := `{"type":"FeatureCollection","features":[` + addressFeature + `,` + v5Feature + `]}`
+
+	resp := ForwardResponse{}
+	err := json.Unmarshal([]byte(data), &resp)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	features := make([]*GeocodingFeature, 0)
+	resp.Each(func(f *GeocodingFeature) bool {
+		features = append(features, f)
+		return true
+	})
+	if !assert.Len(t, features, 2) {
+		t.FailNow()
+	}
+
+	tests := []struct {
+		name        string
+		feature     *GeocodingFeature
+		longitude   float64
+		latitude    float64
+		featureName string
+		fullAddress string
+		featureType Type
+		confidence  ConfidenceLevel
+	}{
+		{"v6 address", features[0], -77.050119, 38.889859, "2 Lincoln Memorial Circle Northwest",
+			"2 Lincoln Memorial Circle Northwest, Washington, District of Columbia 20037, United States", Address, ConfidenceExact},
+		{"v5 place", features[1], 151.2, -33.8, "Sydney", "Sydney, New South Wales, Australia", Place, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualValues(t, tt.longitude, tt.feature.Longitude())
+			assert.EqualValues(t, tt.latitude, tt.feature.Latitude())
+			assert.EqualValues(t, tt.featureName, tt.feature.Name())
+			assert.EqualValues(t, tt.fullAddress, tt.feature.FullAddress())
+			assert.EqualValues(t, tt.featureType, tt.feature.FeatureType())
+			assert.EqualValues(t, tt.confidence, tt.feature.MatchCode().Confidence)
+		})
+	}
+
+	t.Run("Stops iteration", func(t *testing.T) {
+		count := 0
+		resp.Each(func(f *GeocodingFeature) bool {
+			count++
+			return false
+		})
+		assert.EqualValues(t, 1, count)
+	})
+
+	t.Run("Returns the first feature", func(t *testing.T) {
+		f, ok := resp.First()
+		assert.True(t, ok)
+		assert.EqualValues(t, "address", f.FeatureType())
+
+		f, ok = (&ReverseResponse{}).First()
+		assert.False(t, ok)
+		assert.Nil(t, f)
+	})
+}
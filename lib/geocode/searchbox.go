@@ -0,0 +1,190 @@
+/**
+ * go-mapbox Geocoding Module Search Box Requests
+ * Wraps the mapbox search box API for server side use
+ * See https://docs.mapbox.com/api/search/search-box/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package geocode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/google/go-querystring/query"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const (
+	apiSearchBoxName    = "search/searchbox"
+	apiSearchBoxVersion = "v1"
+)
+
+// SuggestOpts request options for search box suggestions
+type SuggestOpts struct {
+	// SessionToken groups suggest and retrieve requests into a billing session, see NewSessionToken
+	SessionToken string           `url:"session_token"`
+	Proximity    string           `url:"proximity,omitempty"`
+	BBox         base.BoundingBox `url:"bbox,omitempty,comma"`
+	Country      string           `url:"country,omitempty"`
+	Language     Language         `url:"language,omitempty"`
+	Limit        uint             `url:"limit,omitempty"`
+	Types        Types            `url:"types,omitempty"`
+}
+
+// Suggestion is a candidate result of a search box suggest request
+// Suggestions do not include coordinates, these are fetched by retrieving the suggestion by MapboxID
+type Suggestion struct {
+	Name           string  `json:"name"`
+	NamePreferred  string  `json:"name_preferred"`
+	MapboxID       string  `json:"mapbox_id"`
+	FeatureType    string  `json:"feature_type"`
+	Address        string  `json:"address"`
+	FullAddress    string  `json:"full_address"`
+	PlaceFormatted string  `json:"place_formatted"`
+	Distance       float64 `json:"distance"`
+	Maki           string  `json:"maki"`
+}
+
+// SuggestResponse is the response to a search box suggest request
+type SuggestResponse struct {
+	Suggestions []Suggestion `json:"suggestions"`
+	Attribution string       `json:"attribution"`
+}
+
+// SearchBoxSuggest fetches ranked suggestions for a partial search query
+func (g *Geocode) SearchBoxSuggest(ctx context.Context, q string, opts *SuggestOpts) (*SuggestResponse, error) {
+	if opts == nil || opts.SessionToken == "" {
+		return nil, errors.New("Suggest requests require a session token, see NewSessionToken")
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	v, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+	v.Set("q", q)
+
+	resp := SuggestResponse{}
+
+	queryString := fmt.Sprintf("%s/%s/suggest", apiSearchBoxName, apiSearchBoxVersion)
+
+	err = g.base.QueryBaseContext(ctx, queryString, &v, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// RetrieveResponse is the response to a search box retrieve request
+type RetrieveResponse struct {
+	*base.FeatureCollection
+}
+
+// AddressComponents are the named components of a feature address
+type AddressComponents struct {
+	AddressNumber string
+	Street        string
+	Neighborhood  string
+	Locality      string
+	Postcode      string
+	Place         string
+	District      string
+	Region        string
+	Country       string
+}
+
+// SearchBoxRetrieve resolves a suggestion to a full feature by its MapboxID
+// The session token must match that used for the preceding SearchBoxSuggest requests
+func (g *Geocode) SearchBoxRetrieve(ctx context.Context, mapboxID string, sessionToken string) (*RetrieveResponse, error) {
+	if sessionToken == "" {
+		return nil, errors.New("Retrieve requests require a session token, see NewSessionToken")
+	}
+
+	v := url.Values{}
+	v.Set("session_token", sessionToken)
+
+	resp := RetrieveResponse{}
+
+	queryString := fmt.Sprintf("%s/%s/retrieve/%s", apiSearchBoxName, apiSearchBoxVersion, url.PathEscape(mapboxID))
+
+	err := g.base.QueryBaseContext(ctx, queryString, &v, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// properties parses the properties of the retrieved feature
+func (r *RetrieveResponse) properties() (GeocodingProperties, error) {
+	if r.FeatureCollection == nil || len(r.Features) == 0 {
+		return GeocodingProperties{}, errors.New("Retrieve response contains no features")
+	}
+
+	return ParseProperties(r.Features[0])
+}
+
+// Coordinates returns the coordinates (including routable points) of the retrieved feature
+func (r *RetrieveResponse) Coordinates() (*Coordinates, error) {
+	props, err := r.properties()
+	if err != nil {
+		return nil, err
+	}
+
+	return &props.Coordinates, nil
+}
+
+// Location returns the location of the retrieved feature
+func (r *RetrieveResponse) Location() (*base.Location, error) {
+	c, err := r.Coordinates()
+	if err != nil {
+		return nil, err
+	}
+
+	return &base.Location{Latitude: c.Latitude, Longitude: c.Longitude}, nil
+}
+
+// AddressComponents returns the address components of the retrieved feature from its context
+func (r *RetrieveResponse) AddressComponents() (*AddressComponents, error) {
+	props, err := r.properties()
+	if err != nil {
+		return nil, err
+	}
+
+	name := func(e *ContextEntry) string {
+		if e == nil {
+			return ""
+		}
+		return e.Name
+	}
+
+	c := props.Context
+	address := &AddressComponents{
+		Street:       name(c.Street),
+		Neighborhood: name(c.Neighborhood),
+		Locality:     name(c.Locality),
+		Postcode:     name(c.Postcode),
+		Place:        name(c.Place),
+		District:     name(c.District),
+	}
+	if c.Address != nil {
+		address.AddressNumber = c.Address.AddressNumber
+	}
+	if c.Region != nil {
+		address.Region = c.Region.Name
+	}
+	if c.Country != nil {
+		address.Country = c.Country.Name
+	}
+
+	return address, nil
+}
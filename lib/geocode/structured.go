@@ -0,0 +1,100 @@
+/**
+ * go-mapbox Geocoding Module Structured Input
+ * Wraps the mapbox v6 structured input geocoding API for server side use
+ * See https://docs.mapbox.com/api/search/geocoding/#forward-geocoding-with-structured-input for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package geocode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-querystring/query"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const apiForwardMode = "forward"
+
+// StructuredInputOpts are the address components and options of a structured forward geocode request
+// At least one address component must be provided
+type StructuredInputOpts struct {
+	AddressLine1  string `url:"address_line1,omitempty"`
+	AddressNumber string `url:"address_number,omitempty"`
+	Street        string `url:"street,omitempty"`
+	Block         string `url:"block,omitempty"`
+	Place         string `url:"place,omitempty"`
+	Region        string `url:"region,omitempty"`
+	Postcode      string `url:"postcode,omitempty"`
+	Locality      string `url:"locality,omitempty"`
+	Neighborhood  string `url:"neighborhood,omitempty"`
+	// Country is an ISO 3166-1 alpha-2 code, see CountryCode
+	Country string `url:"country,omitempty"`
+
+	Types     Types            `url:"types,omitempty"`
+	Limit     uint             `url:"limit,omitempty"`
+	BBox      base.BoundingBox `url:"bbox,omitempty"`
+	Language  Language         `url:"language,omitempty"`
+	Worldview Worldview        `url:"worldview,omitempty"`
+	Permanent bool             `url:"permanent,omitempty"`
+}
+
+// validate checks structured input options before a request is made
+func (o *StructuredInputOpts) validate() error {
+	components := []string{o.AddressLine1, o.AddressNumber, o.Street, o.Block, o.Place, o.Region, o.Postcode, o.Locality, o.Neighborhood, o.Country}
+	empty := true
+	for _, c := range components {
+		if c != "" {
+			empty = false
+			break
+		}
+	}
+	if empty {
+		return &base.ValidationError{Field: "address_line1", Message: "at least one address component is required"}
+	}
+
+	if o.Limit > MaxForwardLimit {
+		return &base.ValidationError{Field: "limit", Message: fmt.Sprintf("%d out of range (maximum %d)", o.Limit, MaxForwardLimit)}
+	}
+	if err := validateLanguage(o.Language); err != nil {
+		return err
+	}
+	if err := validateCountry(o.Country); err != nil {
+		return err
+	}
+	if err := validateBBox(o.BBox); err != nil {
+		return err
+	}
+	return o.Worldview.Validate()
+}
+
+// ForwardStructured geocodes an address from its components using the v6 API
+// Features include v6 properties such as the match code, see ForwardResponse.MatchCodes
+func (g *Geocode) ForwardStructured(ctx context.Context, opts *StructuredInputOpts) (*ForwardResponse, error) {
+	if opts == nil {
+		opts = &StructuredInputOpts{}
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	v, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := ForwardResponse{}
+
+	queryString := fmt.Sprintf("%s/%s/%s", apiBatchName, apiBatchVersion, apiForwardMode)
+
+	err = g.base.QueryBaseContext(ctx, queryString, &v, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,400 @@
+/**
+ * go-mapbox Geocoding Module Types
+ * Wraps the mapbox geocoding API for server side use
+ * See https://docs.mapbox.com/api/search/geocoding/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package geocode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+// GeocodingProperties is a typed view of the properties object of a geocoding feature
+// https://docs.mapbox.com/api/search/geocoding/#the-properties-object
+type GeocodingProperties struct {
+	MapboxID       string           `json:"mapbox_id"`
+	FeatureType    string           `json:"feature_type"`
+	Name           string           `json:"name"`
+	NamePreferred  string           `json:"name_preferred"`
+	Address        string           `json:"address"`
+	FullAddress    string           `json:"full_address"`
+	PlaceFormatted string           `json:"place_formatted"`
+	Context        GeocodingContext `json:"context"`
+	Coordinates    Coordinates      `json:"coordinates"`
+	BBox           []float64        `json:"bbox"`
+	Language       string           `json:"language"`
+	MatchCode      MatchCode        `json:"match_code"`
+}
+
+// FeatureProperties is a typed view of the properties object of a geocoding feature
+// Deprecated: use GeocodingProperties
+type FeatureProperties = GeocodingProperties
+
+// GeocodingContext describes the hierarchy of features containing a geocoding feature
+// Context levels that do not apply to a feature are nil
+// https://docs.mapbox.com/api/search/geocoding/#the-context-object
+type GeocodingContext struct {
+	Address      *ContextAddress `json:"address"`
+	Street       *ContextEntry   `json:"street"`
+	Neighborhood *ContextEntry   `json:"neighborhood"`
+	Locality     *ContextEntry   `json:"locality"`
+	Postcode     *ContextEntry   `json:"postcode"`
+	Place        *ContextEntry   `json:"place"`
+	District     *ContextEntry   `json:"district"`
+	Region       *ContextRegion  `json:"region"`
+	Country      *ContextCountry `json:"country"`
+}
+
+// Context is the hierarchy of features containing a geocoding feature
+type Context = GeocodingContext
+
+// ContextLevels are the levels of a geocoding context, from the broadest to the most specific
+var ContextLevels = []string{"country", "region", "district", "place", "locality", "postcode", "neighborhood", "street", "address"}
+
+// ContextMap returns the levels of the feature context keyed by level name, omitting levels that do not apply
+// Iterate ContextLevels to visit the entries in order, for example to build breadcrumbs
+func (p GeocodingProperties) ContextMap() map[string]ContextEntry {
+	c := p.Context
+	m := make(map[string]ContextEntry)
+
+	if c.Country != nil {
+		m["country"] = c.Country.ContextEntry
+	}
+	if c.Region != nil {
+		m["region"] = c.Region.ContextEntry
+	}
+	for level, e := range map[string]*ContextEntry{
+		"district":     c.District,
+		"place":        c.Place,
+		"locality":     c.Locality,
+		"postcode":     c.Postcode,
+		"neighborhood": c.Neighborhood,
+		"street":       c.Street,
+	} {
+		if e != nil {
+			m[level] = *e
+		}
+	}
+	if c.Address != nil {
+		m["address"] = c.Address.ContextEntry
+	}
+
+	return m
+}
+
+// ContextEntry is a single level of a geocoding context
+type ContextEntry struct {
+	MapboxID   string `json:"mapbox_id"`
+	Name       string `json:"name"`
+	WikidataID string `json:"wikidata_id"`
+}
+
+// ContextAddress is the address level of a geocoding context
+type ContextAddress struct {
+	ContextEntry
+	AddressNumber string `json:"address_number"`
+	StreetName    string `json:"street_name"`
+}
+
+// ContextRegion is the region level of a geocoding context
+type ContextRegion struct {
+	ContextEntry
+	RegionCode     string `json:"region_code"`
+	RegionCodeFull string `json:"region_code_full"`
+}
+
+// ContextCountry is the country level of a geocoding context
+type ContextCountry struct {
+	ContextEntry
+	CountryCode       string `json:"country_code"`
+	CountryCodeAlpha3 string `json:"country_code_alpha_3"`
+}
+
+// GeocodingFeature wraps a feature returned by the geocoding API with typed accessors
+type GeocodingFeature struct {
+	base.Feature
+}
+
+// GeocodingProperties decodes the properties of the feature
+func (f *GeocodingFeature) GeocodingProperties() (*GeocodingProperties, error) {
+	props, err := ParseProperties(f.Feature)
+	if err != nil {
+		return nil, err
+	}
+
+	return &props, nil
+}
+
+// Coordinates contains the location of a feature and any routable points
+// https://docs.mapbox.com/api/search/geocoding/#the-coordinates-object
+type Coordinates struct {
+	Longitude      float64         `json:"longitude"`
+	Latitude       float64         `json:"latitude"`
+	Accuracy       string          `json:"accuracy"`
+	RoutablePoints []RoutablePoint `json:"routable_points"`
+}
+
+// RoutablePoint is a point on the road network suitable for navigation to a feature
+type RoutablePoint struct {
+	Name      string  `json:"name"`
+	Longitude float64 `json:"longitude"`
+	Latitude  float64 `json:"latitude"`
+}
+
+// MatchCode describes how each component of a query matched the returned feature
+// https://docs.mapbox.com/api/search/geocoding/#the-match_code-object
+type MatchCode struct {
+	AddressNumber string          `json:"address_number"`
+	Street        string          `json:"street"`
+	Postcode      string          `json:"postcode"`
+	Place         string          `json:"place"`
+	Region        string          `json:"region"`
+	Locality      string          `json:"locality"`
+	Country       string          `json:"country"`
+	Confidence    ConfidenceLevel `json:"confidence"`
+}
+
+// ConfidenceLevel is the overall confidence that a feature matches a structured query
+type ConfidenceLevel string
+
+const (
+	// ConfidenceExact all components of the query matched
+	ConfidenceExact ConfidenceLevel = "exact"
+	// ConfidenceHigh the feature is very likely to match the query
+	ConfidenceHigh ConfidenceLevel = "high"
+	// ConfidenceMedium the feature may match the query
+	ConfidenceMedium ConfidenceLevel = "medium"
+	// ConfidenceLow the feature is unlikely to match the query
+	ConfidenceLow ConfidenceLevel = "low"
+	// ConfidenceNotApplicable confidence is not available for the feature type
+	ConfidenceNotApplicable ConfidenceLevel = "not_applicable"
+)
+
+// IsReliable indicates whether the match confidence is exact or high
+func (m *MatchCode) IsReliable() bool {
+	return m.Confidence == ConfidenceExact || m.Confidence == ConfidenceHigh
+}
+
+// ParseMatchCode decodes the match code from the raw properties of a feature
+// A zero value MatchCode is returned where the properties have no match code, as
+// is the case for reverse geocoding and queries not using structured input
+func ParseMatchCode(raw map[string]interface{}) (*MatchCode, error) {
+	code := MatchCode{}
+
+	value, ok := raw["match_code"]
+	if !ok || value == nil {
+		return &code, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &code, nil
+}
+
+// stringProperty returns a string property of the feature, or an empty string where it is not set
+func (f *GeocodingFeature) stringProperty(key string) string {
+	value, _ := f.RawProperties[key].(string)
+	return value
+}
+
+// Longitude returns the longitude of the feature, or zero where it has no location
+func (f *GeocodingFeature) Longitude() float64 {
+	loc, err := FeatureLocation(f.Feature)
+	if err != nil {
+		return 0
+	}
+	return loc.Longitude
+}
+
+// Latitude returns the latitude of the feature, or zero where it has no location
+func (f *GeocodingFeature) Latitude() float64 {
+	loc, err := FeatureLocation(f.Feature)
+	if err != nil {
+		return 0
+	}
+	return loc.Latitude
+}
+
+// Name returns the name of the feature, falling back to the v5 text field
+func (f *GeocodingFeature) Name() string {
+	if name := f.stringProperty("name"); name != "" {
+		return name
+	}
+	return f.Text
+}
+
+// FullAddress returns the full address of the feature, falling back to the v5 place name
+func (f *GeocodingFeature) FullAddress() string {
+	if address := f.stringProperty("full_address"); address != "" {
+		return address
+	}
+	return f.PlaceName
+}
+
+// FeatureType returns the type of the feature, falling back to the first v5 place type
+func (f *GeocodingFeature) FeatureType() Type {
+	if t := f.stringProperty("feature_type"); t != "" {
+		return Type(t)
+	}
+	if len(f.PlaceType) > 0 {
+		return Type(f.PlaceType[0])
+	}
+	return ""
+}
+
+// MatchCode returns the match code of the feature, or a zero value where it has none
+func (f *GeocodingFeature) MatchCode() MatchCode {
+	code, err := ParseMatchCode(f.RawProperties)
+	if err != nil {
+		return MatchCode{}
+	}
+	return *code
+}
+
+// eachFeature calls fn for each feature in the collection until fn returns false
+func eachFeature(fc *base.FeatureCollection, fn func(f *GeocodingFeature) bool) {
+	if fc == nil {
+		return
+	}
+	for _, f := range fc.Features {
+		if !fn(&GeocodingFeature{f}) {
+			return
+		}
+	}
+}
+
+// firstFeature returns the first feature in the collection
+func firstFeature(fc *base.FeatureCollection) (*GeocodingFeature, bool) {
+	if fc == nil || len(fc.Features) == 0 {
+		return nil, false
+	}
+	return &GeocodingFeature{fc.Features[0]}, true
+}
+
+// ParseProperties decodes the raw properties of a feature into a GeocodingProperties object
+// The raw properties remain available via Feature.RawProperties
+func ParseProperties(f base.Feature) (GeocodingProperties, error) {
+	props := GeocodingProperties{}
+
+	if f.RawProperties == nil {
+		return props, nil
+	}
+
+	data, err := json.Marshal(f.RawProperties)
+	if err != nil {
+		return props, err
+	}
+
+	err = json.Unmarshal(data, &props)
+
+	return props, err
+}
+
+// FeatureLocation returns the location of a point feature
+func FeatureLocation(f base.Feature) (base.Location, error) {
+	if f.Geometry.Type == "" {
+		return base.Location{}, fmt.Errorf("Feature %s has no geometry", f.ID)
+	}
+	if f.Geometry.Type != "Point" {
+		return base.Location{}, fmt.Errorf("Feature %s has non-point geometry (type: %s)", f.ID, f.Geometry.Type)
+	}
+	if len(f.Geometry.Coordinates) != 2 {
+		return base.Location{}, fmt.Errorf("Feature %s has malformed point geometry (%d coordinates)", f.ID, len(f.Geometry.Coordinates))
+	}
+
+	return base.Location{
+		Longitude: f.Geometry.Coordinates[0],
+		Latitude:  f.Geometry.Coordinates[1],
+	}, nil
+}
+
+// routablePoint returns the first routable point of a feature, or the feature location (and false) where there is none
+// Routable points are read from the v5 feature (returned with routing requested), falling back to v6 coordinates
+func routablePoint(fc *base.FeatureCollection, idx int) (*base.Location, bool) {
+	if fc == nil || idx < 0 || idx >= len(fc.Features) {
+		return nil, false
+	}
+	f := fc.Features[idx]
+
+	if f.RoutablePoints != nil {
+		for _, p := range f.RoutablePoints.Points {
+			if len(p.Coordinates) >= 2 {
+				return &base.Location{Latitude: p.Coordinates[1], Longitude: p.Coordinates[0]}, true
+			}
+		}
+	}
+
+	props, err := ParseProperties(f)
+	if err == nil && len(props.Coordinates.RoutablePoints) > 0 {
+		p := props.Coordinates.RoutablePoints[0]
+		return &base.Location{Latitude: p.Latitude, Longitude: p.Longitude}, true
+	}
+
+	loc, err := FeatureLocation(f)
+	if err != nil {
+		return nil, false
+	}
+
+	return &loc, false
+}
+
+// Worldview selects the boundaries and names returned for disputed areas
+// Worldviews apply to features whose boundaries or names differ between countries' official positions
+type Worldview string
+
+const (
+	// WorldviewDefault leaves the worldview unset, using the API default (WorldviewUS)
+	WorldviewDefault Worldview = ""
+	// WorldviewAll returns features for all worldviews
+	WorldviewAll Worldview = "all"
+	// WorldviewAR Argentina, for example the Falkland Islands (Islas Malvinas)
+	WorldviewAR Worldview = "ar"
+	// WorldviewCN China, for example Taiwan and the China-India border regions
+	WorldviewCN Worldview = "cn"
+	// WorldviewIN India, for example Kashmir and the China-India border regions
+	WorldviewIN Worldview = "in"
+	// WorldviewJP Japan, for example the Kuril Islands and Liancourt Rocks
+	WorldviewJP Worldview = "jp"
+	// WorldviewMA Morocco, for example Western Sahara
+	WorldviewMA Worldview = "ma"
+	// WorldviewRS Serbia, for example Kosovo
+	WorldviewRS Worldview = "rs"
+	// WorldviewRU Russia, for example Crimea
+	WorldviewRU Worldview = "ru"
+	// WorldviewTR Turkey, for example Northern Cyprus
+	WorldviewTR Worldview = "tr"
+	// WorldviewUS United States (the API default)
+	WorldviewUS Worldview = "us"
+)
+
+var worldviews = map[Worldview]bool{
+	WorldviewAll: true, WorldviewAR: true, WorldviewCN: true, WorldviewIN: true, WorldviewJP: true,
+	WorldviewMA: true, WorldviewRS: true, WorldviewRU: true, WorldviewTR: true, WorldviewUS: true,
+}
+
+// Validate checks the worldview is supported by the API, unset worldviews are valid
+func (w Worldview) Validate() error {
+	if w != WorldviewDefault && !worldviews[w] {
+		return &base.ValidationError{Field: "worldview", Message: fmt.Sprintf("%q is not supported", string(w))}
+	}
+	return nil
+}
+
+// Language is a language code used for feature names, or a comma separated list of codes
+// Supported codes and their validation are defined by base.LanguageCode
+type Language = base.LanguageCode
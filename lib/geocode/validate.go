@@ -0,0 +1,132 @@
+/**
+ * go-mapbox Geocoding Module Validation
+ * Validates request options before requests are made
+ * See https://docs.mapbox.com/api/search/geocoding/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package geocode
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const (
+	// MaxForwardLimit is the maximum number of results of a forward geocode request
+	MaxForwardLimit = 10
+	// MaxReverseLimit is the maximum number of results of a reverse geocode request
+	MaxReverseLimit = 5
+)
+
+// validate checks forward request options before a request is made
+func (o *ForwardRequestOpts) validate() error {
+	if o.Limit > MaxForwardLimit {
+		return &base.ValidationError{Field: "limit", Message: fmt.Sprintf("%d out of range (maximum %d)", o.Limit, MaxForwardLimit)}
+	}
+	if err := validateLanguage(o.Language); err != nil {
+		return err
+	}
+	if err := validateCountry(o.Country); err != nil {
+		return err
+	}
+	if err := validateBBox(o.BBox); err != nil {
+		return err
+	}
+	if len(o.Proximity) != 0 && len(o.Proximity) != 2 {
+		return &base.ValidationError{Field: "proximity", Message: fmt.Sprintf("%d coordinates provided, a lng,lat pair is required", len(o.Proximity))}
+	}
+	if len(o.Proximity) != 0 && o.IPProximity {
+		return &base.ValidationError{Field: "proximity", Message: "only one of Proximity and IPProximity may be set"}
+	}
+	return o.Worldview.Validate()
+}
+
+// validate checks reverse request options before a request is made
+func (o *ReverseRequestOpts) validate() error {
+	if o.Limit > MaxReverseLimit {
+		return &base.ValidationError{Field: "limit", Message: fmt.Sprintf("%d out of range (maximum %d)", o.Limit, MaxReverseLimit)}
+	}
+	if err := validateLanguage(o.Language); err != nil {
+		return err
+	}
+	if err := validateCountry(o.Country); err != nil {
+		return err
+	}
+	return o.Worldview.Validate()
+}
+
+// validate checks suggest request options before a request is made
+func (o *SuggestOpts) validate() error {
+	if o.Limit > MaxForwardLimit {
+		return &base.ValidationError{Field: "limit", Message: fmt.Sprintf("%d out of range (maximum %d)", o.Limit, MaxForwardLimit)}
+	}
+	if err := validateLanguage(o.Language); err != nil {
+		return err
+	}
+	if err := validateCountry(o.Country); err != nil {
+		return err
+	}
+	return validateBBox(o.BBox)
+}
+
+// validateLanguage checks a comma separated list of languages are supported, see base.ValidLanguageCode
+func validateLanguage(l Language) error {
+	if l == "" {
+		return nil
+	}
+
+	for _, tag := range strings.Split(string(l), ",") {
+		if !base.ValidLanguageCode(tag) {
+			return &base.ValidationError{Field: "language", Message: fmt.Sprintf("unsupported language code %q", tag)}
+		}
+	}
+
+	return nil
+}
+
+// validateCountry checks a comma separated list of countries are ISO 3166-1 alpha-2 codes, see TypedCountry
+func validateCountry(c string) error {
+	if c == "" {
+		return nil
+	}
+
+	for _, code := range strings.Split(c, ",") {
+		if !ValidCountryCode(code) {
+			return &base.ValidationError{Field: "country", Message: fmt.Sprintf("%q is not an ISO 3166-1 alpha-2 code", code)}
+		}
+	}
+
+	return nil
+}
+
+// validateBBox checks a bounding box has valid minimum and maximum coordinates
+func validateBBox(bb base.BoundingBox) error {
+	if len(bb) == 0 {
+		return nil
+	}
+	if len(bb) != 4 {
+		return &base.ValidationError{Field: "bbox", Message: fmt.Sprintf("%d coordinates provided (expected 4)", len(bb))}
+	}
+
+	for _, f := range bb {
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return &base.ValidationError{Field: "bbox", Message: "coordinates must be finite"}
+		}
+	}
+
+	minLng, minLat, maxLng, maxLat := bb[0], bb[1], bb[2], bb[3]
+	if minLng < -180 || maxLng > 180 || minLat < -90 || maxLat > 90 {
+		return &base.ValidationError{Field: "bbox", Message: "coordinates out of range"}
+	}
+	if minLng >= maxLng || minLat >= maxLat {
+		return &base.ValidationError{Field: "bbox", Message: "minimum coordinates must be less than maximum coordinates"}
+	}
+
+	return nil
+}
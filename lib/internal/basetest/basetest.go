@@ -0,0 +1,41 @@
+/**
+ * go-mapbox Base Test Helpers
+ * Provides a mock API server for testing API modules
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package basetest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+// Token is the access token used by NewBase
+const Token = "test-token"
+
+// NewBase creates a base instance for the provided handler, see NewBaseWithToken
+func NewBase(t *testing.T, handler http.HandlerFunc, opts ...base.Option) *base.Base {
+	return NewBaseWithToken(t, Token, handler, opts...)
+}
+
+// NewBaseWithToken creates a base instance with the provided token that sends requests to the provided handler
+// The test server is closed when the test completes
+func NewBaseWithToken(t *testing.T, token string, handler http.HandlerFunc, opts ...base.Option) *base.Base {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	b, err := base.NewBase(token, append([]base.Option{base.WithBaseURL(server.URL)}, opts...)...)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	return b
+}
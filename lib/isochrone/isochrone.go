@@ -0,0 +1,196 @@
+/**
+ * go-mapbox Isochrone Module
+ * Wraps the mapbox isochrone API for server side use
+ * See https://docs.mapbox.com/api/navigation/isochrone/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package isochrone
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/go-querystring/query"
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const (
+	apiName    = "isochrone"
+	apiVersion = "v1"
+
+	// MaxContours is the maximum number of contours in a single request
+	MaxContours = 4
+	// MaxContourMinutes is the maximum time (in minutes) of a contour
+	MaxContourMinutes = 60
+	// MaxContourMeters is the maximum distance (in meters) of a contour
+	MaxContourMeters = 100000
+)
+
+// RoutingProfile defines routing mode for isochrone generation
+type RoutingProfile string
+
+const (
+	// RoutingDrivingTraffic mode for automotive routing takes into account current and historic traffic
+	RoutingDrivingTraffic RoutingProfile = "mapbox/driving-traffic"
+	// RoutingDriving mode for for automovide routing
+	RoutingDriving RoutingProfile = "mapbox/driving"
+	// RoutingWalking mode for Pedestrian routing
+	RoutingWalking RoutingProfile = "mapbox/walking"
+	// RoutingCycling mode for bicycle routing
+	RoutingCycling RoutingProfile = "mapbox/cycling"
+)
+
+// Isochrone api wrapper instance
+type Isochrone struct {
+	base *base.Base
+}
+
+// NewIsochrone Create a new Isochrone API wrapper
+func NewIsochrone(base *base.Base) *Isochrone {
+	return &Isochrone{base}
+}
+
+// IsochroneOpts request options for the isochrone api
+// Exactly one of ContoursMinutes and ContoursMeters must be set, with up to MaxContours contours
+type IsochroneOpts struct {
+	ContoursMinutes []int `url:"contours_minutes,omitempty,comma"`
+	ContoursMeters  []int `url:"contours_meters,omitempty,comma"`
+	// ContoursColors are hex colors (without a leading #) for each contour
+	ContoursColors []string `url:"contours_colors,omitempty,comma"`
+	// Polygons returns contours as polygons rather than linestrings
+	Polygons bool `url:"polygons,omitempty"`
+	// Denoise (0 to 1) removes contours smaller than this fraction of the largest contour, defaulting to 1
+	// This is a pointer so a denoise of 0 can be sent rather than falling back to the default
+	Denoise *float64 `url:"denoise,omitempty"`
+	// Generalize is the tolerance (in meters) used to simplify contours
+	Generalize float64 `url:"generalize,omitempty"`
+}
+
+// ContourConflictError is returned when both time and distance contours are requested
+type ContourConflictError struct{}
+
+func (e *ContourConflictError) Error() string {
+	return "Isochrone contours_minutes and contours_meters cannot be used together"
+}
+
+// validate checks the contours are within the API limits
+func (o *IsochroneOpts) validate() error {
+	if len(o.ContoursMinutes) > 0 && len(o.ContoursMeters) > 0 {
+		return &ContourConflictError{}
+	}
+
+	field, contours, max := "contours_minutes", o.ContoursMinutes, MaxContourMinutes
+	if len(o.ContoursMeters) > 0 {
+		field, contours, max = "contours_meters", o.ContoursMeters, MaxContourMeters
+	}
+
+	if len(contours) == 0 {
+		return &base.ValidationError{Field: "contours", Message: "one of contours_minutes or contours_meters is required"}
+	}
+	if len(contours) > MaxContours {
+		return &base.ValidationError{Field: field, Message: fmt.Sprintf("%d contours provided, maximum %d", len(contours), MaxContours)}
+	}
+	for _, c := range contours {
+		if c <= 0 || c > max {
+			return &base.ValidationError{Field: field, Message: fmt.Sprintf("%d is out of range (maximum %d)", c, max)}
+		}
+	}
+
+	if len(o.ContoursColors) > 0 && len(o.ContoursColors) != len(contours) {
+		return &base.ValidationError{Field: "contours_colors", Message: fmt.Sprintf("%d colors provided for %d contours", len(o.ContoursColors), len(contours))}
+	}
+
+	return nil
+}
+
+// IsochroneResponse is the response from GetIsochrone
+// Contours are returned as features, with the contour value and color in the feature properties
+type IsochroneResponse struct {
+	base.FeatureCollection
+}
+
+// Contour metrics
+const (
+	MetricTime     = "time"
+	MetricDistance = "distance"
+)
+
+// Contour is a single isochrone contour
+type Contour struct {
+	// Metric is the contour metric, either MetricTime or MetricDistance
+	Metric string
+	// Value is the contour time (in minutes) or distance (in meters)
+	Value float64
+	// Minutes is the travel time of a contour requested with ContoursMinutes
+	Minutes int
+	// Meters is the travel distance of a contour requested with ContoursMeters
+	Meters int
+	// Color is the hex color of the contour (without a leading #)
+	Color string
+	// Geometry is the Polygon (where IsochroneOpts.Polygons is set) or LineString geometry of the contour
+	Geometry base.Geometry
+}
+
+// ContourFeature is a single isochrone contour
+// Contours are exposed by the IsochroneResponse.Contours method (which sorts them) rather than a
+// Contours field, as a field of the same name would conflict with the method
+type ContourFeature = Contour
+
+// Contours returns the contours of the response sorted from smallest to largest
+func (r *IsochroneResponse) Contours() []Contour {
+	contours := make([]Contour, len(r.Features))
+
+	for i, f := range r.Features {
+		value, _ := f.RawProperties["contour"].(float64)
+		color, _ := f.RawProperties["color"].(string)
+		metric, _ := f.RawProperties["metric"].(string)
+
+		if metric == "" {
+			metric = MetricTime
+		}
+
+		contours[i] = Contour{Metric: metric, Value: value, Color: color, Geometry: f.Geometry}
+		if metric == MetricDistance {
+			contours[i].Meters = int(value)
+		} else {
+			contours[i].Minutes = int(value)
+		}
+	}
+
+	sort.SliceStable(contours, func(i, j int) bool {
+		return contours[i].Value < contours[j].Value
+	})
+
+	return contours
+}
+
+// GetIsochrone fetches the areas reachable from a location within each contour using the specified routing profile
+func (i *Isochrone) GetIsochrone(center base.Location, profile RoutingProfile, opts *IsochroneOpts) (*IsochroneResponse, error) {
+	if opts == nil {
+		opts = &IsochroneOpts{}
+	}
+
+	err := opts.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	queryString := fmt.Sprintf("%f,%f", center.Longitude, center.Latitude)
+
+	resp := IsochroneResponse{}
+
+	err = i.base.Query(apiName, apiVersion, string(profile), queryString, &v, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
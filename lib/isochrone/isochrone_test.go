@@ -0,0 +1,152 @@
+/**
+ * go-mapbox Isochrone Module Tests
+ * Wraps the mapbox isochrone API for server side use
+ * See https://docs.mapbox.com/api/navigation/isochrone/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package isochrone
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/internal/basetest"
+)
+
+const isochroneResponse = `{
+	"type": "FeatureCollection",
+	"features": [{
+		"type": "Feature",
+		"properties": {"contour": 15, "color": "6706ce", "fill": "6706ce", "fillOpacity": 0.33},
+		"geometry": {"type": "Polygon", "coordinates": [[[-122.42, 37.78], [-122.40, 37.78], [-122.41, 37.79], [-122.42, 37.78]]]}
+	}, {
+		"type": "Feature",
+		"properties": {"contour": 5, "color": "04e813", "fill": "04e813", "fillOpacity": 0.33},
+		"geometry": {"type": "Polygon", "coordinates": [[[-122.415, 37.782], [-122.412, 37.782], [-122.413, 37.784], [-122.415, 37.782]]]}
+	}]
+}`
+
+func TestIsochrone(t *testing.T) {
+	center := base.Location{Latitude: 37.78, Longitude: -122.41}
+
+	t.Run("Fetches contours", func(t *testing.T) {
+		i := NewIsochrone(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.EqualValues(t, "/isochrone/v1/mapbox/walking/-122.410000,37.780000", r.URL.Path)
+			assert.EqualValues(t, "5,15", r.URL.Query().Get("contours_minutes"))
+			assert.EqualValues(t, "04e813,6706ce", r.URL.Query().Get("contours_colors"))
+			assert.EqualValues(t, "true", r.URL.Query().Get("polygons"))
+			assert.EqualValues(t, "0.5", r.URL.Query().Get("denoise"))
+			assert.EqualValues(t, "", r.URL.Query().Get("contours_meters"))
+			w.Write([]byte(isochroneResponse))
+		}))
+
+		denoise := 0.5
+		opts := IsochroneOpts{
+			ContoursMinutes: []int{5, 15},
+			ContoursColors:  []string{"04e813", "6706ce"},
+			Polygons:        true,
+			Denoise:         &denoise,
+		}
+
+		res, err := i.GetIsochrone(center, RoutingWalking, &opts)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		if !assert.Len(t, res.Features, 2) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "Polygon", res.Features[0].Geometry.Type)
+		assert.EqualValues(t, 15, res.Features[0].RawProperties["contour"])
+
+		contours := res.Contours()
+		if !assert.Len(t, contours, 2) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, MetricTime, contours[0].Metric)
+		assert.EqualValues(t, 5, contours[0].Value)
+		assert.EqualValues(t, 5, contours[0].Minutes)
+		assert.EqualValues(t, "04e813", contours[0].Color)
+		assert.EqualValues(t, "Polygon", contours[0].Geometry.Type)
+		assert.EqualValues(t, 15, contours[1].Minutes)
+		assert.EqualValues(t, "6706ce", contours[1].Color)
+	})
+
+	t.Run("Sorts distance contours", func(t *testing.T) {
+		i := NewIsochrone(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"type":"FeatureCollection","features":[
+				{"type":"Feature","properties":{"contour":2000,"metric":"distance","color":"ff0000"},"geometry":{"type":"LineString","coordinates":[[-122.42,37.78],[-122.40,37.78]]}},
+				{"type":"Feature","properties":{"contour":500,"metric":"distance","color":"00ff00"},"geometry":{"type":"LineString","coordinates":[[-122.415,37.782],[-122.412,37.782]]}},
+				{"type":"Feature","properties":{"contour":1000,"metric":"distance","color":"0000ff"},"geometry":{"type":"LineString","coordinates":[[-122.418,37.781],[-122.41,37.781]]}}
+			]}`))
+		}))
+
+		res, err := i.GetIsochrone(center, RoutingCycling, &IsochroneOpts{ContoursMeters: []int{2000, 500, 1000}})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		contours := res.Contours()
+		if !assert.Len(t, contours, 3) {
+			t.FailNow()
+		}
+		for i, meters := range []int{500, 1000, 2000} {
+			assert.EqualValues(t, MetricDistance, contours[i].Metric)
+			assert.EqualValues(t, meters, contours[i].Value)
+			assert.EqualValues(t, meters, contours[i].Meters)
+			assert.EqualValues(t, 0, contours[i].Minutes)
+			assert.EqualValues(t, "LineString", contours[i].Geometry.Type)
+		}
+	})
+
+	t.Run("Sends zero denoise", func(t *testing.T) {
+		i := NewIsochrone(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.EqualValues(t, "0", r.URL.Query().Get("denoise"))
+			w.Write([]byte(`{"type":"FeatureCollection","features":[]}`))
+		}))
+
+		denoise := 0.0
+		_, err := i.GetIsochrone(center, RoutingWalking, &IsochroneOpts{ContoursMinutes: []int{10}, Denoise: &denoise})
+		assert.Nil(t, err)
+	})
+
+	t.Run("Validates contours before querying", func(t *testing.T) {
+		i := NewIsochrone(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("Unexpected request: %s", r.URL)
+		}))
+
+		tests := []struct {
+			name  string
+			opts  *IsochroneOpts
+			field string
+		}{
+			{"no contours", nil, "contours"},
+			{"too many contours", &IsochroneOpts{ContoursMeters: []int{100, 200, 300, 400, 500}}, "contours_meters"},
+			{"contour out of range", &IsochroneOpts{ContoursMinutes: []int{90}}, "contours_minutes"},
+			{"mismatched colors", &IsochroneOpts{ContoursMinutes: []int{5, 10}, ContoursColors: []string{"ff0000"}}, "contours_colors"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := i.GetIsochrone(center, RoutingDriving, tt.opts)
+				validationErr := &base.ValidationError{}
+				if assert.True(t, errors.As(err, &validationErr)) {
+					assert.EqualValues(t, tt.field, validationErr.Field)
+				}
+			})
+		}
+
+		t.Run("minutes and meters", func(t *testing.T) {
+			_, err := i.GetIsochrone(center, RoutingDriving, &IsochroneOpts{ContoursMinutes: []int{5}, ContoursMeters: []int{500}})
+			conflictErr := &ContourConflictError{}
+			assert.True(t, errors.As(err, &conflictErr))
+		})
+	})
+}
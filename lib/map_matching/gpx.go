@@ -0,0 +1,89 @@
+/**
+ * go-mapbox Map Matching Module GPX Import
+ * Wraps the mapbox Map Matching API for server side use
+ * See https://www.mapbox.com/api-documentation/#retrieve-a-match for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package mapmatching
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+// GPXParseError is returned when a GPX document cannot be parsed
+type GPXParseError struct {
+	Err error
+}
+
+func (e *GPXParseError) Error() string {
+	return fmt.Sprintf("Malformed GPX document (%s)", e.Err)
+}
+
+// Unwrap returns the underlying parse error
+func (e *GPXParseError) Unwrap() error {
+	return e.Err
+}
+
+// gpxDocument is the subset of a GPX 1.1 document containing track points
+type gpxDocument struct {
+	XMLName xml.Name `xml:"gpx"`
+	Tracks  []struct {
+		Segments []struct {
+			Points []struct {
+				Latitude  float64 `xml:"lat,attr"`
+				Longitude float64 `xml:"lon,attr"`
+				Time      string  `xml:"time"`
+			} `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+// ParseGPX reads the track points of all tracks and segments in a GPX document
+// Track point times are used as timestamps where present
+func ParseGPX(r io.Reader) ([]TracePoint, error) {
+	doc := gpxDocument{}
+	err := xml.NewDecoder(r).Decode(&doc)
+	if err != nil {
+		return nil, &GPXParseError{Err: err}
+	}
+
+	trace := make([]TracePoint, 0)
+	for _, t := range doc.Tracks {
+		for _, s := range t.Segments {
+			for _, p := range s.Points {
+				point := TracePoint{Location: base.Location{Latitude: p.Latitude, Longitude: p.Longitude}}
+
+				if p.Time != "" {
+					timestamp, err := time.Parse(time.RFC3339, p.Time)
+					if err != nil {
+						return nil, &GPXParseError{Err: err}
+					}
+					point.Timestamp = timestamp.Unix()
+				}
+
+				trace = append(trace, point)
+			}
+		}
+	}
+
+	return trace, nil
+}
+
+// MatchGPX snaps the track points of a GPX document to the road network
+func (d *MapMatching) MatchGPX(ctx context.Context, gpxReader io.Reader, opts *MatchOpts) (*MatchResponse, error) {
+	trace, err := ParseGPX(gpxReader)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.Match(ctx, trace, opts)
+}
@@ -10,6 +10,7 @@
 package mapmatching
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -20,6 +21,11 @@ import (
 const (
 	apiName    = "matching"
 	apiVersion = "v5"
+
+	// MinTracePoints is the minimum number of points in a match request
+	MinTracePoints = 2
+	// MaxTracePoints is the maximum number of points in a match request
+	MaxTracePoints = 100
 )
 
 // MapMatching api wrapper instance
@@ -120,3 +126,71 @@ func (d *MapMatching) GetMatching(path []base.Location, profile RoutingProfile,
 
 	return &resp, err
 }
+
+// MatchOpts request options for the Match method
+type MatchOpts struct {
+	// Profile is the routing profile to use, defaulting to RoutingDriving
+	Profile     RoutingProfile   `url:"-"`
+	Geometries  GeometryType     `url:"geometries,omitempty"`
+	Annotations []AnnotationType `url:"annotations,omitempty,comma"`
+	Overview    OverviewType     `url:"overview,omitempty"`
+	Steps       bool             `url:"steps,omitempty"`
+	// Tidy removes clusters and re-samples traces, which may drop input points
+	Tidy bool `url:"tidy,omitempty"`
+	// Waypoints are the indices of trace points to be treated as waypoints, defaulting to all
+	Waypoints []int  `url:"waypoints,omitempty,semicolon"`
+	Language  string `url:"language,omitempty"`
+}
+
+// Match snaps a trace of (possibly timestamped) points to the road network
+func (d *MapMatching) Match(ctx context.Context, trace []TracePoint, opts *MatchOpts) (*MatchResponse, error) {
+	if opts == nil {
+		opts = &MatchOpts{}
+	}
+
+	if len(trace) < MinTracePoints || len(trace) > MaxTracePoints {
+		return nil, &base.ValidationError{Field: "trace", Message: fmt.Sprintf("%d points provided, between %d and %d are required", len(trace), MinTracePoints, MaxTracePoints)}
+	}
+
+	v, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	coordinateStrings := make([]string, len(trace))
+	timestampStrings := make([]string, len(trace))
+	radiusStrings := make([]string, len(trace))
+	hasTimestamps, hasRadiuses := false, false
+
+	for i, p := range trace {
+		coordinateStrings[i] = fmt.Sprintf("%f,%f", p.Longitude, p.Latitude)
+		timestampStrings[i] = fmt.Sprintf("%d", p.Timestamp)
+		radiusStrings[i] = fmt.Sprintf("%v", p.Radius)
+
+		hasTimestamps = hasTimestamps || p.Timestamp != 0
+		hasRadiuses = hasRadiuses || p.Radius != 0
+	}
+
+	if hasTimestamps {
+		v.Set("timestamps", strings.Join(timestampStrings, ";"))
+	}
+	if hasRadiuses {
+		v.Set("radiuses", strings.Join(radiusStrings, ";"))
+	}
+
+	profile := opts.Profile
+	if profile == "" {
+		profile = RoutingDriving
+	}
+
+	queryString := fmt.Sprintf("%s/%s/%s/%s", apiName, apiVersion, profile, strings.Join(coordinateStrings, ";"))
+
+	resp := MatchResponse{}
+
+	err = d.base.QueryBaseContext(ctx, queryString, &v, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
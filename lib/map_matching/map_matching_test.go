@@ -10,12 +10,17 @@
 package mapmatching
 
 import (
+	"context"
+	"errors"
+	"net/http"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/internal/basetest"
 )
 
 func TestMapMatching(t *testing.T) {
@@ -80,3 +85,132 @@ func TestMapMatching(t *testing.T) {
 		assert.NotNil(t, err)
 	})
 }
+
+const matchResponse = `{
+	"code": "Ok",
+	"matchings": [{
+		"confidence": 0.93,
+		"distance": 315.2,
+		"duration": 61.4,
+		"geometry": "ipkcFfichVnP@j@BLLEHa@",
+		"legs": [
+			{"distance": 150.1, "duration": 30.2, "summary": "Douglass Street", "steps": []},
+			{"distance": 165.1, "duration": 31.2, "summary": "Douglass Street", "steps": []}
+		]
+	}],
+	"tracepoints": [
+		{"waypoint_index": 0, "matchings_index": 0, "name": "Douglass Street", "location": [-122.442541, 37.753195]},
+		null,
+		{"waypoint_index": 1, "matchings_index": 0, "alternatives_count": 2, "name": "Douglass Street", "location": [-122.441994, 37.754111]}
+	]
+}`
+
+func TestMatch(t *testing.T) {
+
+	trace := []TracePoint{
+		{Location: base.Location{Latitude: 37.753195, Longitude: -122.442541}, Timestamp: 1492878132, Radius: 9},
+		{Location: base.Location{Latitude: 37.753738, Longitude: -122.442380}, Timestamp: 1492878142, Radius: 6},
+		{Location: base.Location{Latitude: 37.754111, Longitude: -122.441994}, Timestamp: 1492878152, Radius: 8},
+	}
+
+	t.Run("Matches a trace", func(t *testing.T) {
+		b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.EqualValues(t, "/matching/v5/mapbox/cycling/-122.442541,37.753195;-122.442380,37.753738;-122.441994,37.754111", r.URL.Path)
+			assert.EqualValues(t, "1492878132;1492878142;1492878152", r.URL.Query().Get("timestamps"))
+			assert.EqualValues(t, "9;6;8", r.URL.Query().Get("radiuses"))
+			assert.EqualValues(t, "0;2", r.URL.Query().Get("waypoints"))
+			assert.EqualValues(t, "distance,speed", r.URL.Query().Get("annotations"))
+			assert.EqualValues(t, "true", r.URL.Query().Get("tidy"))
+			w.Write([]byte(matchResponse))
+		})
+
+		opts := MatchOpts{
+			Profile:     RoutingCycling,
+			Annotations: []AnnotationType{AnnotationDistance, AnnotationSpeed},
+			Tidy:        true,
+			Waypoints:   []int{0, 2},
+		}
+
+		res, err := NewMapMaptching(b).Match(context.Background(), trace, &opts)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.EqualValues(t, CodeOK, res.Code)
+		assert.Len(t, res.Matchings, 1)
+		assert.EqualValues(t, 0.93, res.Matchings[0].Confidence)
+		assert.Len(t, res.Matchings[0].Legs, 2)
+		assert.Len(t, res.MatchedPoints, 3)
+		assert.Nil(t, res.MatchedPoints[1])
+		assert.EqualValues(t, 1, res.MatchedPoints[2].WaypointIndex)
+		assert.EqualValues(t, 2, res.MatchedPoints[2].AlternativesCount)
+
+		snapped := res.SnappedLocations()
+		if assert.Len(t, snapped, 3) {
+			assert.EqualValues(t, &base.Location{Latitude: 37.753195, Longitude: -122.442541}, snapped[0])
+			assert.Nil(t, snapped[1])
+			assert.EqualValues(t, &base.Location{Latitude: 37.754111, Longitude: -122.441994}, snapped[2])
+		}
+	})
+
+	t.Run("Rejects traces outside the point limits", func(t *testing.T) {
+		b, err := base.NewBase("test-token")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		_, err = NewMapMaptching(b).Match(context.Background(), trace[:1], nil)
+		validationErr := &base.ValidationError{}
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.EqualValues(t, "trace", validationErr.Field)
+		}
+
+		_, err = NewMapMaptching(b).Match(context.Background(), make([]TracePoint, MaxTracePoints+1), nil)
+		assert.NotNil(t, err)
+	})
+}
+
+const gpxTrack = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1">
+	<trk>
+		<name>Morning Ride</name>
+		<trkseg>
+			<trkpt lat="37.753195" lon="-122.442541"><ele>52.1</ele><time>2017-04-22T16:22:12Z</time></trkpt>
+			<trkpt lat="37.753738" lon="-122.442380"><time>2017-04-22T16:22:22Z</time></trkpt>
+		</trkseg>
+		<trkseg>
+			<trkpt lat="37.754111" lon="-122.441994"><time>2017-04-22T16:22:32Z</time></trkpt>
+		</trkseg>
+	</trk>
+</gpx>`
+
+func TestMatchGPX(t *testing.T) {
+	b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.EqualValues(t, "/matching/v5/mapbox/driving/-122.442541,37.753195;-122.442380,37.753738;-122.441994,37.754111", r.URL.Path)
+		assert.EqualValues(t, "1492878132;1492878142;1492878152", r.URL.Query().Get("timestamps"))
+		w.Write([]byte(matchResponse))
+	})
+	m := NewMapMaptching(b)
+
+	t.Run("Matches a GPX track", func(t *testing.T) {
+		res, err := m.MatchGPX(context.Background(), strings.NewReader(gpxTrack), nil)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.Len(t, res.Matchings, 1)
+	})
+
+	t.Run("Rejects malformed documents", func(t *testing.T) {
+		_, err := m.MatchGPX(context.Background(), strings.NewReader(`<gpx><trk><trkseg><trkpt lat="1"`), nil)
+		parseErr := &GPXParseError{}
+		assert.True(t, errors.As(err, &parseErr))
+
+		_, err = m.MatchGPX(context.Background(), strings.NewReader(`<gpx><trk><trkseg><trkpt lat="1" lon="2"><time>yesterday</time></trkpt></trkseg></trk></gpx>`), nil)
+		assert.True(t, errors.As(err, &parseErr))
+	})
+
+	t.Run("Rejects tracks outside the point limits", func(t *testing.T) {
+		_, err := m.MatchGPX(context.Background(), strings.NewReader(`<gpx><trk><trkseg><trkpt lat="1" lon="2"></trkpt></trkseg></trk></gpx>`), nil)
+		assert.NotNil(t, err)
+	})
+}
@@ -11,14 +11,31 @@ package mapmatching
 
 import (
 	"fmt"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/directions"
 )
 
-// MatchingResponse is the response from GetMatching
+// MatchResponse is the response from Match or GetMatching
 // https://www.mapbox.com/api-documentation/#match-response-object
-type MatchingResponse struct {
-	Code       string
-	Matchings  []Matchings
-	Tracepoint []TracePoint
+type MatchResponse struct {
+	Code      string
+	Matchings []Matching
+	// MatchedPoints contains one entry per input point, nil where the point could not be matched
+	MatchedPoints []*MatchedPoint `json:"tracepoints"`
+}
+
+// MatchingResponse is the response from GetMatching
+// Deprecated: use MatchResponse
+type MatchingResponse = MatchResponse
+
+// TracePoint is an input location to be matched to the road network
+type TracePoint struct {
+	base.Location
+	// Timestamp (unix seconds) of the location, omitted if zero
+	Timestamp int64
+	// Radius (meters) the location may be snapped within, omitted if zero
+	Radius float64
 }
 
 type Coordinate []float64
@@ -29,17 +46,28 @@ type GeojsonGeometry struct {
 
 type PolylineGeometry string
 
-// Matchings it a route object with additional confidence field
+// Matching it a route object with additional confidence field
 // https://www.mapbox.com/api-documentation/#match-object
-type Matchings struct {
+type Matching struct {
 	Confidence float64
 	Distance   float64
 	Duration   float64
 	Geometry   interface{} // Issue: must support polyline (string) or geojson (object)
-	Legs       []MatchingLeg
+	Legs       []RouteLeg
 }
 
-func (m *Matchings) GetGeometryGeojson() (*GeojsonGeometry, error) {
+// Matchings is a route object with additional confidence field
+// Deprecated: use Matching
+type Matchings = Matching
+
+// RouteLeg is a route between two matched waypoints
+type RouteLeg = directions.RouteLeg
+
+// MatchingLeg legs inside the matching object
+// Deprecated: use RouteLeg
+type MatchingLeg = RouteLeg
+
+func (m *Matching) GetGeometryGeojson() (*GeojsonGeometry, error) {
 	geojson, ok := m.Geometry.(map[string]interface{})
 	if !ok {
 		return nil, fmt.Errorf("Malformed geojson geometry (expected map[string]interface, received %t)", m.Geometry)
@@ -84,7 +112,7 @@ func (m *Matchings) GetGeometryGeojson() (*GeojsonGeometry, error) {
 	return &geometry, nil
 }
 
-func (m *Matchings) GetGeometryPolyline() (string, error) {
+func (m *Matching) GetGeometryPolyline() (string, error) {
 	g, ok := m.Geometry.(string)
 	if !ok {
 		return "", fmt.Errorf("Non polyline geometry (type: %t)", m.Geometry)
@@ -92,20 +120,40 @@ func (m *Matchings) GetGeometryPolyline() (string, error) {
 	return g, nil
 }
 
-//MatchingLeg legs inside the matching object
-type MatchingLeg struct {
-	Step     []float64
-	Summary  string
-	Duration float64
-	Distance float64
+// MatchedPoint represents the location an input point was matched with
+type MatchedPoint struct {
+	WaypointIndex  int16     `json:"waypoint_index"`
+	Location       []float64 `json:"location"`
+	Name           string    `json:"name"`
+	MatchingsIndex int16     `json:"matchings_index"`
+	// AlternativesCount is the number of probable alternative matchings for the point
+	AlternativesCount int `json:"alternatives_count"`
 }
 
-// TracePoint represents the location an input point was matched with
-type TracePoint struct {
-	WaypointIndex  int16
-	Location       []float64
-	Name           string
-	MatchingsIndex int16
+// Tracepoint is the matched location of an input point
+type Tracepoint = MatchedPoint
+
+// SnappedLocation returns the location the point was snapped to
+func (p *MatchedPoint) SnappedLocation() base.Location {
+	if len(p.Location) < 2 {
+		return base.Location{}
+	}
+	return base.Location{Latitude: p.Location[1], Longitude: p.Location[0]}
+}
+
+// SnappedLocations returns the snapped location of each input point in input order,
+// with nil entries where a point could not be matched
+// Points removed from the trace when MatchOpts.Tidy is set are also nil, so the entries
+// (unlike WaypointIndex, which only counts points that were matched) always align with the input
+func (r *MatchResponse) SnappedLocations() []*base.Location {
+	locations := make([]*base.Location, len(r.MatchedPoints))
+	for i, p := range r.MatchedPoints {
+		if p != nil {
+			l := p.SnappedLocation()
+			locations[i] = &l
+		}
+	}
+	return locations
 }
 
 // OverviewType Type of returned overview geometry
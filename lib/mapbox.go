@@ -11,11 +11,21 @@ package mapbox
 
 import (
 	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/datasets"
 	"github.com/ryankurte/go-mapbox/lib/directions"
 	"github.com/ryankurte/go-mapbox/lib/directions_matrix"
+	"github.com/ryankurte/go-mapbox/lib/elevation"
 	"github.com/ryankurte/go-mapbox/lib/geocode"
+	"github.com/ryankurte/go-mapbox/lib/isochrone"
 	"github.com/ryankurte/go-mapbox/lib/map_matching"
 	"github.com/ryankurte/go-mapbox/lib/maps"
+	"github.com/ryankurte/go-mapbox/lib/optimization"
+	"github.com/ryankurte/go-mapbox/lib/staticimage"
+	"github.com/ryankurte/go-mapbox/lib/styles"
+	"github.com/ryankurte/go-mapbox/lib/tilequery"
+	"github.com/ryankurte/go-mapbox/lib/tilesets"
+	"github.com/ryankurte/go-mapbox/lib/tokens"
+	"github.com/ryankurte/go-mapbox/lib/uploads"
 )
 
 // Mapbox API Wrapper structure
@@ -31,14 +41,35 @@ type Mapbox struct {
 	DirectionsMatrix *directionsmatrix.DirectionsMatrix
 	// MapMatching snaps inaccurate path tracked to a map to produce a clean path
 	MapMatching *mapmatching.MapMatching
+	// Optimization returns the optimal order in which to visit a set of waypoints
+	Optimization *optimization.Optimization
+	// StaticImage renders static map images with optional overlays
+	StaticImage *staticimage.StaticImage
+	// Styles manages map styles and fonts
+	Styles *styles.Styles
+	// Datasets manages datasets of GeoJSON features
+	Datasets *datasets.Datasets
+	// Tokens manages and validates API tokens
+	Tokens *tokens.Tokens
+	// Uploads stages files and converts them into tilesets
+	Uploads *uploads.Uploads
+	// Isochrone returns the areas reachable within travel times or distances
+	Isochrone *isochrone.Isochrone
+	// Tilequery returns the tileset features at or near a location
+	Tilequery *tilequery.Tilequery
+	// Elevation returns point elevations from terrain data
+	Elevation *elevation.Elevation
+	// Tilesets lists tilesets and reports their publishing status
+	Tilesets *tilesets.Tilesets
 }
 
 // NewMapbox Create a new mapbox API instance
-func NewMapbox(token string) (*Mapbox, error) {
+// Options are passed through to the underlying base instance
+func NewMapbox(token string, opts ...base.Option) (*Mapbox, error) {
 	m := &Mapbox{}
 
 	// Create base instance
-	base, err := base.NewBase(token)
+	base, err := base.NewBase(token, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -50,6 +81,16 @@ func NewMapbox(token string) (*Mapbox, error) {
 	m.Directions = directions.NewDirections(m.base)
 	m.DirectionsMatrix = directionsmatrix.NewDirectionsMatrix(m.base)
 	m.MapMatching = mapmatching.NewMapMaptching(m.base)
+	m.Optimization = optimization.NewOptimization(m.base)
+	m.StaticImage = staticimage.NewStaticImage(m.base)
+	m.Styles = styles.NewStyles(m.base)
+	m.Datasets = datasets.NewDatasets(m.base)
+	m.Tokens = tokens.NewTokens(m.base)
+	m.Uploads = uploads.NewUploads(m.base)
+	m.Isochrone = isochrone.NewIsochrone(m.base)
+	m.Tilequery = tilequery.NewTilequery(m.base)
+	m.Elevation = elevation.NewElevation(m.base)
+	m.Tilesets = tilesets.NewTilesets(m.base)
 
 	return m, nil
 }
@@ -43,6 +43,14 @@ func TestMaps(t *testing.T) {
 		t.Error(err)
 	}
 
+	// Rate limit information is recorded from responses
+	if mapBox.base.LastRateLimit().IsZero() {
+		t.Error("Rate limit information not recorded")
+	}
+	if mapBox.base.LastRequestID() == "" {
+		t.Error("Request ID not recorded")
+	}
+
 	// Reverse Geocoding
 	var reverseOpts geocode.ReverseRequestOpts
 	reverseOpts.Limit = 1
@@ -0,0 +1,159 @@
+/**
+ * go-mapbox Optimization Module
+ * Wraps the mapbox optimization (traveling salesman) API for server side use
+ * See https://docs.mapbox.com/api/navigation/optimization-v1/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package optimization
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-querystring/query"
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const (
+	apiName    = "optimized-trips"
+	apiVersion = "v1"
+
+	// MinWaypoints is the minimum number of waypoints in an optimization request
+	MinWaypoints = 2
+	// MaxWaypoints is the maximum number of waypoints in an optimization request
+	MaxWaypoints = 12
+)
+
+// Optimization api wrapper instance
+type Optimization struct {
+	base *base.Base
+}
+
+// NewOptimization Create a new Optimization API wrapper
+func NewOptimization(base *base.Base) *Optimization {
+	return &Optimization{base}
+}
+
+// OptimizationOpts request options for the optimization api
+type OptimizationOpts struct {
+	// Profile is the routing profile to use, defaulting to RoutingDriving
+	Profile RoutingProfile `url:"-"`
+	// Roundtrip selects whether the trip returns to the first location, defaulting to true when nil
+	Roundtrip *bool `url:"roundtrip,omitempty"`
+	// Source selects the starting waypoint (any or first)
+	Source Endpoint `url:"source,omitempty"`
+	// Destination selects the ending waypoint (any or last)
+	Destination Endpoint `url:"destination,omitempty"`
+	// Distributions are pickup and dropoff pairs that must be visited in order
+	Distributions []Distribution   `url:"-"`
+	Overview      OverviewType     `url:"overview,omitempty"`
+	Steps         bool             `url:"steps,omitempty"`
+	Geometries    GeometryType     `url:"geometries,omitempty"`
+	Annotations   []AnnotationType `url:"annotations,omitempty,comma"`
+}
+
+// TripOpts request options for GetTrip
+type TripOpts = OptimizationOpts
+
+// Distribution is a pickup and dropoff pair of waypoint indices
+type Distribution struct {
+	Pickup  int
+	Dropoff int
+}
+
+// validateDistributions checks distribution indices are in range and each waypoint is used by at most one distribution
+func validateDistributions(distributions []Distribution, count int) error {
+	used := make(map[int]bool)
+
+	for _, d := range distributions {
+		pair := fmt.Sprintf("%d,%d", d.Pickup, d.Dropoff)
+
+		if d.Pickup < 0 || d.Pickup >= count || d.Dropoff < 0 || d.Dropoff >= count {
+			return &base.ValidationError{Field: "distributions", Message: fmt.Sprintf("%s out of range (%d waypoints)", pair, count)}
+		}
+		if d.Pickup == d.Dropoff {
+			return &base.ValidationError{Field: "distributions", Message: fmt.Sprintf("%s uses the same waypoint for pickup and dropoff", pair)}
+		}
+		for _, i := range []int{d.Pickup, d.Dropoff} {
+			if used[i] {
+				return &base.ValidationError{Field: "distributions", Message: fmt.Sprintf("%s reuses waypoint %d", pair, i)}
+			}
+			used[i] = true
+		}
+	}
+
+	return nil
+}
+
+// Get computes the optimal order in which to visit a set of waypoints
+func (o *Optimization) Get(ctx context.Context, waypoints []base.Location, opts *OptimizationOpts) (*OptimizationResponse, error) {
+	if opts == nil {
+		opts = &OptimizationOpts{}
+	}
+
+	if len(waypoints) < MinWaypoints || len(waypoints) > MaxWaypoints {
+		return nil, &base.ValidationError{Field: "waypoints", Message: fmt.Sprintf("%d waypoints provided, between %d and %d are required", len(waypoints), MinWaypoints, MaxWaypoints)}
+	}
+
+	v, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts.Distributions) > 0 {
+		err = validateDistributions(opts.Distributions, len(waypoints))
+		if err != nil {
+			return nil, err
+		}
+
+		distributionStrings := make([]string, len(opts.Distributions))
+		for i, d := range opts.Distributions {
+			distributionStrings[i] = fmt.Sprintf("%d,%d", d.Pickup, d.Dropoff)
+		}
+		v.Set("distributions", strings.Join(distributionStrings, ";"))
+	}
+
+	coordinateStrings := make([]string, len(waypoints))
+	for i, l := range waypoints {
+		coordinateStrings[i] = fmt.Sprintf("%f,%f", l.Longitude, l.Latitude)
+	}
+
+	profile := opts.Profile
+	if profile == "" {
+		profile = RoutingDriving
+	}
+
+	queryString := fmt.Sprintf("%s/%s/%s/%s", apiName, apiVersion, profile, strings.Join(coordinateStrings, ";"))
+
+	resp := OptimizationResponse{}
+
+	err = o.base.QueryBaseContext(ctx, queryString, &v, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.WaypointOrder = make([]int, len(resp.Waypoints))
+	for i, w := range resp.Waypoints {
+		if w.WaypointIndex < 0 || w.WaypointIndex >= len(resp.Waypoints) {
+			return nil, fmt.Errorf("Invalid waypoint index %d in response", w.WaypointIndex)
+		}
+		resp.WaypointOrder[w.WaypointIndex] = i
+	}
+
+	return &resp, nil
+}
+
+// GetTrip computes the optimal order in which to visit a set of locations using the specified routing profile
+func (o *Optimization) GetTrip(locs []base.Location, profile RoutingProfile, opts *TripOpts) (*OptimizationResponse, error) {
+	t := TripOpts{}
+	if opts != nil {
+		t = *opts
+	}
+	t.Profile = profile
+
+	return o.Get(context.Background(), locs, &t)
+}
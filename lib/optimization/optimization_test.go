@@ -0,0 +1,185 @@
+/**
+ * go-mapbox Optimization Module Tests
+ * Wraps the mapbox optimization (traveling salesman) API for server side use
+ * See https://docs.mapbox.com/api/navigation/optimization-v1/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package optimization
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/internal/basetest"
+)
+
+const optimizationResponse = `{
+	"code": "Ok",
+	"waypoints": [
+		{"name": "North Lake Boulevard", "location": [-122.7, 45.5], "waypoint_index": 0, "trips_index": 0},
+		{"name": "Kerby Avenue", "location": [-122.6, 45.6], "waypoint_index": 2, "trips_index": 0},
+		{"name": "Northwest Lovejoy Street", "location": [-122.5, 45.4], "waypoint_index": 1, "trips_index": 0}
+	],
+	"trips": [{
+		"distance": 8921.3,
+		"duration": 1213.6,
+		"weight": 1213.6,
+		"weight_name": "routability",
+		"geometry": "sefaG~bbkV",
+		"legs": [
+			{"distance": 3021.1, "duration": 401.2, "summary": "", "steps": []},
+			{"distance": 2890.4, "duration": 398.1, "summary": "", "steps": []},
+			{"distance": 3009.8, "duration": 414.3, "summary": "", "steps": []}
+		]
+	}]
+}`
+
+func TestOptimization(t *testing.T) {
+
+	locs := []base.Location{
+		{Latitude: 45.5, Longitude: -122.7},
+		{Latitude: 45.6, Longitude: -122.6},
+		{Latitude: 45.4, Longitude: -122.5},
+	}
+
+	t.Run("Optimizes a trip", func(t *testing.T) {
+		b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.EqualValues(t, "/optimized-trips/v1/mapbox/cycling/-122.700000,45.500000;-122.600000,45.600000;-122.500000,45.400000", r.URL.Path)
+			assert.EqualValues(t, "false", r.URL.Query().Get("roundtrip"))
+			assert.EqualValues(t, "first", r.URL.Query().Get("source"))
+			assert.EqualValues(t, "last", r.URL.Query().Get("destination"))
+			assert.EqualValues(t, "0,2", r.URL.Query().Get("distributions"))
+			w.Write([]byte(optimizationResponse))
+		})
+
+		roundtrip := false
+		opts := OptimizationOpts{
+			Profile:       RoutingCycling,
+			Roundtrip:     &roundtrip,
+			Source:        EndpointFirst,
+			Destination:   EndpointLast,
+			Distributions: []Distribution{{Pickup: 0, Dropoff: 2}},
+		}
+
+		res, err := NewOptimization(b).Get(context.Background(), locs, &opts)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.EqualValues(t, CodeOK, res.Code)
+		assert.Len(t, res.Trips, 1)
+		assert.Len(t, res.Trips[0].Legs, 3)
+		assert.EqualValues(t, []int{0, 2, 1}, res.WaypointOrder)
+	})
+
+	t.Run("Validates waypoints before querying", func(t *testing.T) {
+		b, err := base.NewBase("test-token")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		o := NewOptimization(b)
+
+		_, err = o.Get(context.Background(), locs[:1], nil)
+		validationErr := &base.ValidationError{}
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.EqualValues(t, "waypoints", validationErr.Field)
+		}
+
+		_, err = o.Get(context.Background(), make([]base.Location, MaxWaypoints+1), nil)
+		assert.NotNil(t, err)
+
+		_, err = o.Get(context.Background(), locs, &OptimizationOpts{Distributions: []Distribution{{Pickup: 0, Dropoff: 3}}})
+		assert.NotNil(t, err)
+	})
+}
+
+func TestGetTrip(t *testing.T) {
+	locs := []base.Location{
+		{Latitude: 45.50, Longitude: -122.70},
+		{Latitude: 45.60, Longitude: -122.60},
+		{Latitude: 45.40, Longitude: -122.50},
+		{Latitude: 45.55, Longitude: -122.65},
+		{Latitude: 45.45, Longitude: -122.55},
+	}
+
+	b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.EqualValues(t, "/optimized-trips/v1/mapbox/walking/-122.700000,45.500000;-122.600000,45.600000;-122.500000,45.400000;-122.650000,45.550000;-122.550000,45.450000", r.URL.Path)
+		assert.EqualValues(t, "geojson", r.URL.Query().Get("geometries"))
+		w.Write([]byte(`{
+			"code": "Ok",
+			"waypoints": [
+				{"location": [-122.70, 45.50], "waypoint_index": 0, "trips_index": 0},
+				{"location": [-122.60, 45.60], "waypoint_index": 2, "trips_index": 0},
+				{"location": [-122.50, 45.40], "waypoint_index": 3, "trips_index": 0},
+				{"location": [-122.65, 45.55], "waypoint_index": 1, "trips_index": 0},
+				{"location": [-122.55, 45.45], "waypoint_index": 4, "trips_index": 0}
+			],
+			"trips": [{"distance": 18210.4, "duration": 13020.7, "legs": []}]
+		}`))
+	})
+
+	res, err := NewOptimization(b).GetTrip(locs, RoutingWalking, &TripOpts{Geometries: GeometryGeojson})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	ordered, err := res.OrderedLocations(locs)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.EqualValues(t, []base.Location{locs[0], locs[3], locs[1], locs[2], locs[4]}, ordered)
+
+	_, err = res.OrderedLocations(locs[:4])
+	assert.NotNil(t, err)
+}
+
+func TestDistributions(t *testing.T) {
+	locs := []base.Location{
+		{Latitude: 45.50, Longitude: -122.70},
+		{Latitude: 45.60, Longitude: -122.60},
+		{Latitude: 45.40, Longitude: -122.50},
+		{Latitude: 45.55, Longitude: -122.65},
+		{Latitude: 45.45, Longitude: -122.55},
+	}
+
+	b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.EqualValues(t, "1,3;2,4", r.URL.Query().Get("distributions"))
+		w.Write([]byte(`{"code":"Ok","waypoints":[],"trips":[]}`))
+	})
+	o := NewOptimization(b)
+
+	t.Run("Sends distributions", func(t *testing.T) {
+		opts := TripOpts{Distributions: []Distribution{{Pickup: 1, Dropoff: 3}, {Pickup: 2, Dropoff: 4}}}
+		_, err := o.GetTrip(locs, RoutingDriving, &opts)
+		assert.Nil(t, err)
+	})
+
+	tests := []struct {
+		name          string
+		distributions []Distribution
+		message       string
+	}{
+		{"out of range", []Distribution{{Pickup: 1, Dropoff: 5}}, "1,5 out of range (5 waypoints)"},
+		{"same waypoint", []Distribution{{Pickup: 2, Dropoff: 2}}, "2,2 uses the same waypoint for pickup and dropoff"},
+		{"reused waypoint", []Distribution{{Pickup: 1, Dropoff: 3}, {Pickup: 3, Dropoff: 4}}, "3,4 reuses waypoint 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run("Rejects distributions "+tt.name, func(t *testing.T) {
+			_, err := o.GetTrip(locs, RoutingDriving, &TripOpts{Distributions: tt.distributions})
+			validationErr := &base.ValidationError{}
+			if assert.True(t, errors.As(err, &validationErr)) {
+				assert.EqualValues(t, "distributions", validationErr.Field)
+				assert.EqualValues(t, tt.message, validationErr.Message)
+			}
+		})
+	}
+}
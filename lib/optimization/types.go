@@ -0,0 +1,140 @@
+/**
+ * go-mapbox Optimization Module Types
+ * Wraps the mapbox optimization (traveling salesman) API for server side use
+ * See https://docs.mapbox.com/api/navigation/optimization-v1/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package optimization
+
+import (
+	"fmt"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/directions"
+)
+
+// RoutingProfile defines routing mode for optimization
+type RoutingProfile string
+
+const (
+	// RoutingDriving mode for for automovide routing
+	RoutingDriving RoutingProfile = "mapbox/driving"
+	// RoutingWalking mode for Pedestrian routing
+	RoutingWalking RoutingProfile = "mapbox/walking"
+	// RoutingCycling mode for bicycle routing
+	RoutingCycling RoutingProfile = "mapbox/cycling"
+)
+
+// Endpoint selects the start or end waypoint of a trip
+type Endpoint string
+
+const (
+	// EndpointAny allows any waypoint to be used
+	EndpointAny Endpoint = "any"
+	// EndpointFirst forces the trip to start at the first waypoint
+	EndpointFirst Endpoint = "first"
+	// EndpointLast forces the trip to end at the last waypoint
+	EndpointLast Endpoint = "last"
+)
+
+// OverviewType Type of returned overview geometry
+type OverviewType string
+
+const (
+	//OverviewFull returns a detailed overview geometry
+	OverviewFull OverviewType = "full"
+	//OverviewSimplified returns a simplified overview geometry
+	OverviewSimplified OverviewType = "simplified"
+	//OverviewFalse returns no overview geometry
+	OverviewFalse OverviewType = "false"
+)
+
+// GeometryType Format of the returned geometry
+type GeometryType string
+
+const (
+	// GeometryGeojson returns a geojson like geometry
+	GeometryGeojson GeometryType = "geojson"
+	// GeometryPolyline returns a polyline 5 encoded string like geometry
+	GeometryPolyline GeometryType = "polyline"
+	// GeometryPolyline6 returns a polyline 6 encode string like geometry
+	GeometryPolyline6 GeometryType = "polyline6"
+)
+
+// AnnotationType type of metadata to be returned additionally along the route
+type AnnotationType string
+
+const (
+	// AnnotationDuration returns a additional duration metadata
+	AnnotationDuration AnnotationType = "duration"
+	// AnnotationDistance returns a additional distance metadata
+	AnnotationDistance AnnotationType = "distance"
+	// AnnotationSpeed returns a additional speed metadata
+	AnnotationSpeed AnnotationType = "speed"
+)
+
+// OptimizationResponse is the response from Get
+// https://docs.mapbox.com/api/navigation/optimization-v1/#optimization-response-object
+type OptimizationResponse struct {
+	Code      string
+	Waypoints []WaypointWithIndex
+	Trips     []Trip
+	// WaypointOrder lists the input waypoint indices in the order they are visited
+	WaypointOrder []int `json:"-"`
+}
+
+// OrderedLocations returns the input locations reordered into the optimized visiting order
+func (r *OptimizationResponse) OrderedLocations(locs []base.Location) ([]base.Location, error) {
+	if len(locs) != len(r.WaypointOrder) {
+		return nil, fmt.Errorf("Received %d locations for %d waypoints", len(locs), len(r.WaypointOrder))
+	}
+
+	ordered := make([]base.Location, len(locs))
+	for i, w := range r.WaypointOrder {
+		ordered[i] = locs[w]
+	}
+
+	return ordered, nil
+}
+
+// WaypointWithIndex is an input waypoint snapped to the road network along with its position in the trip
+type WaypointWithIndex struct {
+	Name     string    `json:"name"`
+	Location []float64 `json:"location"`
+	// WaypointIndex is the position of this waypoint in the trip
+	WaypointIndex int `json:"waypoint_index"`
+	// TripsIndex is the index of the trip containing this waypoint
+	TripsIndex int `json:"trips_index"`
+}
+
+// Trip is an optimized route through all waypoints
+type Trip struct {
+	Distance   float64
+	Duration   float64
+	Weight     float64
+	WeightName string      `json:"weight_name"`
+	Geometry   interface{} // polyline (string) or geojson (object) depending on the requested geometries
+	Legs       []directions.RouteLeg
+}
+
+// Codes are optimization response Codes
+// https://docs.mapbox.com/api/navigation/optimization-v1/#optimization-api-errors
+type Codes string
+
+const (
+	// CodeOK success response
+	CodeOK Codes = "Ok"
+	// CodeNoRoute no route found between the waypoints
+	CodeNoRoute Codes = "NoRoute"
+	// CodeNoTrips no trip visiting all waypoints found
+	CodeNoTrips Codes = "NoTrips"
+	// CodeNotImplemented unsupported source, destination and roundtrip combination
+	CodeNotImplemented Codes = "NotImplemented"
+	// CodeProfileNotFound invalid routing profile
+	CodeProfileNotFound Codes = "ProfileNotFound"
+	// CodeInvalidInput invalid input data to the server
+	CodeInvalidInput Codes = "InvalidInput"
+)
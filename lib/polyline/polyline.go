@@ -0,0 +1,56 @@
+/**
+ * go-mapbox Polyline Module
+ * Provides decoding and encoding of polylines returned by the directions and map matching APIs
+ * See https://developers.google.com/maps/documentation/utilities/polylinealgorithm for format information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package polyline
+
+import (
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+// Decode decodes a polyline with the provided precision, 5 for the classic format or 6 for polyline6
+// A ValidationError is returned for any other precision
+func Decode(encoded string, precision int) ([]base.Location, error) {
+	return base.DecodePolyline(encoded, precision)
+}
+
+// Encode encodes a list of locations as a polyline with the provided precision, 5 for the classic format or 6 for polyline6
+// Encode panics with a ValidationError for any other precision
+func Encode(coords []base.Location, precision int) string {
+	return base.EncodePolyline(coords, precision)
+}
+
+// DecodeP5 decodes a precision 5 polyline
+func DecodeP5(encoded string) ([]base.Location, error) {
+	return Decode(encoded, 5)
+}
+
+// EncodeP5 encodes a list of locations as a precision 5 polyline
+func EncodeP5(coords []base.Location) string {
+	return Encode(coords, 5)
+}
+
+// DecodeP6 decodes a precision 6 polyline
+func DecodeP6(encoded string) ([]base.Location, error) {
+	return Decode(encoded, 6)
+}
+
+// EncodeP6 encodes a list of locations as a precision 6 polyline
+func EncodeP6(coords []base.Location) string {
+	return Encode(coords, 6)
+}
+
+// DecodePolyline5 decodes a precision 5 polyline, as returned for the "polyline" geometry type
+func DecodePolyline5(encoded string) ([]base.Location, error) {
+	return Decode(encoded, 5)
+}
+
+// DecodePolyline6 decodes a precision 6 polyline, as returned for the "polyline6" geometry type
+func DecodePolyline6(encoded string) ([]base.Location, error) {
+	return Decode(encoded, 6)
+}
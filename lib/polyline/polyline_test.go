@@ -0,0 +1,146 @@
+/**
+ * go-mapbox Polyline Module Tests
+ * Provides decoding and encoding of polylines returned by the directions and map matching APIs
+ * See https://developers.google.com/maps/documentation/utilities/polylinealgorithm for format information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package polyline
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+func TestPolyline(t *testing.T) {
+
+	locations := []base.Location{
+		{Latitude: 38.5, Longitude: -120.2},
+		{Latitude: 40.7, Longitude: -120.95},
+		{Latitude: 43.252, Longitude: -126.453},
+	}
+
+	tests := []struct {
+		name      string
+		encoded   string
+		precision int
+	}{
+		{"precision 5", "_p~iF~ps|U_ulLnnqC_mqNvxq`@", 5},
+		{"precision 6", "_izlhA~rlgdF_{geC~ywl@_kwzCn`{nI", 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded := mustDecode(t, tt.encoded, tt.precision)
+			if !assert.Len(t, decoded, len(locations)) {
+				t.FailNow()
+			}
+			for i := range decoded {
+				assert.InDelta(t, locations[i].Latitude, decoded[i].Latitude, 1e-9)
+				assert.InDelta(t, locations[i].Longitude, decoded[i].Longitude, 1e-9)
+			}
+
+			assert.EqualValues(t, tt.encoded, Encode(locations, tt.precision))
+		})
+	}
+
+	t.Run("Rejects truncated polylines", func(t *testing.T) {
+		_, err := Decode("_p~iF~ps|U_", 5)
+		assert.NotNil(t, err)
+		_, err = Decode("_p~iF~ps|U_ulL", 5)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Rejects unsupported precisions", func(t *testing.T) {
+		for _, precision := range []int{-1, 0, 4, 7, 20} {
+			_, err := Decode("_p~iF~ps|U_ulLnnqC_mqNvxq`@", precision)
+			validationErr := &base.ValidationError{}
+			if assert.True(t, errors.As(err, &validationErr)) {
+				assert.EqualValues(t, "precision", validationErr.Field)
+			}
+
+			assert.Panics(t, func() { Encode(locations, precision) })
+		}
+	})
+}
+
+func TestEncodeDecode(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		locations []base.Location
+		p5, p6    string
+	}{
+		{"empty", []base.Location{}, "", ""},
+		{"origin", []base.Location{{Latitude: 0, Longitude: 0}}, "??", "??"},
+		{"reference", []base.Location{
+			{Latitude: 38.5, Longitude: -120.2},
+			{Latitude: 40.7, Longitude: -120.95},
+			{Latitude: 43.252, Longitude: -126.453},
+		}, "_p~iF~ps|U_ulLnnqC_mqNvxq`@", "_izlhA~rlgdF_{geC~ywl@_kwzCn`{nI"},
+		{"negative", []base.Location{
+			{Latitude: -33.8688, Longitude: 151.2093},
+			{Latitude: -37.8136, Longitude: 144.9631},
+		}, "~~umEca|y[~maWv}be@", "~~dr_Agtal_H~twoFnrf|J"},
+		{"negative single", []base.Location{
+			{Latitude: -33.86882, Longitude: 151.20929},
+		}, "b_vmEaa|y[", "f`er_Assal_H"},
+		{"bounds", []base.Location{
+			{Latitude: 90, Longitude: 180},
+			{Latitude: -90, Longitude: -180},
+		}, "_cidP_gsia@~fsia@~ngtcA", "_gdtjD_oiivI~niivI~~ssmT"},
+		{"antimeridian", []base.Location{
+			{Latitude: 0, Longitude: 179.99999},
+			{Latitude: 0, Longitude: -179.99999},
+		}, "?}fsia@?zngtcA", "?kniivI?v}ssmT"},
+		{"repeated", []base.Location{
+			{Latitude: 51.5, Longitude: -0.12},
+			{Latitude: 51.5, Longitude: -0.12},
+		}, "_riyH~lV??", "_}hfaB~jiF??"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualValues(t, tt.p5, EncodeP5(tt.locations))
+			assert.EqualValues(t, tt.p6, EncodeP6(tt.locations))
+
+			p5, err := DecodeP5(tt.p5)
+			if !assert.Nil(t, err) {
+				t.FailNow()
+			}
+			p6, err := DecodePolyline6(tt.p6)
+			if !assert.Nil(t, err) {
+				t.FailNow()
+			}
+
+			for _, decoded := range [][]base.Location{p5, p6, mustDecode(t, tt.p5, 5), mustDecode(t, tt.p6, 6)} {
+				if !assert.Len(t, decoded, len(tt.locations)) {
+					t.FailNow()
+				}
+				for i := range decoded {
+					assert.InDelta(t, tt.locations[i].Latitude, decoded[i].Latitude, 1e-9)
+					assert.InDelta(t, tt.locations[i].Longitude, decoded[i].Longitude, 1e-9)
+				}
+			}
+		})
+	}
+
+	t.Run("Rejects invalid characters", func(t *testing.T) {
+		_, err := Decode("_p~iF ps|U", 5)
+		assert.NotNil(t, err)
+	})
+}
+
+func mustDecode(t *testing.T, encoded string, precision int) []base.Location {
+	decoded, err := Decode(encoded, precision)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return decoded
+}
@@ -0,0 +1,314 @@
+/**
+ * go-mapbox Static Images Module
+ * Wraps the mapbox static images API for server side use
+ * See https://docs.mapbox.com/api/maps/static-images/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package staticimage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const (
+	apiName    = "styles"
+	apiVersion = "v1"
+
+	// DefaultUsername is the owner of the default style
+	DefaultUsername = "mapbox"
+	// DefaultStyleID is the style used when none is specified
+	DefaultStyleID = "streets-v12"
+)
+
+// StaticImage api wrapper instance
+type StaticImage struct {
+	base *base.Base
+}
+
+// NewStaticImage Create a new Static Images API wrapper
+func NewStaticImage(base *base.Base) *StaticImage {
+	return &StaticImage{base}
+}
+
+// MaxSize is the maximum width or height of an image in pixels
+const MaxSize = 1280
+
+// MaxZoom is the maximum zoom level of an image
+const MaxZoom = 22
+
+// MaxOverlayLength is the maximum length of an encoded GeoJSON overlay
+const MaxOverlayLength = 4096
+
+// ErrOverlayTooLarge is returned when an encoded overlay exceeds MaxOverlayLength, as the API rejects long URLs
+var ErrOverlayTooLarge = errors.New("Static image overlay too large")
+
+// MaxURLLength is the maximum length of a request URL accepted by the API
+const MaxURLLength = 8192
+
+// ErrURLTooLong is returned when the request URL exceeds MaxURLLength, typically due to overlays
+var ErrURLTooLong = errors.New("Static image URL too long")
+
+// StaticOpts request options for the static images api
+type StaticOpts struct {
+	// Username is the owner of the style, defaulting to DefaultUsername
+	Username string
+	// StyleID is the style to render, defaulting to DefaultStyleID
+	StyleID string
+
+	// Longitude, Latitude, Zoom, Bearing and Pitch position the map when Auto is not set
+	Longitude float64
+	Latitude  float64
+	Zoom      float64
+	Bearing   float64
+	Pitch     float64
+
+	// Auto fits the map to the provided overlays instead of using the position above
+	Auto bool
+	// Padding (pixels) around the overlays when Auto is set
+	Padding int
+
+	// Width and Height of the image in pixels
+	Width  int
+	Height int
+	// Retina requests a high density (@2x) image
+	// There is no image format option, as the styles static API (unlike the classic v4 API) does not accept
+	// png, jpg or jpg90 formats. The format of the returned image is reported by the Fetch content type
+	Retina bool
+
+	Markers         []Marker
+	CustomMarkers   []CustomMarker
+	PathOverlays    []PathOverlay
+	GeoJSONOverlays []GeoJSONOverlay
+
+	// Overlays are drawn after the typed overlays above, in the order provided
+	Overlays []Overlay
+}
+
+// SetGeoJSONOverlay replaces the GeoJSON overlays with a feature collection
+// Features may be styled using simplestyle properties
+func (o *StaticOpts) SetGeoJSONOverlay(fc *base.FeatureCollection) error {
+	c := *fc
+	if c.Type == "" {
+		c.Type = "FeatureCollection"
+	}
+
+	data, err := json.Marshal(&c)
+	if err != nil {
+		return err
+	}
+
+	overlay := GeoJSONOverlay{GeoJSON: data}
+	if len(overlay.String()) > MaxOverlayLength {
+		return ErrOverlayTooLarge
+	}
+
+	o.GeoJSONOverlays = []GeoJSONOverlay{overlay}
+
+	return nil
+}
+
+// SetAutofit fits the map to the overlays, with padding (pixels) around them
+func (o *StaticOpts) SetAutofit(padding int) {
+	o.Auto = true
+	o.Padding = padding
+}
+
+// AddMarker adds a pin marker overlay
+func (o *StaticOpts) AddMarker(m Marker) {
+	o.Markers = append(o.Markers, m)
+}
+
+// AddCustomMarker adds a marker overlay using the icon image at the provided URL
+func (o *StaticOpts) AddCustomMarker(url string, lat, lon float64) {
+	o.CustomMarkers = append(o.CustomMarkers, CustomMarker{Longitude: lon, Latitude: lat, URL: url})
+}
+
+// queryString builds the request path for the provided options
+func (o *StaticOpts) queryString() string {
+	username, styleID := o.Username, o.StyleID
+	if username == "" {
+		username = DefaultUsername
+	}
+	if styleID == "" {
+		styleID = DefaultStyleID
+	}
+
+	segments := []string{apiName, apiVersion, username, styleID, "static"}
+
+	overlays := make([]string, 0)
+	for _, g := range o.GeoJSONOverlays {
+		overlays = append(overlays, g.String())
+	}
+	for _, p := range o.PathOverlays {
+		overlays = append(overlays, p.String())
+	}
+	for _, m := range o.Markers {
+		overlays = append(overlays, m.String())
+	}
+	for _, m := range o.CustomMarkers {
+		overlays = append(overlays, m.String())
+	}
+	for _, ov := range o.Overlays {
+		overlays = append(overlays, ov.String())
+	}
+	if len(overlays) > 0 {
+		segments = append(segments, strings.Join(overlays, ","))
+	}
+
+	if o.Auto {
+		segments = append(segments, "auto")
+	} else {
+		segments = append(segments, fmt.Sprintf("%v,%v,%v,%v,%v", o.Longitude, o.Latitude, o.Zoom, o.Bearing, o.Pitch))
+	}
+
+	size := fmt.Sprintf("%dx%d", o.Width, o.Height)
+	if o.Retina {
+		size += "@2x"
+	}
+	segments = append(segments, size)
+
+	return strings.Join(segments, "/")
+}
+
+// values builds the request arguments for the provided options
+func (o *StaticOpts) values() url.Values {
+	v := url.Values{}
+	if o.Padding != 0 {
+		v.Set("padding", fmt.Sprintf("%d", o.Padding))
+	}
+	return v
+}
+
+// GetURL returns the (tokenised) image URL for the provided options, for use as an image source
+func (s *StaticImage) GetURL(opts *StaticOpts) string {
+	v := opts.values()
+
+	return s.base.RequestURL(opts.queryString(), &v)
+}
+
+// validate checks the image size and position are within the API limits
+func (o *StaticOpts) validate() error {
+	if o.Width < 1 || o.Width > MaxSize {
+		return &base.ValidationError{Field: "width", Message: fmt.Sprintf("%d out of range [1, %d]", o.Width, MaxSize)}
+	}
+	if o.Height < 1 || o.Height > MaxSize {
+		return &base.ValidationError{Field: "height", Message: fmt.Sprintf("%d out of range [1, %d]", o.Height, MaxSize)}
+	}
+	if !o.Auto && (o.Zoom < 0 || o.Zoom > MaxZoom) {
+		return &base.ValidationError{Field: "zoom", Message: fmt.Sprintf("%v out of range [0, %d]", o.Zoom, MaxZoom)}
+	}
+	for _, m := range o.Markers {
+		if err := m.validate(); err != nil {
+			return err
+		}
+	}
+	for _, ov := range o.Overlays {
+		if m, ok := ov.(Marker); ok {
+			if err := m.validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ImageOpts request options for Image
+type ImageOpts = StaticOpts
+
+// ImageResponse is a rendered static image
+type ImageResponse struct {
+	// Data is the encoded image
+	Data []byte
+	// ContentType is the image MIME type
+	ContentType string
+	// URL is the (tokenised) image URL, for use as a cache key or image source
+	URL string
+}
+
+// Image renders the style with the provided options, overriding the style ID of the options
+func (s *StaticImage) Image(styleID string, opts *ImageOpts) (*ImageResponse, error) {
+	o := *opts
+	o.StyleID = styleID
+
+	data, contentType, err := s.Fetch(context.Background(), &o)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ImageResponse{Data: data, ContentType: contentType, URL: s.GetURL(&o)}, nil
+}
+
+// ImageTo renders the style with the provided options, streaming the image to the writer
+// The writer is only written to once the API has responded successfully
+func (s *StaticImage) ImageTo(ctx context.Context, styleID string, opts *ImageOpts, w io.Writer) (string, error) {
+	o := *opts
+	o.StyleID = styleID
+
+	resp, err := s.query(ctx, &o)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	_, err = io.Copy(w, resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("Error reading response body (%s)", err)
+	}
+
+	return resp.Header.Get("Content-Type"), nil
+}
+
+// Fetch downloads the image for the provided options, returning the image data and content type
+func (s *StaticImage) Fetch(ctx context.Context, opts *StaticOpts) ([]byte, string, error) {
+	resp, err := s.query(ctx, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("Error reading response body (%s)", err)
+	}
+
+	return data, resp.Header.Get("Content-Type"), nil
+}
+
+// query validates the options and requests the image, the caller must close the response body
+func (s *StaticImage) query(ctx context.Context, opts *StaticOpts) (*http.Response, error) {
+	err := opts.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.GetURL(opts)) > MaxURLLength {
+		return nil, ErrURLTooLong
+	}
+
+	v := opts.values()
+
+	return s.base.QueryRequestContext(ctx, opts.queryString(), &v)
+}
+
+// escape encodes an overlay component for use in a URL path
+func escape(s string) string {
+	return url.PathEscape(s)
+}
+
+// escapeURL fully percent-encodes a URL for use within an overlay
+func escapeURL(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
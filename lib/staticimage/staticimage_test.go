@@ -0,0 +1,370 @@
+/**
+ * go-mapbox Static Images Module Tests
+ * Wraps the mapbox static images API for server side use
+ * See https://docs.mapbox.com/api/maps/static-images/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package staticimage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/internal/basetest"
+)
+
+func TestStaticImage(t *testing.T) {
+
+	t.Run("Builds image URLs", func(t *testing.T) {
+		b, err := base.NewBase("test-token")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		opts := StaticOpts{
+			Longitude: -122.4241,
+			Latitude:  37.78,
+			Zoom:      14.25,
+			Width:     600,
+			Height:    400,
+			Retina:    true,
+			Markers:   []Marker{{Longitude: -122.4241, Latitude: 37.78, Label: "a", Color: "9ed4bd"}},
+			PathOverlays: []PathOverlay{{
+				Polyline:      "_p~iF~ps|U_ulLnnqC_mqNvxq`@",
+				StrokeWidth:   5,
+				StrokeColor:   "f44",
+				StrokeOpacity: 0.5,
+			}},
+		}
+
+		u := NewStaticImage(b).GetURL(&opts)
+		assert.EqualValues(t, base.BaseURL+"/styles/v1/mapbox/streets-v12/static/"+
+			"path-5+f44-0.5(_p~iF~ps%7CU_ulLnnqC_mqNvxq%60@),pin-s-a+9ed4bd(-122.4241,37.78)/"+
+			"-122.4241,37.78,14.25,0,0/600x400@2x?access_token=test-token", u)
+	})
+
+	t.Run("Builds auto-fit URLs", func(t *testing.T) {
+		b, err := base.NewBase("test-token")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		opts := StaticOpts{
+			Username:        "example",
+			StyleID:         "custom",
+			Auto:            true,
+			Padding:         10,
+			Width:           300,
+			Height:          200,
+			GeoJSONOverlays: []GeoJSONOverlay{{GeoJSON: []byte(`{"type":"Point","coordinates":[1,2]}`)}},
+		}
+
+		u := NewStaticImage(b).GetURL(&opts)
+		assert.EqualValues(t, base.BaseURL+"/styles/v1/example/custom/static/"+
+			"geojson(%7B%22type%22:%22Point%22%2C%22coordinates%22:%5B1%2C2%5D%7D)/auto/300x200?access_token=test-token&padding=10", u)
+	})
+
+	t.Run("Fetches images", func(t *testing.T) {
+		png := []byte("\x89PNG\r\n\x1a\nimage-data")
+
+		b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.EqualValues(t, "/styles/v1/mapbox/streets-v12/static/1,2,3,0,0/100x100", r.URL.Path)
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(png)
+		})
+
+		opts := StaticOpts{Longitude: 1, Latitude: 2, Zoom: 3, Width: 100, Height: 100}
+
+		data, contentType, err := NewStaticImage(b).Fetch(context.Background(), &opts)
+		assert.Nil(t, err)
+		assert.EqualValues(t, png, data)
+		assert.EqualValues(t, "image/png", contentType)
+	})
+}
+
+func TestMarkers(t *testing.T) {
+	b, err := base.NewBase("test-token")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	tests := []struct {
+		name    string
+		add     func(o *StaticOpts)
+		overlay string
+	}{
+		{"default marker", func(o *StaticOpts) {
+			o.AddMarker(Marker{Longitude: -76.9, Latitude: 38.9})
+		}, "pin-s(-76.9,38.9)"},
+		{"labelled marker", func(o *StaticOpts) {
+			o.AddMarker(Marker{Longitude: -76.9, Latitude: 38.9, Size: MarkerLarge, Label: "42", Color: "f74e4e"})
+		}, "pin-l-42+f74e4e(-76.9,38.9)"},
+		{"custom marker", func(o *StaticOpts) {
+			o.AddCustomMarker("https://docs.mapbox.com/api/img/custom-marker.png", 38.9, -76.9)
+		}, "url-https%3A%2F%2Fdocs.mapbox.com%2Fapi%2Fimg%2Fcustom-marker.png(-76.9,38.9)"},
+		{"custom marker with special characters", func(o *StaticOpts) {
+			o.AddCustomMarker("https://example.com/icons/my pin+1.png?size=2&color=#fff", 38.9, -76.9)
+		}, "url-https%3A%2F%2Fexample.com%2Ficons%2Fmy%20pin%2B1.png%3Fsize%3D2%26color%3D%23fff(-76.9,38.9)"},
+		{"markers and custom markers", func(o *StaticOpts) {
+			o.AddCustomMarker("https://example.com/a,b.png", 2, 1)
+			o.AddMarker(Marker{Longitude: 3, Latitude: 4, Label: "a"})
+		}, "pin-s-a(3,4),url-https%3A%2F%2Fexample.com%2Fa%2Cb.png(1,2)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := StaticOpts{Auto: true, Width: 100, Height: 100}
+			tt.add(&opts)
+
+			u := NewStaticImage(b).GetURL(&opts)
+			assert.EqualValues(t, base.BaseURL+"/styles/v1/mapbox/streets-v12/static/"+tt.overlay+"/auto/100x100?access_token=test-token", u)
+			assert.Nil(t, opts.validate())
+		})
+	}
+
+	labels := []struct {
+		label string
+		valid bool
+	}{
+		{"a", true},
+		{"z", true},
+		{"0", true},
+		{"99", true},
+		{"rail-metro", true},
+		{"A", false},
+		{"100", false},
+		{"-1", false},
+		{"07", false},
+		{"cafe!", false},
+		{"-cafe", false},
+	}
+
+	for _, tt := range labels {
+		t.Run("Validates label "+tt.label, func(t *testing.T) {
+			opts := StaticOpts{Auto: true, Width: 100, Height: 100}
+			opts.AddMarker(Marker{Longitude: 1, Latitude: 2, Label: tt.label})
+
+			err := opts.validate()
+			if tt.valid {
+				assert.Nil(t, err)
+				return
+			}
+
+			validationErr := &base.ValidationError{}
+			if assert.True(t, errors.As(err, &validationErr)) {
+				assert.EqualValues(t, "marker", validationErr.Field)
+			}
+
+			opts = StaticOpts{Auto: true, Width: 100, Height: 100, Overlays: []Overlay{Marker{Label: tt.label}}}
+			_, _, err = NewStaticImage(b).Fetch(context.Background(), &opts)
+			assert.True(t, errors.As(err, &validationErr))
+		})
+	}
+
+	t.Run("Rejects unknown sizes", func(t *testing.T) {
+		opts := StaticOpts{Auto: true, Width: 100, Height: 100, Markers: []Marker{{Size: "pin-m"}}}
+		assert.NotNil(t, opts.validate())
+	})
+}
+
+func TestGeoJSONOverlay(t *testing.T) {
+	b, err := base.NewBase("test-token")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	t.Run("Encodes feature collections", func(t *testing.T) {
+		fc := base.FeatureCollection{Features: []base.Feature{{
+			Geometry:      base.Geometry{Type: "Point", Coordinates: []float64{1, 2}},
+			RawProperties: map[string]interface{}{"marker-color": "#f00"},
+		}}}
+
+		opts := StaticOpts{Width: 300, Height: 200}
+		opts.SetAutofit(20)
+		err := opts.SetGeoJSONOverlay(&fc)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		u := NewStaticImage(b).GetURL(&opts)
+		assert.EqualValues(t, base.BaseURL+"/styles/v1/mapbox/streets-v12/static/"+
+			"geojson(%7B%22type%22:%22FeatureCollection%22%2C%22features%22:%5B%7B%22type%22:%22Feature%22%2C"+
+			"%22properties%22:%7B%22marker-color%22:%22%23f00%22%7D%2C%22geometry%22:%7B%22type%22:%22Point%22%2C"+
+			"%22coordinates%22:%5B1%2C2%5D%7D%7D%5D%2C%22attribution%22:%22%22%7D)/auto/300x200?access_token=test-token&padding=20", u)
+	})
+
+	t.Run("Rejects large overlays", func(t *testing.T) {
+		fc := base.FeatureCollection{}
+		for i := 0; i < 200; i++ {
+			fc.Features = append(fc.Features, base.Feature{
+				Geometry:      base.Geometry{Type: "Point", Coordinates: []float64{float64(i) / 10, 2}},
+				RawProperties: map[string]interface{}{"title": "Marker"},
+			})
+		}
+
+		opts := StaticOpts{}
+		err := opts.SetGeoJSONOverlay(&fc)
+		assert.True(t, errors.Is(err, ErrOverlayTooLarge))
+		assert.Nil(t, opts.GeoJSONOverlays)
+	})
+}
+
+func TestImage(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\nthumbnail")
+
+	var host string
+	b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+		assert.EqualValues(t, "/styles/v1/mapbox/light-v11/static/pin-l+f00(-0.1276,51.5072)/-0.1276,51.5072,12.5,30,45/640x320@2x", r.URL.Path)
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(png)
+	})
+	s := NewStaticImage(b)
+
+	t.Run("Renders images", func(t *testing.T) {
+		opts := ImageOpts{
+			StyleID:   "ignored",
+			Longitude: -0.1276,
+			Latitude:  51.5072,
+			Zoom:      12.5,
+			Bearing:   30,
+			Pitch:     45,
+			Width:     640,
+			Height:    320,
+			Retina:    true,
+			Markers:   []Marker{{Longitude: -0.1276, Latitude: 51.5072, Size: MarkerLarge, Color: "f00"}},
+		}
+
+		img, err := s.Image("light-v11", &opts)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, png, img.Data)
+		assert.EqualValues(t, "image/png", img.ContentType)
+		assert.EqualValues(t, "http://"+host+"/styles/v1/mapbox/light-v11/static/pin-l+f00(-0.1276,51.5072)/-0.1276,51.5072,12.5,30,45/640x320@2x?access_token=test-token", img.URL)
+		assert.EqualValues(t, "ignored", opts.StyleID)
+	})
+
+	t.Run("Validates size and zoom before querying", func(t *testing.T) {
+		tests := []struct {
+			opts  ImageOpts
+			field string
+		}{
+			{ImageOpts{Width: 1281, Height: 100}, "width"},
+			{ImageOpts{Width: 100, Height: 0}, "height"},
+			{ImageOpts{Width: 100, Height: 100, Zoom: 22.5}, "zoom"},
+		}
+
+		for _, tt := range tests {
+			_, err := s.Image("light-v11", &tt.opts)
+			validationErr := &base.ValidationError{}
+			if assert.True(t, errors.As(err, &validationErr)) {
+				assert.EqualValues(t, tt.field, validationErr.Field)
+			}
+		}
+	})
+}
+
+func TestOverlays(t *testing.T) {
+	b, err := base.NewBase("test-token")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	s := NewStaticImage(b)
+
+	tests := []struct {
+		name    string
+		overlay Overlay
+		encoded string
+	}{
+		{"marker", Marker{Longitude: -73.99, Latitude: 40.7, Size: MarkerLarge, Label: "cafe", Color: "3bb2d0"},
+			"pin-l-cafe+3bb2d0(-73.99,40.7)"},
+		{"path", Path{Polyline: "}rkmF~|vbM", StrokeWidth: 3, StrokeColor: "f00", StrokeOpacity: 0.8, FillColor: "00f", FillOpacity: 0.2},
+			"path-3+f00-0.8+00f-0.2(%7DrkmF~%7CvbM)"},
+		{"path with only a fill", Path{Polyline: "}rkmF~|vbM", FillColor: "00f", FillOpacity: 0.2},
+			"path-1+555555+00f-0.2(%7DrkmF~%7CvbM)"},
+		{"geojson", GeoJSONOverlay{GeoJSON: []byte(`{"type":"Point","coordinates":[-73.99,40.7]}`)},
+			"geojson(%7B%22type%22:%22Point%22%2C%22coordinates%22:%5B-73.99%2C40.7%5D%7D)"},
+	}
+
+	for _, tt := range tests {
+		t.Run("Encodes "+tt.name+" overlays", func(t *testing.T) {
+			assert.EqualValues(t, tt.encoded, tt.overlay.String())
+
+			opts := StaticOpts{Auto: true, Width: 100, Height: 100, Overlays: []Overlay{tt.overlay}}
+			assert.EqualValues(t, base.BaseURL+"/styles/v1/mapbox/streets-v12/static/"+tt.encoded+"/auto/100x100?access_token=test-token", s.GetURL(&opts))
+		})
+	}
+
+	t.Run("Preserves overlay order", func(t *testing.T) {
+		opts := StaticOpts{Auto: true, Width: 100, Height: 100, Overlays: []Overlay{
+			Marker{Longitude: 1, Latitude: 2},
+			Path{Polyline: "abc"},
+			Marker{Longitude: 3, Latitude: 4, Label: "b"},
+		}}
+		assert.EqualValues(t, base.BaseURL+"/styles/v1/mapbox/streets-v12/static/pin-s(1,2),path-1(abc),pin-s-b(3,4)/auto/100x100?access_token=test-token", s.GetURL(&opts))
+	})
+
+	t.Run("Rejects URLs over the length limit", func(t *testing.T) {
+		opts := StaticOpts{Auto: true, Width: 100, Height: 100}
+		for i := 0; i < 500; i++ {
+			opts.Overlays = append(opts.Overlays, Marker{Longitude: float64(i) / 100, Latitude: 51.5})
+		}
+
+		_, _, err := s.Fetch(context.Background(), &opts)
+		assert.True(t, errors.Is(err, ErrURLTooLong))
+	})
+}
+
+func TestImageTo(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01")
+
+	b := basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/styles/v1/mapbox/dark-v11/static/auto/200x100" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Style not found"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(png)
+	})
+	s := NewStaticImage(b)
+
+	t.Run("Streams images to the writer", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		contentType, err := s.ImageTo(context.Background(), "dark-v11", &ImageOpts{Auto: true, Width: 200, Height: 100}, &buf)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "image/png", contentType)
+		assert.EqualValues(t, png, buf.Bytes())
+	})
+
+	t.Run("Does not write API errors", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		_, err := s.ImageTo(context.Background(), "missing", &ImageOpts{Auto: true, Width: 200, Height: 100}, &buf)
+		apiErr := &base.APIError{}
+		if assert.True(t, errors.As(err, &apiErr)) {
+			assert.EqualValues(t, http.StatusNotFound, apiErr.StatusCode)
+		}
+		assert.EqualValues(t, 0, buf.Len())
+	})
+
+	t.Run("Does not write invalid requests", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		_, err := s.ImageTo(context.Background(), "dark-v11", &ImageOpts{Auto: true, Width: 0, Height: 100}, &buf)
+		validationErr := &base.ValidationError{}
+		assert.True(t, errors.As(err, &validationErr))
+		assert.EqualValues(t, 0, buf.Len())
+	})
+}
@@ -0,0 +1,169 @@
+/**
+ * go-mapbox Static Images Module Types
+ * Wraps the mapbox static images API for server side use
+ * See https://docs.mapbox.com/api/maps/static-images/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package staticimage
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+// Overlay is a marker, path or GeoJSON object drawn over the map
+type Overlay interface {
+	// String formats the overlay using the overlay notation
+	String() string
+}
+
+// MarkerSize is the size of a marker pin
+// The styles static API only supports small and large pins (the classic API also supported pin-m)
+type MarkerSize string
+
+const (
+	// MarkerSmall small marker pin
+	MarkerSmall MarkerSize = "pin-s"
+	// MarkerLarge large marker pin
+	MarkerLarge MarkerSize = "pin-l"
+)
+
+// Marker is a pin overlay at a location
+// https://docs.mapbox.com/api/maps/static-images/#marker
+type Marker struct {
+	Longitude float64
+	Latitude  float64
+	// Size of the marker, defaulting to MarkerSmall
+	Size MarkerSize
+	// Label is an optional letter (a to z), number (0 to 99) or maki icon name
+	Label string
+	// Color is an optional hex color (without the #)
+	Color string
+}
+
+// String formats the marker using the overlay notation
+func (m Marker) String() string {
+	s := string(m.Size)
+	if s == "" {
+		s = string(MarkerSmall)
+	}
+	if m.Label != "" {
+		s += "-" + escape(m.Label)
+	}
+	if m.Color != "" {
+		s += "+" + m.Color
+	}
+	return fmt.Sprintf("%s(%v,%v)", s, m.Longitude, m.Latitude)
+}
+
+// makiName matches the form of maki icon names, such as cafe or rail-metro
+var makiName = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// validate checks the marker size and label are supported
+func (m Marker) validate() error {
+	if m.Size != "" && m.Size != MarkerSmall && m.Size != MarkerLarge {
+		return &base.ValidationError{Field: "marker", Message: fmt.Sprintf("unknown size %s", m.Size)}
+	}
+	if m.Label == "" {
+		return nil
+	}
+	if n, err := strconv.Atoi(m.Label); err == nil {
+		if n < 0 || n > 99 || strconv.Itoa(n) != m.Label {
+			return &base.ValidationError{Field: "marker", Message: fmt.Sprintf("label %s out of range [0, 99]", m.Label)}
+		}
+		return nil
+	}
+	if len(m.Label) == 1 && m.Label[0] >= 'a' && m.Label[0] <= 'z' {
+		return nil
+	}
+	if len(m.Label) > 1 && makiName.MatchString(m.Label) {
+		return nil
+	}
+	return &base.ValidationError{Field: "marker", Message: fmt.Sprintf("label %q is not a letter, number or maki icon name", m.Label)}
+}
+
+// CustomMarker is a marker overlay using a custom icon image
+// https://docs.mapbox.com/api/maps/static-images/#custom-marker
+type CustomMarker struct {
+	Longitude float64
+	Latitude  float64
+	// URL of the PNG or JPEG icon image
+	URL string
+}
+
+// String formats the custom marker using the overlay notation
+func (m CustomMarker) String() string {
+	return fmt.Sprintf("url-%s(%v,%v)", escapeURL(m.URL), m.Longitude, m.Latitude)
+}
+
+// DefaultStrokeColor is the API default path stroke color, sent where only a fill color is set
+// as the API reads the first color of a path as the stroke
+const DefaultStrokeColor = "555555"
+
+// Path is an alias of PathOverlay
+type Path = PathOverlay
+
+// PathOverlay is a line or polygon overlay described by an encoded polyline
+// https://docs.mapbox.com/api/maps/static-images/#path
+type PathOverlay struct {
+	// Polyline is the precision 5 encoded path
+	Polyline string
+	// StrokeWidth in pixels, defaults to 1 when zero
+	StrokeWidth int
+	// StrokeColor is an optional hex color (without the #), defaulting to DefaultStrokeColor where a fill is set
+	StrokeColor string
+	// StrokeOpacity between 0 and 1, omitted when zero
+	StrokeOpacity float64
+	// FillColor is an optional hex color (without the #) for closed paths
+	FillColor string
+	// FillOpacity between 0 and 1, omitted when zero
+	FillOpacity float64
+}
+
+// String formats the path using the overlay notation
+func (p PathOverlay) String() string {
+	width := p.StrokeWidth
+	if width == 0 {
+		width = 1
+	}
+
+	stroke := p.StrokeColor
+	if stroke == "" && p.FillColor != "" {
+		stroke = DefaultStrokeColor
+	}
+
+	s := fmt.Sprintf("path-%d", width)
+	if stroke != "" {
+		s += "+" + stroke
+		if p.StrokeOpacity != 0 {
+			s += fmt.Sprintf("-%v", p.StrokeOpacity)
+		}
+	}
+	if p.FillColor != "" {
+		s += "+" + p.FillColor
+		if p.FillOpacity != 0 {
+			s += fmt.Sprintf("-%v", p.FillOpacity)
+		}
+	}
+
+	return fmt.Sprintf("%s(%s)", s, escape(p.Polyline))
+}
+
+// GeoJSONOverlay is an arbitrary GeoJSON object overlay, styled using simplestyle properties
+// https://docs.mapbox.com/api/maps/static-images/#geojson
+type GeoJSONOverlay struct {
+	// GeoJSON is an encoded GeoJSON Feature, FeatureCollection or Geometry
+	GeoJSON json.RawMessage
+}
+
+// String formats the GeoJSON object using the overlay notation
+func (g GeoJSONOverlay) String() string {
+	return fmt.Sprintf("geojson(%s)", escape(string(g.GeoJSON)))
+}
@@ -0,0 +1,191 @@
+/**
+ * go-mapbox Styles Module
+ * Wraps the mapbox styles API for server side use
+ * See https://docs.mapbox.com/api/maps/styles/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package styles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/go-querystring/query"
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const (
+	apiName         = "styles"
+	apiVersion      = "v1"
+	apiFontsName    = "fonts"
+	apiFontsVersion = "v1"
+
+	scopeList      = "styles:list"
+	scopeRead      = "styles:read"
+	scopeWrite     = "styles:write"
+	scopeFontsRead = "fonts:read"
+)
+
+// Styles api wrapper instance
+type Styles struct {
+	base *base.Base
+}
+
+// NewStyles Create a new Styles API wrapper
+func NewStyles(base *base.Base) *Styles {
+	return &Styles{base}
+}
+
+// ListOpts request options for listing styles
+type ListOpts struct {
+	// Start is the ID of the style to start listing after, for pagination
+	Start string `url:"start,omitempty"`
+	// Limit is the maximum number of styles to return
+	Limit int `url:"limit,omitempty"`
+	// Draft lists draft rather than published versions of styles
+	Draft bool `url:"draft,omitempty"`
+}
+
+// List the styles belonging to a user
+func (s *Styles) List(ctx context.Context, username string, opts *ListOpts) ([]StyleMeta, error) {
+	if opts == nil {
+		opts = &ListOpts{}
+	}
+
+	v, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]StyleMeta, 0)
+
+	queryString := fmt.Sprintf("%s/%s/%s", apiName, apiVersion, username)
+
+	err = s.base.QueryBaseContext(ctx, queryString, &v, &resp)
+	if err != nil {
+		return nil, wrapError(err, scopeList)
+	}
+
+	return resp, nil
+}
+
+// ListStyles lists the styles belonging to a user, requiring a token with the styles:list scope
+func (s *Styles) ListStyles(username string) ([]StyleMeta, error) {
+	return s.List(context.Background(), username, nil)
+}
+
+// GetStyle fetches a style document, requiring a token with the styles:read scope
+func (s *Styles) GetStyle(username, styleID string) (*Style, error) {
+	return s.Get(context.Background(), username, styleID)
+}
+
+// Get fetches a style document
+func (s *Styles) Get(ctx context.Context, username, styleID string) (*StyleDocument, error) {
+	v := url.Values{}
+
+	resp := StyleDocument{}
+
+	queryString := fmt.Sprintf("%s/%s/%s/%s", apiName, apiVersion, username, styleID)
+
+	err := s.base.QueryBaseContext(ctx, queryString, &v, &resp)
+	if err != nil {
+		return nil, wrapError(err, scopeRead)
+	}
+
+	return &resp, nil
+}
+
+// Create a new style, returning the metadata of the created style
+func (s *Styles) Create(ctx context.Context, username string, style StyleDocument) (*StyleMeta, error) {
+	v := url.Values{}
+
+	resp := StyleMeta{}
+
+	queryString := fmt.Sprintf("%s/%s/%s", apiName, apiVersion, username)
+
+	err := s.base.QueryWithBodyBase(ctx, http.MethodPost, queryString, &v, &style, &resp)
+	if err != nil {
+		return nil, wrapError(err, scopeWrite)
+	}
+
+	return &resp, nil
+}
+
+// Update an existing style, returning the metadata of the updated style
+func (s *Styles) Update(ctx context.Context, username, styleID string, style StyleDocument) (*StyleMeta, error) {
+	v := url.Values{}
+
+	resp := StyleMeta{}
+
+	queryString := fmt.Sprintf("%s/%s/%s/%s", apiName, apiVersion, username, styleID)
+
+	err := s.base.QueryWithBodyBase(ctx, http.MethodPatch, queryString, &v, &style, &resp)
+	if err != nil {
+		return nil, wrapError(err, scopeWrite)
+	}
+
+	return &resp, nil
+}
+
+// Delete a style
+func (s *Styles) Delete(ctx context.Context, username, styleID string) error {
+	v := url.Values{}
+
+	queryString := fmt.Sprintf("%s/%s/%s/%s", apiName, apiVersion, username, styleID)
+
+	err := s.base.QueryWithBodyBase(ctx, http.MethodDelete, queryString, &v, nil, nil)
+
+	return wrapError(err, scopeWrite)
+}
+
+// RetrieveSprite fetches the sprite index of a style, mapping icon names to their location in the sprite image
+func (s *Styles) RetrieveSprite(ctx context.Context, username, styleID string, highDPI bool) (map[string]SpriteImage, error) {
+	v := url.Values{}
+
+	dpiFlag := ""
+	if highDPI {
+		dpiFlag = "@2x"
+	}
+
+	resp := make(map[string]SpriteImage)
+
+	queryString := fmt.Sprintf("%s/%s/%s/%s/sprite%s.json", apiName, apiVersion, username, styleID, dpiFlag)
+
+	err := s.base.QueryBaseContext(ctx, queryString, &v, &resp)
+	if err != nil {
+		return nil, wrapError(err, scopeRead)
+	}
+
+	return resp, nil
+}
+
+// ListFonts lists the fonts available to a user
+func (s *Styles) ListFonts(ctx context.Context, username string) ([]string, error) {
+	v := url.Values{}
+
+	resp := make([]string, 0)
+
+	queryString := fmt.Sprintf("%s/%s/%s", apiFontsName, apiFontsVersion, username)
+
+	err := s.base.QueryBaseContext(ctx, queryString, &v, &resp)
+	if err != nil {
+		return nil, wrapError(err, scopeFontsRead)
+	}
+
+	return resp, nil
+}
+
+// wrapError converts style validation failures into ValidationErrors, and authorization failures into ScopeErrors
+func wrapError(err error, scope string) error {
+	apiErr := &base.APIError{}
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity {
+		return newValidationError(apiErr)
+	}
+	return base.WrapScopeError(err, scope)
+}
@@ -0,0 +1,232 @@
+/**
+ * go-mapbox Styles Module Tests
+ * Wraps the mapbox styles API for server side use
+ * See https://docs.mapbox.com/api/maps/styles/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package styles
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/internal/basetest"
+)
+
+const styleResponse = `{
+	"version": 8,
+	"name": "Example",
+	"id": "cjexample",
+	"owner": "example",
+	"created": "2023-01-01T00:00:00.000Z",
+	"modified": "2023-01-02T00:00:00.000Z",
+	"visibility": "private",
+	"draft": false,
+	"center": [-122.4, 37.8],
+	"zoom": 12,
+	"sources": {"composite": {"type": "vector", "url": "mapbox://mapbox.mapbox-streets-v8"}},
+	"sprite": "mapbox://sprites/example/cjexample",
+	"glyphs": "mapbox://fonts/example/{fontstack}/{range}.pbf",
+	"layers": [
+		{"id": "background", "type": "background", "paint": {"background-color": "#fff"}},
+		{"id": "roads", "type": "line", "source": "composite", "source-layer": "road", "minzoom": 5}
+	]
+}`
+
+func TestStyles(t *testing.T) {
+
+	t.Run("Can list styles", func(t *testing.T) {
+		s := NewStyles(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.EqualValues(t, "/styles/v1/example", r.URL.Path)
+			assert.EqualValues(t, "cjstart", r.URL.Query().Get("start"))
+			assert.EqualValues(t, "2", r.URL.Query().Get("limit"))
+			w.Write([]byte(`[{"version":8,"name":"One","id":"one","owner":"example"},{"version":8,"name":"Two","id":"two","owner":"example"}]`))
+		}))
+
+		res, err := s.List(context.Background(), "example", &ListOpts{Start: "cjstart", Limit: 2})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.Len(t, res, 2)
+		assert.EqualValues(t, "two", res[1].ID)
+	})
+
+	t.Run("Can get styles", func(t *testing.T) {
+		s := NewStyles(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.EqualValues(t, http.MethodGet, r.Method)
+			assert.EqualValues(t, "/styles/v1/example/cjexample", r.URL.Path)
+			w.Write([]byte(styleResponse))
+		}))
+
+		style, err := s.Get(context.Background(), "example", "cjexample")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, 8, style.Version)
+		assert.EqualValues(t, "private", style.Visibility)
+		assert.EqualValues(t, "vector", style.Sources["composite"].Type)
+		assert.Len(t, style.Layers, 2)
+		assert.EqualValues(t, "road", style.Layers[1].SourceLayer)
+		assert.EqualValues(t, "#fff", style.Layers[0].Paint["background-color"])
+	})
+
+	t.Run("Can create and update styles", func(t *testing.T) {
+		s := NewStyles(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			style := StyleDocument{}
+			assert.Nil(t, json.Unmarshal(body, &style))
+			assert.EqualValues(t, "Example", style.Name)
+			assert.NotContains(t, string(body), "created")
+
+			switch r.Method {
+			case http.MethodPost:
+				assert.EqualValues(t, "/styles/v1/example", r.URL.Path)
+			case http.MethodPatch:
+				assert.EqualValues(t, "/styles/v1/example/cjexample", r.URL.Path)
+			default:
+				t.Errorf("unexpected method %s", r.Method)
+			}
+			w.Write([]byte(styleResponse))
+		}))
+
+		style := StyleDocument{
+			StyleMeta: StyleMeta{Version: 8, Name: "Example"},
+			Sources:   map[string]Source{},
+			Layers:    []Layer{{ID: "background", Type: "background"}},
+		}
+
+		meta, err := s.Create(context.Background(), "example", style)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "cjexample", meta.ID)
+
+		meta, err = s.Update(context.Background(), "example", "cjexample", style)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "2023-01-02T00:00:00.000Z", meta.Modified)
+	})
+
+	t.Run("Can delete styles", func(t *testing.T) {
+		s := NewStyles(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.EqualValues(t, http.MethodDelete, r.Method)
+			assert.EqualValues(t, "/styles/v1/example/cjexample", r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		err := s.Delete(context.Background(), "example", "cjexample")
+		assert.Nil(t, err)
+	})
+
+	t.Run("Can retrieve sprites and fonts", func(t *testing.T) {
+		s := NewStyles(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/styles/v1/example/cjexample/sprite@2x.json":
+				w.Write([]byte(`{"airport-15":{"width":42,"height":42,"x":0,"y":0,"pixelRatio":2}}`))
+			case "/fonts/v1/example":
+				w.Write([]byte(`["Open Sans Regular","Arial Unicode MS Regular"]`))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+
+		sprite, err := s.RetrieveSprite(context.Background(), "example", "cjexample", true)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, 42, sprite["airport-15"].Width)
+		assert.EqualValues(t, 2, sprite["airport-15"].PixelRatio)
+
+		fonts, err := s.ListFonts(context.Background(), "example")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, []string{"Open Sans Regular", "Arial Unicode MS Regular"}, fonts)
+	})
+
+	t.Run("Returns validation errors", func(t *testing.T) {
+		s := NewStyles(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message":"Style validation error","errors":["layers[0]: missing required property \"type\"","sources.composite.url: string expected"]}`))
+		}))
+
+		_, err := s.Create(context.Background(), "example", StyleDocument{})
+
+		validationErr := &ValidationError{}
+		if !assert.True(t, errors.As(err, &validationErr)) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, []FieldError{
+			{Field: "layers[0]", Message: "missing required property \"type\""},
+			{Field: "sources.composite.url", Message: "string expected"},
+		}, validationErr.Fields)
+
+		apiErr := &base.APIError{}
+		assert.True(t, errors.As(err, &apiErr))
+		assert.EqualValues(t, http.StatusUnprocessableEntity, apiErr.StatusCode)
+	})
+
+	t.Run("Lists and gets styles without a context", func(t *testing.T) {
+		s := NewStyles(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/styles/v1/example":
+				w.Write([]byte(`[
+					{"version":8,"name":"One","id":"one","owner":"example","created":"2023-01-01T00:00:00.000Z","modified":"2023-02-01T00:00:00.000Z","visibility":"public"},
+					{"version":8,"name":"Two","id":"two","owner":"example","created":"2023-03-01T00:00:00.000Z","modified":"2023-04-01T00:00:00.000Z","visibility":"private"}
+				]`))
+			case "/styles/v1/example/cjexample":
+				w.Write([]byte(styleResponse))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+
+		list, err := s.ListStyles("example")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, []StyleMeta{
+			{Version: 8, Name: "One", ID: "one", Owner: "example", Created: "2023-01-01T00:00:00.000Z", Modified: "2023-02-01T00:00:00.000Z", Visibility: "public"},
+			{Version: 8, Name: "Two", ID: "two", Owner: "example", Created: "2023-03-01T00:00:00.000Z", Modified: "2023-04-01T00:00:00.000Z", Visibility: "private"},
+		}, list)
+
+		style, err := s.GetStyle("example", "cjexample")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "Example", style.Name)
+	})
+
+	t.Run("Returns scope errors", func(t *testing.T) {
+		s := NewStyles(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"This endpoint requires a token with styles:read scope"}`))
+		}))
+
+		_, err := s.GetStyle("example", "cjexample")
+
+		scopeErr := &ScopeError{}
+		if !assert.True(t, errors.As(err, &scopeErr)) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "styles:read", scopeErr.Scope)
+		assert.EqualValues(t, "Mapbox API error 401, token requires the styles:read scope (This endpoint requires a token with styles:read scope)", err.Error())
+		assert.True(t, errors.Is(err, base.ErrorAPIUnauthorized))
+
+		_, err = s.ListStyles("example")
+		if assert.True(t, errors.As(err, &scopeErr)) {
+			assert.EqualValues(t, "styles:list", scopeErr.Scope)
+		}
+	})
+}
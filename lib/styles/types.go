@@ -0,0 +1,148 @@
+/**
+ * go-mapbox Styles Module Types
+ * Wraps the mapbox styles API for server side use
+ * See https://docs.mapbox.com/api/maps/styles/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package styles
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+// StyleMeta is the metadata describing a style
+// https://docs.mapbox.com/api/maps/styles/#the-style-object
+type StyleMeta struct {
+	Version    int    `json:"version,omitempty"`
+	Name       string `json:"name,omitempty"`
+	ID         string `json:"id,omitempty"`
+	Owner      string `json:"owner,omitempty"`
+	Created    string `json:"created,omitempty"`
+	Modified   string `json:"modified,omitempty"`
+	Visibility string `json:"visibility,omitempty"`
+	Draft      bool   `json:"draft,omitempty"`
+}
+
+// StyleDocument is a Mapbox GL style
+// https://docs.mapbox.com/mapbox-gl-js/style-spec/root/
+type StyleDocument struct {
+	StyleMeta
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	Center     []float64              `json:"center,omitempty"`
+	Zoom       float64                `json:"zoom,omitempty"`
+	Bearing    float64                `json:"bearing,omitempty"`
+	Pitch      float64                `json:"pitch,omitempty"`
+	Sources    map[string]Source      `json:"sources"`
+	Sprite     string                 `json:"sprite,omitempty"`
+	Glyphs     string                 `json:"glyphs,omitempty"`
+	Layers     []Layer                `json:"layers"`
+	Light      map[string]interface{} `json:"light,omitempty"`
+	Terrain    map[string]interface{} `json:"terrain,omitempty"`
+	Fog        map[string]interface{} `json:"fog,omitempty"`
+	Transition map[string]interface{} `json:"transition,omitempty"`
+}
+
+// Style is an alias of StyleDocument
+type Style = StyleDocument
+
+// Source is a data source referenced by style layers
+// https://docs.mapbox.com/mapbox-gl-js/style-spec/sources/
+type Source struct {
+	Type        string      `json:"type"`
+	URL         string      `json:"url,omitempty"`
+	Tiles       []string    `json:"tiles,omitempty"`
+	TileSize    int         `json:"tileSize,omitempty"`
+	MinZoom     float64     `json:"minzoom,omitempty"`
+	MaxZoom     float64     `json:"maxzoom,omitempty"`
+	Bounds      []float64   `json:"bounds,omitempty"`
+	Attribution string      `json:"attribution,omitempty"`
+	Data        interface{} `json:"data,omitempty"`
+}
+
+// Layer is a style layer
+// https://docs.mapbox.com/mapbox-gl-js/style-spec/layers/
+type Layer struct {
+	ID          string                 `json:"id"`
+	Type        string                 `json:"type"`
+	Source      string                 `json:"source,omitempty"`
+	SourceLayer string                 `json:"source-layer,omitempty"`
+	MinZoom     float64                `json:"minzoom,omitempty"`
+	MaxZoom     float64                `json:"maxzoom,omitempty"`
+	Filter      interface{}            `json:"filter,omitempty"`
+	Layout      map[string]interface{} `json:"layout,omitempty"`
+	Paint       map[string]interface{} `json:"paint,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// SpriteImage is the location of an icon within a sprite image
+type SpriteImage struct {
+	Width      int     `json:"width"`
+	Height     int     `json:"height"`
+	X          int     `json:"x"`
+	Y          int     `json:"y"`
+	PixelRatio float64 `json:"pixelRatio"`
+}
+
+// FieldError is a validation failure of a single field within a style
+type FieldError struct {
+	// Field is the path to the invalid field, for example layers[0].paint
+	Field   string
+	Message string
+}
+
+// ValidationError is returned when the API rejects a style as invalid (HTTP 422)
+type ValidationError struct {
+	*base.APIError
+	Fields []FieldError
+}
+
+// newValidationError parses the per-field errors from a validation failure response
+func newValidationError(apiErr *base.APIError) *ValidationError {
+	body := struct {
+		Errors []string `json:"errors"`
+	}{}
+	json.Unmarshal(apiErr.Body, &body)
+
+	e := &ValidationError{APIError: apiErr}
+	for _, s := range body.Errors {
+		parts := strings.SplitN(s, ": ", 2)
+		if len(parts) == 2 {
+			e.Fields = append(e.Fields, FieldError{Field: parts[0], Message: parts[1]})
+		} else {
+			e.Fields = append(e.Fields, FieldError{Message: s})
+		}
+	}
+
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Fields) == 0 {
+		return fmt.Sprintf("Style validation failed (%s)", e.Message)
+	}
+
+	fields := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		if f.Field == "" {
+			fields[i] = f.Message
+		} else {
+			fields[i] = fmt.Sprintf("%s: %s", f.Field, f.Message)
+		}
+	}
+	return fmt.Sprintf("Style validation failed (%s)", strings.Join(fields, ", "))
+}
+
+// Unwrap returns the underlying APIError
+func (e *ValidationError) Unwrap() error {
+	return e.APIError
+}
+
+// ScopeError is returned when the API rejects a request as unauthorized
+type ScopeError = base.ScopeError
@@ -0,0 +1,152 @@
+/**
+ * go-mapbox Tilequery Module
+ * Wraps the mapbox tilequery API for server side use
+ * See https://docs.mapbox.com/api/maps/tilequery/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package tilequery
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/go-querystring/query"
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const (
+	apiName    = "tilequery"
+	apiVersion = "v4"
+
+	// MaxLimit is the maximum number of features returned by a query
+	MaxLimit = 50
+)
+
+// GeometryType filters the geometry types of returned features
+type GeometryType string
+
+const (
+	// GeometryPoint returns only point features
+	GeometryPoint GeometryType = "point"
+	// GeometryLinestring returns only line features
+	GeometryLinestring GeometryType = "linestring"
+	// GeometryPolygon returns only polygon features
+	GeometryPolygon GeometryType = "polygon"
+)
+
+// Tilequery api wrapper instance
+type Tilequery struct {
+	base *base.Base
+}
+
+// NewTilequery Create a new Tilequery API wrapper
+func NewTilequery(base *base.Base) *Tilequery {
+	return &Tilequery{base}
+}
+
+// TilequeryOpts request options for the tilequery api
+type TilequeryOpts struct {
+	// Radius (meters) around the location to search, defaulting to 0 (features containing the location)
+	Radius int `url:"radius,omitempty"`
+	// Limit is the maximum number of features returned (up to MaxLimit), defaulting to 5
+	Limit int `url:"limit,omitempty"`
+	// Dedupe removes duplicate features split across tiles, defaulting to true when nil
+	Dedupe *bool `url:"dedupe,omitempty"`
+	// Geometry restricts results to a single geometry type
+	Geometry GeometryType `url:"geometry,omitempty"`
+	// Layers restricts results to the named layers
+	Layers []string `url:"layers,omitempty,comma"`
+}
+
+// validate checks the options before a request is made
+func (o *TilequeryOpts) validate() error {
+	if o.Radius < 0 {
+		return &base.ValidationError{Field: "radius", Message: "must not be negative"}
+	}
+	if o.Limit < 0 || o.Limit > MaxLimit {
+		return &base.ValidationError{Field: "limit", Message: fmt.Sprintf("%d out of range (maximum %d)", o.Limit, MaxLimit)}
+	}
+	return nil
+}
+
+// TilequeryResponse is the response from Query
+// Features are sorted by distance from the queried location
+type TilequeryResponse struct {
+	base.FeatureCollection
+}
+
+// TilequeryFeature wraps a feature returned by the tilequery API with typed accessors
+type TilequeryFeature struct {
+	base.Feature
+}
+
+// TilequeryProperties are the query details added to the properties of each feature
+type TilequeryProperties struct {
+	// Distance (meters) from the queried location, zero where the feature contains the location
+	Distance float64 `json:"distance"`
+	// Geometry is the type of the feature geometry (point, linestring or polygon)
+	Geometry string `json:"geometry"`
+	// Layer is the tileset layer containing the feature
+	Layer string `json:"layer"`
+}
+
+// TilequeryFeatures returns the features of the response in distance order
+func (r *TilequeryResponse) TilequeryFeatures() []TilequeryFeature {
+	features := make([]TilequeryFeature, len(r.Features))
+	for i, f := range r.Features {
+		features[i] = TilequeryFeature{f}
+	}
+	return features
+}
+
+// Tilequery decodes the tilequery property of the feature
+func (f *TilequeryFeature) Tilequery() (*TilequeryProperties, error) {
+	raw, ok := f.RawProperties["tilequery"]
+	if !ok {
+		return nil, fmt.Errorf("Feature has no tilequery property")
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	props := TilequeryProperties{}
+	err = json.Unmarshal(data, &props)
+	if err != nil {
+		return nil, err
+	}
+
+	return &props, nil
+}
+
+// Query fetches the features of a tileset at (or near) a location
+// Multiple tilesets may be queried by passing a comma separated list of tileset IDs
+func (t *Tilequery) Query(tilesetID string, loc base.Location, opts *TilequeryOpts) (*TilequeryResponse, error) {
+	if opts == nil {
+		opts = &TilequeryOpts{}
+	}
+
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	v, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	queryString := fmt.Sprintf("%s/%s/%s/%f,%f.json", apiVersion, tilesetID, apiName, loc.Longitude, loc.Latitude)
+
+	resp := TilequeryResponse{}
+
+	err = t.base.QueryBase(queryString, &v, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,120 @@
+/**
+ * go-mapbox Tilequery Module Tests
+ * Wraps the mapbox tilequery API for server side use
+ * See https://docs.mapbox.com/api/maps/tilequery/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package tilequery
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/internal/basetest"
+)
+
+const tilequeryResponse = `{
+	"type": "FeatureCollection",
+	"features": [{
+		"type": "Feature",
+		"id": 1,
+		"geometry": {"type": "Point", "coordinates": [-122.4194, 37.7749]},
+		"properties": {"name": "Mission", "tilequery": {"distance": 0, "geometry": "polygon", "layer": "neighborhoods"}}
+	}, {
+		"type": "Feature",
+		"id": 2,
+		"geometry": {"type": "Point", "coordinates": [-122.4188, 37.7752]},
+		"properties": {"name": "Valencia St", "tilequery": {"distance": 64.2, "geometry": "linestring", "layer": "road"}}
+	}, {
+		"type": "Feature",
+		"id": 3,
+		"geometry": {"type": "Point", "coordinates": [-122.4170, 37.7760]},
+		"properties": {"name": "Cafe", "tilequery": {"distance": 231.7, "geometry": "point", "layer": "poi_label"}}
+	}]
+}`
+
+func TestTilequery(t *testing.T) {
+	loc := base.Location{Latitude: 37.7749, Longitude: -122.4194}
+
+	t.Run("Queries features", func(t *testing.T) {
+		tq := NewTilequery(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.EqualValues(t, "/v4/mapbox.mapbox-streets-v8/tilequery/-122.419400,37.774900.json", r.URL.Path)
+			assert.EqualValues(t, "250", r.URL.Query().Get("radius"))
+			assert.EqualValues(t, "10", r.URL.Query().Get("limit"))
+			assert.EqualValues(t, "false", r.URL.Query().Get("dedupe"))
+			assert.EqualValues(t, "neighborhoods,road,poi_label", r.URL.Query().Get("layers"))
+			assert.EqualValues(t, "", r.URL.Query().Get("geometry"))
+			w.Write([]byte(tilequeryResponse))
+		}))
+
+		dedupe := false
+		opts := TilequeryOpts{
+			Radius: 250,
+			Limit:  10,
+			Dedupe: &dedupe,
+			Layers: []string{"neighborhoods", "road", "poi_label"},
+		}
+
+		res, err := tq.Query("mapbox.mapbox-streets-v8", loc, &opts)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		features := res.TilequeryFeatures()
+		if !assert.Len(t, features, 3) {
+			t.FailNow()
+		}
+
+		distances := make([]float64, len(features))
+		for i, f := range features {
+			props, err := f.Tilequery()
+			if !assert.Nil(t, err) {
+				t.FailNow()
+			}
+			distances[i] = props.Distance
+		}
+		assert.EqualValues(t, []float64{0, 64.2, 231.7}, distances)
+
+		props, err := features[1].Tilequery()
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "linestring", props.Geometry)
+		assert.EqualValues(t, "road", props.Layer)
+		assert.EqualValues(t, "Valencia St", features[1].RawProperties["name"])
+	})
+
+	t.Run("Filters geometry types", func(t *testing.T) {
+		tq := NewTilequery(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.EqualValues(t, "polygon", r.URL.Query().Get("geometry"))
+			assert.EqualValues(t, "", r.URL.Query().Get("dedupe"))
+			w.Write([]byte(`{"type":"FeatureCollection","features":[]}`))
+		}))
+
+		res, err := tq.Query("mapbox.mapbox-streets-v8", loc, &TilequeryOpts{Geometry: GeometryPolygon})
+		assert.Nil(t, err)
+		assert.Len(t, res.TilequeryFeatures(), 0)
+	})
+
+	t.Run("Validates options before querying", func(t *testing.T) {
+		tq := NewTilequery(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("Unexpected request: %s", r.URL)
+		}))
+
+		_, err := tq.Query("mapbox.mapbox-streets-v8", loc, &TilequeryOpts{Limit: MaxLimit + 1})
+		validationErr := &base.ValidationError{}
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.EqualValues(t, "limit", validationErr.Field)
+		}
+
+		_, err = tq.Query("mapbox.mapbox-streets-v8", loc, &TilequeryOpts{Radius: -1})
+		assert.True(t, errors.As(err, &validationErr))
+	})
+}
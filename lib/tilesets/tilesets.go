@@ -0,0 +1,117 @@
+/**
+ * go-mapbox Tilesets Module
+ * Wraps the mapbox tilesets API for server side use
+ * See https://docs.mapbox.com/api/maps/mapbox-tiling-service/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package tilesets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+
+	"github.com/google/go-querystring/query"
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const (
+	apiName    = "tilesets"
+	apiVersion = "v1"
+
+	scopeList = "tilesets:list"
+	scopeRead = "tilesets:read"
+)
+
+// Tilesets api wrapper instance
+type Tilesets struct {
+	base *base.Base
+}
+
+// NewTilesets Create a new Tilesets API wrapper
+func NewTilesets(base *base.Base) *Tilesets {
+	return &Tilesets{base}
+}
+
+// ListOpts request options for listing tilesets
+type ListOpts struct {
+	// Type filters tilesets by type (vector or raster)
+	Type TilesetType `url:"type,omitempty"`
+	// Visibility filters tilesets by visibility (public or private)
+	Visibility Visibility `url:"visibility,omitempty"`
+	// SortBy sorts tilesets by their creation or modification time
+	SortBy SortBy `url:"sortby,omitempty"`
+	// Limit is the maximum number of tilesets to return (1 to 500)
+	Limit int `url:"limit,omitempty"`
+	// Start is the cursor returned as TilesetPage.Next, for pagination
+	Start string `url:"start,omitempty"`
+}
+
+// validate checks the options before a request is made
+func (o *ListOpts) validate() error {
+	if o.Limit < 0 || o.Limit > MaxLimit {
+		return &base.ValidationError{Field: "limit", Message: fmt.Sprintf("%d out of range [1, %d]", o.Limit, MaxLimit)}
+	}
+	return nil
+}
+
+// ListTilesets lists the tilesets belonging to a user, requiring a token with the tilesets:list scope
+// Subsequent pages are fetched by passing the returned TilesetPage.Next as ListOpts.Start
+func (t *Tilesets) ListTilesets(username string, opts *ListOpts) (*TilesetPage, error) {
+	if opts == nil {
+		opts = &ListOpts{}
+	}
+
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	v, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	queryString := fmt.Sprintf("%s/%s/%s", apiName, apiVersion, url.PathEscape(username))
+
+	resp, err := t.base.QueryRequestContext(context.Background(), queryString, &v)
+	if err != nil {
+		return nil, base.WrapScopeError(err, scopeList)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	page := TilesetPage{Tilesets: make([]Tileset, 0)}
+	err = json.Unmarshal(body, &page.Tilesets)
+	if err != nil {
+		return nil, err
+	}
+
+	page.Next, _ = base.NewPaginator(resp).Next()
+
+	return &page, nil
+}
+
+// TilesetStatus fetches the publishing status of a tileset, requiring a token with the tilesets:read scope
+func (t *Tilesets) TilesetStatus(tilesetID string) (*Status, error) {
+	v := url.Values{}
+
+	resp := Status{}
+
+	queryString := fmt.Sprintf("%s/%s/%s/status", apiName, apiVersion, url.PathEscape(tilesetID))
+
+	err := t.base.QueryBase(queryString, &v, &resp)
+	if err != nil {
+		return nil, base.WrapScopeError(err, scopeRead)
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,156 @@
+/**
+ * go-mapbox Tilesets Module Tests
+ * Wraps the mapbox tilesets API for server side use
+ * See https://docs.mapbox.com/api/maps/mapbox-tiling-service/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package tilesets
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/internal/basetest"
+)
+
+const tilesetsPage1 = `[{
+	"type": "vector",
+	"center": [-0.2680000000000007, 11.7014165, 2],
+	"created": "2023-03-15T18:54:57.014Z",
+	"description": "",
+	"filesize": 17879790,
+	"id": "example.cij1zoclm06w7w9m0y7nvz7kr",
+	"modified": "2023-03-15T18:54:57.014Z",
+	"name": "populated_places",
+	"visibility": "private",
+	"status": "available"
+}, {
+	"type": "raster",
+	"center": [-110.32, 44.6, 8],
+	"created": "2023-04-01T10:00:00.000Z",
+	"description": "Hillshade",
+	"filesize": 2048,
+	"id": "example.hillshade",
+	"modified": "2023-04-02T10:00:00.000Z",
+	"name": "hillshade",
+	"visibility": "public",
+	"status": "pending"
+}]`
+
+func TestTilesets(t *testing.T) {
+
+	t.Run("Lists tilesets", func(t *testing.T) {
+		ts := NewTilesets(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.EqualValues(t, "/tilesets/v1/example", r.URL.Path)
+			assert.EqualValues(t, "vector", r.URL.Query().Get("type"))
+			assert.EqualValues(t, "private", r.URL.Query().Get("visibility"))
+			assert.EqualValues(t, "modified", r.URL.Query().Get("sortby"))
+			assert.EqualValues(t, "2", r.URL.Query().Get("limit"))
+			w.Write([]byte(tilesetsPage1))
+		}))
+
+		page, err := ts.ListTilesets("example", &ListOpts{Type: TypeVector, Visibility: VisibilityPrivate, SortBy: SortByModified, Limit: 2})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.Len(t, page.Tilesets, 2)
+		assert.EqualValues(t, "", page.Next)
+
+		assert.EqualValues(t, Tileset{
+			Type:       TypeVector,
+			ID:         "example.cij1zoclm06w7w9m0y7nvz7kr",
+			Name:       "populated_places",
+			Center:     []float64{-0.2680000000000007, 11.7014165, 2},
+			Created:    "2023-03-15T18:54:57.014Z",
+			Modified:   "2023-03-15T18:54:57.014Z",
+			Visibility: VisibilityPrivate,
+			Filesize:   17879790,
+			Status:     "available",
+		}, page.Tilesets[0])
+		assert.EqualValues(t, TypeRaster, page.Tilesets[1].Type)
+	})
+
+	t.Run("Follows pagination cursors", func(t *testing.T) {
+		ts := NewTilesets(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.EqualValues(t, "1", r.URL.Query().Get("limit"))
+			switch r.URL.Query().Get("start") {
+			case "":
+				w.Header().Set("Link", `<https://api.mapbox.com/tilesets/v1/example?limit=1&start=cursor-2>; rel="next"`)
+				w.Write([]byte(`[{"id":"example.one","type":"vector"}]`))
+			case "cursor-2":
+				w.Write([]byte(`[{"id":"example.two","type":"vector"}]`))
+			default:
+				t.Errorf("Unexpected cursor %s", r.URL.Query().Get("start"))
+			}
+		}))
+
+		ids := make([]string, 0)
+		opts := ListOpts{Limit: 1}
+		for {
+			page, err := ts.ListTilesets("example", &opts)
+			if !assert.Nil(t, err) {
+				t.FailNow()
+			}
+			for _, tileset := range page.Tilesets {
+				ids = append(ids, tileset.ID)
+			}
+			if page.Next == "" {
+				break
+			}
+			opts.Start = page.Next
+		}
+
+		assert.EqualValues(t, []string{"example.one", "example.two"}, ids)
+	})
+
+	t.Run("Validates limits", func(t *testing.T) {
+		ts := NewTilesets(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("Unexpected request")
+		}))
+
+		_, err := ts.ListTilesets("example", &ListOpts{Limit: MaxLimit + 1})
+		validationErr := &base.ValidationError{}
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.EqualValues(t, "limit", validationErr.Field)
+		}
+	})
+
+	t.Run("Fetches tileset status", func(t *testing.T) {
+		ts := NewTilesets(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.EqualValues(t, "/tilesets/v1/example.hello-world/status", r.URL.Path)
+			w.Write([]byte(`{"id":"example.hello-world","latest_job":"ckaycp4e4000008l99k0m6lvq","status":"processing"}`))
+		}))
+
+		status, err := ts.TilesetStatus("example.hello-world")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, Status{ID: "example.hello-world", LatestJob: "ckaycp4e4000008l99k0m6lvq", Status: "processing"}, *status)
+	})
+
+	t.Run("Returns scope errors", func(t *testing.T) {
+		ts := NewTilesets(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"Forbidden"}`))
+		}))
+
+		_, err := ts.ListTilesets("example", nil)
+		scopeErr := &ScopeError{}
+		if assert.True(t, errors.As(err, &scopeErr)) {
+			assert.EqualValues(t, "tilesets:list", scopeErr.Scope)
+			assert.EqualValues(t, http.StatusForbidden, scopeErr.StatusCode)
+		}
+
+		_, err = ts.TilesetStatus("example.hello-world")
+		if assert.True(t, errors.As(err, &scopeErr)) {
+			assert.EqualValues(t, "tilesets:read", scopeErr.Scope)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+/**
+ * go-mapbox Tilesets Module Types
+ * Wraps the mapbox tilesets API for server side use
+ * See https://docs.mapbox.com/api/maps/mapbox-tiling-service/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package tilesets
+
+import (
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+// MaxLimit is the maximum number of tilesets returned by a single listing
+const MaxLimit = 500
+
+// TilesetType is the type of data in a tileset
+type TilesetType string
+
+const (
+	TypeVector TilesetType = "vector"
+	TypeRaster TilesetType = "raster"
+)
+
+// Visibility is the visibility of a tileset
+type Visibility string
+
+const (
+	VisibilityPublic  Visibility = "public"
+	VisibilityPrivate Visibility = "private"
+)
+
+// SortBy sort order for tileset listings
+type SortBy string
+
+const (
+	// SortByCreated sorts tilesets by creation time
+	SortByCreated SortBy = "created"
+	// SortByModified sorts tilesets by modification time
+	SortByModified SortBy = "modified"
+)
+
+// Tileset is the metadata describing a tileset
+// https://docs.mapbox.com/api/maps/mapbox-tiling-service/#the-tileset-object
+type Tileset struct {
+	Type        TilesetType `json:"type"`
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	// Center is the default view of the tileset in the form [lng, lat, zoom]
+	Center     []float64  `json:"center"`
+	Created    string     `json:"created"`
+	Modified   string     `json:"modified"`
+	Visibility Visibility `json:"visibility"`
+	// Filesize is the storage size of the tileset in bytes
+	Filesize int64 `json:"filesize"`
+	// Status is the processing status of the tileset (for example available or pending)
+	Status string `json:"status"`
+}
+
+// TilesetPage is a single page of tilesets
+type TilesetPage struct {
+	Tilesets []Tileset
+	// Next is the cursor for the following page, empty where there are no more tilesets
+	Next string
+}
+
+// Status is the publishing status of a tileset
+type Status struct {
+	ID string `json:"id"`
+	// LatestJob is the ID of the most recent publish job
+	LatestJob string `json:"latest_job"`
+	// Status is the status of the most recent publish job (processing, queued, success or failed)
+	Status string `json:"status"`
+}
+
+// ScopeError is returned when the API rejects a request as unauthorized
+type ScopeError = base.ScopeError
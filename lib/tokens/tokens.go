@@ -0,0 +1,266 @@
+/**
+ * go-mapbox Tokens Module
+ * Wraps the mapbox tokens API for server side use
+ * See https://docs.mapbox.com/api/accounts/tokens/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package tokens
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const (
+	apiName    = "tokens"
+	apiVersion = "v2"
+
+	scopeRead  = "tokens:read"
+	scopeWrite = "tokens:write"
+
+	// MaxTemporaryTokenLifetime is the maximum time until a temporary token expires
+	MaxTemporaryTokenLifetime = time.Hour
+)
+
+// Tokens api wrapper instance
+type Tokens struct {
+	base *base.Base
+}
+
+// NewTokens Create a new Tokens API wrapper
+func NewTokens(base *base.Base) *Tokens {
+	return &Tokens{base}
+}
+
+// CreateTokenOpts contains the properties of a token to be created
+type CreateTokenOpts struct {
+	// Scopes granted to the token, public tokens may only contain public scopes
+	Scopes []string `json:"scopes"`
+	// Note is a human readable description of the token
+	Note string `json:"note,omitempty"`
+	// AllowedURLs restricts the URLs from which the token may be used
+	AllowedURLs []string `json:"allowedUrls,omitempty"`
+}
+
+// TokenOpts contains the properties of a token to be created for the token owner
+type TokenOpts struct {
+	// Name is a human readable description of the token, sent as the token note
+	Name string
+	// Scopes granted to the token
+	Scopes []string
+	// AllowedURLs restricts the URLs from which the token may be used (not supported for temporary tokens)
+	AllowedURLs []string
+	// Expires creates a temporary token expiring at the provided time, within MaxTemporaryTokenLifetime
+	Expires time.Time
+}
+
+// temporaryTokenOpts is the request body for creating a temporary token
+type temporaryTokenOpts struct {
+	Scopes  []string  `json:"scopes"`
+	Expires time.Time `json:"expires"`
+}
+
+// validate checks the token options, including the expiry of temporary tokens
+func (o *TokenOpts) validate(now time.Time) error {
+	if len(o.Scopes) == 0 {
+		return &base.ValidationError{Field: "scopes", Message: "at least one scope is required"}
+	}
+	if o.Expires.IsZero() {
+		return nil
+	}
+	if len(o.AllowedURLs) > 0 {
+		return &base.ValidationError{Field: "allowedUrls", Message: "not supported for temporary tokens"}
+	}
+	if !o.Expires.After(now) {
+		return &base.ValidationError{Field: "expires", Message: "must be in the future"}
+	}
+	if o.Expires.Sub(now) > MaxTemporaryTokenLifetime {
+		return &base.ValidationError{Field: "expires", Message: fmt.Sprintf("must be within %s", MaxTemporaryTokenLifetime)}
+	}
+	return nil
+}
+
+// CreateToken creates a token for the owner of the API token, which must be a secret token with the tokens:write scope
+// A temporary token is created where Expires is set
+func (t *Tokens) CreateToken(opts *TokenOpts) (*TokenMeta, error) {
+	if opts == nil {
+		opts = &TokenOpts{}
+	}
+
+	err := opts.validate(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	username, err := t.base.Username()
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Expires.IsZero() {
+		token, err := t.Create(context.Background(), username, &CreateTokenOpts{
+			Scopes:      opts.Scopes,
+			Note:        opts.Name,
+			AllowedURLs: opts.AllowedURLs,
+		})
+		return token, base.WrapScopeError(err, scopeWrite)
+	}
+
+	v := url.Values{}
+
+	resp := TokenMeta{}
+
+	queryString := fmt.Sprintf("%s/%s/%s/temporary", apiName, apiVersion, username)
+
+	body := temporaryTokenOpts{Scopes: opts.Scopes, Expires: opts.Expires.UTC()}
+
+	err = t.base.QueryWithBodyBase(context.Background(), http.MethodPost, queryString, &v, &body, &resp)
+	if err != nil {
+		return nil, base.WrapScopeError(err, scopeWrite)
+	}
+
+	return &resp, nil
+}
+
+// ListTokens lists the tokens belonging to the owner of the API token, which must have the tokens:read scope
+func (t *Tokens) ListTokens() ([]TokenMeta, error) {
+	username, err := t.base.Username()
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := t.List(context.Background(), username)
+	if err != nil {
+		return nil, base.WrapScopeError(err, scopeRead)
+	}
+
+	return tokens, nil
+}
+
+// DeleteToken revokes a token belonging to the owner of the API token, which must have the tokens:write scope
+func (t *Tokens) DeleteToken(tokenID string) error {
+	username, err := t.base.Username()
+	if err != nil {
+		return err
+	}
+
+	return base.WrapScopeError(t.Delete(context.Background(), username, tokenID), scopeWrite)
+}
+
+// List the tokens belonging to a user
+func (t *Tokens) List(ctx context.Context, username string) ([]TokenMeta, error) {
+	v := url.Values{}
+
+	resp := make([]TokenMeta, 0)
+
+	queryString := fmt.Sprintf("%s/%s/%s", apiName, apiVersion, username)
+
+	err := t.base.QueryBaseContext(ctx, queryString, &v, &resp)
+
+	return resp, err
+}
+
+// Create a new scoped token
+func (t *Tokens) Create(ctx context.Context, username string, opts *CreateTokenOpts) (*TokenMeta, error) {
+	if opts == nil || len(opts.Scopes) == 0 {
+		return nil, errors.New("Tokens must be created with at least one scope")
+	}
+
+	v := url.Values{}
+
+	resp := TokenMeta{}
+
+	queryString := fmt.Sprintf("%s/%s/%s", apiName, apiVersion, username)
+
+	err := t.base.QueryWithBodyBase(ctx, http.MethodPost, queryString, &v, opts, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Rotate replaces a token with a new token with the same scopes, note and allowed URLs
+// The API does not support regenerating a token in place, so a replacement is created
+// before the original is deleted, and the replacement (with a new ID) is returned
+func (t *Tokens) Rotate(ctx context.Context, username, tokenID string) (*TokenMeta, error) {
+	tokens, err := t.List(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	var current *TokenMeta
+	for i := range tokens {
+		if tokens[i].ID == tokenID {
+			current = &tokens[i]
+			break
+		}
+	}
+	if current == nil {
+		return nil, fmt.Errorf("Token %s not found", tokenID)
+	}
+
+	replacement, err := t.Create(ctx, username, &CreateTokenOpts{
+		Scopes:      current.Scopes,
+		Note:        current.Note,
+		AllowedURLs: current.AllowedURLs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.Delete(ctx, username, tokenID)
+	if err != nil {
+		return replacement, err
+	}
+
+	return replacement, nil
+}
+
+// Delete revokes a token
+func (t *Tokens) Delete(ctx context.Context, username, tokenID string) error {
+	v := url.Values{}
+
+	queryString := fmt.Sprintf("%s/%s/%s/%s", apiName, apiVersion, username, tokenID)
+
+	return t.base.QueryWithBodyBase(ctx, http.MethodDelete, queryString, &v, nil, nil)
+}
+
+// Validate introspects the provided token, authenticating with the token itself
+// Tokens the API rejects are returned with the reason in Code rather than as an error
+func (t *Tokens) Validate(ctx context.Context, token string) (*TokenValidation, error) {
+	b, err := t.base.CloneWithToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	v := url.Values{}
+
+	resp := TokenValidation{}
+
+	queryString := fmt.Sprintf("%s/%s", apiName, apiVersion)
+
+	err = b.QueryBaseContext(ctx, queryString, &v, &resp)
+
+	apiErr := &base.APIError{}
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+		if json.Unmarshal(apiErr.Body, &resp) == nil && resp.Code != "" {
+			return &resp, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
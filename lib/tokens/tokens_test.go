@@ -0,0 +1,232 @@
+/**
+ * go-mapbox Tokens Module Tests
+ * Wraps the mapbox tokens API for server side use
+ * See https://docs.mapbox.com/api/accounts/tokens/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package tokens
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+	"github.com/ryankurte/go-mapbox/lib/internal/basetest"
+)
+
+const tokenResponse = `{
+	"id": "cjtoken",
+	"client": "api",
+	"default": false,
+	"usage": "pk",
+	"note": "Website",
+	"scopes": ["styles:read", "fonts:read"],
+	"allowedUrls": ["https://example.com"],
+	"created": "2023-01-01T00:00:00.000Z",
+	"modified": "2023-01-02T00:00:00.000Z",
+	"token": "pk.secret"
+}`
+
+func TestTokens(t *testing.T) {
+
+	t.Run("Can list and create tokens", func(t *testing.T) {
+		tk := NewTokens(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.EqualValues(t, "/tokens/v2/example", r.URL.Path)
+
+			switch r.Method {
+			case http.MethodGet:
+				w.Write([]byte("[" + tokenResponse + "]"))
+			case http.MethodPost:
+				opts := CreateTokenOpts{}
+				assert.Nil(t, json.NewDecoder(r.Body).Decode(&opts))
+				assert.EqualValues(t, []string{"styles:read", "fonts:read"}, opts.Scopes)
+				assert.EqualValues(t, []string{"https://example.com"}, opts.AllowedURLs)
+				w.Write([]byte(tokenResponse))
+			}
+		}))
+
+		list, err := tk.List(context.Background(), "example")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.Len(t, list, 1)
+		assert.EqualValues(t, "Website", list[0].Note)
+
+		token, err := tk.Create(context.Background(), "example", &CreateTokenOpts{
+			Scopes:      []string{"styles:read", "fonts:read"},
+			Note:        "Website",
+			AllowedURLs: []string{"https://example.com"},
+		})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "pk.secret", token.Token)
+
+		_, err = tk.Create(context.Background(), "example", &CreateTokenOpts{})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Can rotate tokens", func(t *testing.T) {
+		requests := []string{}
+		tk := NewTokens(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+
+			switch r.Method {
+			case http.MethodGet:
+				w.Write([]byte("[" + tokenResponse + "]"))
+			case http.MethodPost:
+				opts := CreateTokenOpts{}
+				assert.Nil(t, json.NewDecoder(r.Body).Decode(&opts))
+				assert.EqualValues(t, "Website", opts.Note)
+				w.Write([]byte(`{"id":"cjreplacement","scopes":["styles:read","fonts:read"],"token":"pk.replacement"}`))
+			case http.MethodDelete:
+				w.WriteHeader(http.StatusNoContent)
+			}
+		}))
+
+		token, err := tk.Rotate(context.Background(), "example", "cjtoken")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "cjreplacement", token.ID)
+		assert.EqualValues(t, []string{
+			"GET /tokens/v2/example",
+			"POST /tokens/v2/example",
+			"DELETE /tokens/v2/example/cjtoken",
+		}, requests)
+
+		_, err = tk.Rotate(context.Background(), "example", "missing")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Can validate tokens", func(t *testing.T) {
+		tk := NewTokens(basetest.NewBase(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.EqualValues(t, "/tokens/v2", r.URL.Path)
+
+			switch r.URL.Query().Get("access_token") {
+			case "pk.valid":
+				w.Write([]byte(`{"code":"TokenValid","token":{"usage":"pk","user":"example","authorization":"cjtoken","client":"api","scopes":["styles:read"]}}`))
+			default:
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"code":"TokenRevoked"}`))
+			}
+		}))
+
+		validation, err := tk.Validate(context.Background(), "pk.valid")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.True(t, validation.Valid())
+		assert.EqualValues(t, "example", validation.Username)
+		assert.EqualValues(t, []string{"styles:read"}, validation.Scopes)
+
+		validation, err = tk.Validate(context.Background(), "pk.revoked")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.False(t, validation.Valid())
+		assert.EqualValues(t, TokenRevoked, validation.Code)
+	})
+
+	t.Run("Can create, list and delete tokens for the token owner", func(t *testing.T) {
+		expires := time.Now().Add(30 * time.Minute).UTC().Truncate(time.Second)
+
+		tk := NewTokens(basetest.NewBaseWithToken(t, "sk.eyJ1IjoiZXhhbXBsZSIsImEiOiJjamFiYyJ9.signature", func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /tokens/v2/example":
+				w.Write([]byte("[" + tokenResponse + "]"))
+			case "POST /tokens/v2/example":
+				body, _ := ioutil.ReadAll(r.Body)
+				assert.JSONEq(t, `{"scopes":["styles:read"],"note":"Customer 42","allowedUrls":["https://customer42.example.com"]}`, string(body))
+				w.Write([]byte(tokenResponse))
+			case "POST /tokens/v2/example/temporary":
+				body, _ := ioutil.ReadAll(r.Body)
+				assert.JSONEq(t, fmt.Sprintf(`{"scopes":["styles:tiles"],"expires":"%s"}`, expires.Format(time.RFC3339)), string(body))
+				w.Write([]byte(`{"token":"tk.temporary"}`))
+			case "DELETE /tokens/v2/example/cjtoken":
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			}
+		}))
+
+		token, err := tk.CreateToken(&TokenOpts{
+			Name:        "Customer 42",
+			Scopes:      []string{"styles:read"},
+			AllowedURLs: []string{"https://customer42.example.com"},
+		})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "cjtoken", token.ID)
+
+		temporary, err := tk.CreateToken(&TokenOpts{Scopes: []string{"styles:tiles"}, Expires: expires})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.EqualValues(t, "tk.temporary", temporary.Token)
+
+		list, err := tk.ListTokens()
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.Len(t, list, 1)
+		assert.EqualValues(t, []string{"styles:read", "fonts:read"}, list[0].Scopes)
+		assert.EqualValues(t, "2023-01-01T00:00:00.000Z", list[0].Created)
+
+		assert.Nil(t, tk.DeleteToken("cjtoken"))
+	})
+
+	t.Run("Validates token options", func(t *testing.T) {
+		tk := NewTokens(basetest.NewBaseWithToken(t, "sk.eyJ1IjoiZXhhbXBsZSIsImEiOiJjamFiYyJ9.signature", func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}))
+
+		tests := []struct {
+			opts  TokenOpts
+			field string
+		}{
+			{TokenOpts{}, "scopes"},
+			{TokenOpts{Scopes: []string{"styles:tiles"}, Expires: time.Now().Add(-time.Minute)}, "expires"},
+			{TokenOpts{Scopes: []string{"styles:tiles"}, Expires: time.Now().Add(2 * time.Hour)}, "expires"},
+			{TokenOpts{Scopes: []string{"styles:tiles"}, Expires: time.Now().Add(time.Minute), AllowedURLs: []string{"https://example.com"}}, "allowedUrls"},
+		}
+
+		for _, tt := range tests {
+			_, err := tk.CreateToken(&tt.opts)
+			validationErr := &base.ValidationError{}
+			if assert.True(t, errors.As(err, &validationErr)) {
+				assert.EqualValues(t, tt.field, validationErr.Field)
+			}
+		}
+	})
+
+	t.Run("Returns scope errors", func(t *testing.T) {
+		tk := NewTokens(basetest.NewBaseWithToken(t, "sk.eyJ1IjoiZXhhbXBsZSIsImEiOiJjamFiYyJ9.signature", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"Forbidden"}`))
+		}))
+
+		_, err := tk.CreateToken(&TokenOpts{Scopes: []string{"styles:read"}})
+		scopeErr := &base.ScopeError{}
+		if assert.True(t, errors.As(err, &scopeErr)) {
+			assert.EqualValues(t, "tokens:write", scopeErr.Scope)
+		}
+
+		_, err = tk.ListTokens()
+		if assert.True(t, errors.As(err, &scopeErr)) {
+			assert.EqualValues(t, "tokens:read", scopeErr.Scope)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+/**
+ * go-mapbox Tokens Module Types
+ * Wraps the mapbox tokens API for server side use
+ * See https://docs.mapbox.com/api/accounts/tokens/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package tokens
+
+import (
+	"encoding/json"
+)
+
+// TokenMeta describes a token
+// https://docs.mapbox.com/api/accounts/tokens/#the-token-object
+type TokenMeta struct {
+	ID          string   `json:"id"`
+	Client      string   `json:"client"`
+	Default     bool     `json:"default"`
+	Usage       string   `json:"usage"`
+	Note        string   `json:"note"`
+	Scopes      []string `json:"scopes"`
+	AllowedURLs []string `json:"allowedUrls"`
+	Created     string   `json:"created"`
+	Modified    string   `json:"modified"`
+	// Token is the token value itself
+	Token string `json:"token"`
+}
+
+// Codes describe the result of token validation
+type Codes string
+
+const (
+	// TokenValid the token is valid and active
+	TokenValid Codes = "TokenValid"
+	// TokenMalformed the token cannot be parsed
+	TokenMalformed Codes = "TokenMalformed"
+	// TokenInvalid the token signature is not valid
+	TokenInvalid Codes = "TokenInvalid"
+	// TokenExpired the temporary token has expired
+	TokenExpired Codes = "TokenExpired"
+	// TokenRevoked the token has been revoked
+	TokenRevoked Codes = "TokenRevoked"
+)
+
+// TokenValidation is the result of introspecting a token
+type TokenValidation struct {
+	Code          Codes
+	Username      string
+	Usage         string
+	Authorization string
+	Client        string
+	Scopes        []string
+	Expires       string
+}
+
+// Valid indicates whether the token is valid and active
+func (v *TokenValidation) Valid() bool {
+	return v.Code == TokenValid
+}
+
+// UnmarshalJSON decodes the nested token object returned by the API
+func (v *TokenValidation) UnmarshalJSON(data []byte) error {
+	raw := struct {
+		Code  Codes `json:"code"`
+		Token struct {
+			Usage         string   `json:"usage"`
+			User          string   `json:"user"`
+			Authorization string   `json:"authorization"`
+			Client        string   `json:"client"`
+			Scopes        []string `json:"scopes"`
+			Expires       string   `json:"expires"`
+		} `json:"token"`
+	}{}
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	*v = TokenValidation{
+		Code:          raw.Code,
+		Username:      raw.Token.User,
+		Usage:         raw.Token.Usage,
+		Authorization: raw.Token.Authorization,
+		Client:        raw.Token.Client,
+		Scopes:        raw.Token.Scopes,
+		Expires:       raw.Token.Expires,
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+/**
+ * go-mapbox Uploads Module S3 Staging
+ * Stages files in the Mapbox S3 bucket using temporary credentials
+ * See https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-authenticating-requests.html for signing information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package uploads
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// s3Region is the region of the Mapbox staging bucket
+	s3Region = "us-east-1"
+	// s3UnsignedPayload skips payload hashing, which S3 permits for signed requests
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// s3PartSize is the size of parts used for multipart uploads, files no larger than this are sent in a single request
+var s3PartSize int64 = 64 << 20
+
+// stageFile uploads the contents of r (of the provided size) to the staging location described by creds
+func stageFile(ctx context.Context, creds *S3Credentials, r io.ReaderAt, size int64) error {
+	u, err := url.Parse(creds.URL)
+	if err != nil {
+		return err
+	}
+
+	if size <= s3PartSize {
+		_, err = s3Request(ctx, creds, http.MethodPut, u, nil, io.NewSectionReader(r, 0, size), size)
+		return err
+	}
+
+	return stageMultipart(ctx, creds, u, r, size)
+}
+
+// stageMultipart uploads the contents of r in s3PartSize parts
+func stageMultipart(ctx context.Context, creds *S3Credentials, u *url.URL, r io.ReaderAt, size int64) error {
+	resp, err := s3Request(ctx, creds, http.MethodPost, u, url.Values{"uploads": {""}}, nil, 0)
+	if err != nil {
+		return err
+	}
+
+	initiate := struct {
+		UploadID string `xml:"UploadId"`
+	}{}
+	err = xml.Unmarshal(resp.body, &initiate)
+	if err != nil {
+		return err
+	}
+
+	type part struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	}
+	complete := struct {
+		XMLName xml.Name `xml:"CompleteMultipartUpload"`
+		Parts   []part   `xml:"Part"`
+	}{}
+
+	for offset, n := int64(0), 1; offset < size; offset, n = offset+s3PartSize, n+1 {
+		length := s3PartSize
+		if offset+length > size {
+			length = size - offset
+		}
+
+		q := url.Values{"partNumber": {strconv.Itoa(n)}, "uploadId": {initiate.UploadID}}
+		resp, err := s3Request(ctx, creds, http.MethodPut, u, q, io.NewSectionReader(r, offset, length), length)
+		if err != nil {
+			s3Request(ctx, creds, http.MethodDelete, u, url.Values{"uploadId": {initiate.UploadID}}, nil, 0)
+			return err
+		}
+
+		complete.Parts = append(complete.Parts, part{PartNumber: n, ETag: resp.header.Get("ETag")})
+	}
+
+	body, err := xml.Marshal(&complete)
+	if err != nil {
+		return err
+	}
+
+	q := url.Values{"uploadId": {initiate.UploadID}}
+	_, err = s3Request(ctx, creds, http.MethodPost, u, q, bytes.NewReader(body), int64(len(body)))
+
+	return err
+}
+
+// s3Response is the header and body of a successful S3 response
+type s3Response struct {
+	header http.Header
+	body   []byte
+}
+
+// s3Request issues a request signed (AWS signature version 4) with the provided credentials
+func s3Request(ctx context.Context, creds *S3Credentials, method string, u *url.URL, q url.Values, body io.Reader, length int64) (*s3Response, error) {
+	target := *u
+	target.RawQuery = strings.Replace(q.Encode(), "+", "%20", -1)
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = length
+
+	signS3Request(req, creds, time.Now().UTC())
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("S3 staging error %d (%s)", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	return &s3Response{header: resp.Header, body: data}, nil
+}
+
+// signS3Request adds the AWS signature version 4 authorization headers to a request
+func signS3Request(req *http.Request, creds *S3Credentials, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, s3Region)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(req.Header.Get(k))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	canonicalHeaders := ""
+	for _, k := range names {
+		canonicalHeaders += k + ":" + headers[k] + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		s3UnsignedPayload,
+	}, "\n")
+
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hex.EncodeToString(hash[:])}, "\n")
+
+	key := []byte("AWS4" + creds.SecretAccessKey)
+	for _, s := range []string{date, s3Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, s)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
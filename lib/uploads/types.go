@@ -0,0 +1,59 @@
+/**
+ * go-mapbox Uploads Module Types
+ * Wraps the mapbox uploads API for server side use
+ * See https://docs.mapbox.com/api/maps/uploads/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package uploads
+
+// S3Credentials are temporary credentials for staging a file in the Mapbox S3 bucket
+// https://docs.mapbox.com/api/maps/uploads/#retrieve-s3-credentials
+type S3Credentials struct {
+	Bucket          string `json:"bucket"`
+	Key             string `json:"key"`
+	AccessKeyID     string `json:"accessKeyId"`
+	SecretAccessKey string `json:"secretAccessKey"`
+	SessionToken    string `json:"sessionToken"`
+	URL             string `json:"url"`
+}
+
+// UploadStatus is the processing state of an upload
+type UploadStatus string
+
+const (
+	// StatusProcessing the upload is being processed
+	StatusProcessing UploadStatus = "processing"
+	// StatusComplete the upload has been processed into a tileset
+	StatusComplete UploadStatus = "complete"
+	// StatusFailed the upload could not be processed, see Upload.Error
+	StatusFailed UploadStatus = "failed"
+)
+
+// Upload describes an upload job
+// https://docs.mapbox.com/api/maps/uploads/#the-upload-object
+type Upload struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Tileset  string  `json:"tileset"`
+	Owner    string  `json:"owner"`
+	Complete bool    `json:"complete"`
+	Error    *string `json:"error"`
+	Progress float64 `json:"progress"`
+	Created  string  `json:"created"`
+	Modified string  `json:"modified"`
+}
+
+// Status returns the processing state of the upload
+func (u *Upload) Status() UploadStatus {
+	switch {
+	case u.Error != nil:
+		return StatusFailed
+	case u.Complete:
+		return StatusComplete
+	default:
+		return StatusProcessing
+	}
+}
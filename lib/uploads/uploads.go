@@ -0,0 +1,186 @@
+/**
+ * go-mapbox Uploads Module
+ * Wraps the mapbox uploads API for server side use
+ * See https://docs.mapbox.com/api/maps/uploads/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package uploads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/ryankurte/go-mapbox/lib/base"
+)
+
+const (
+	apiName    = "uploads"
+	apiVersion = "v1"
+)
+
+// pollInterval is the period between status checks when waiting for an upload to be processed
+var pollInterval = 5 * time.Second
+
+// Uploads api wrapper instance
+type Uploads struct {
+	base *base.Base
+}
+
+// NewUploads Create a new Uploads API wrapper
+func NewUploads(base *base.Base) *Uploads {
+	return &Uploads{base}
+}
+
+// UploadOpts request options for creating an upload
+type UploadOpts struct {
+	// Tileset is the ID of the tileset to create or replace, in the form username.tileset_name
+	Tileset string `json:"tileset"`
+	// URL is the location of the staged file, this is set automatically by UploadFile
+	URL string `json:"url"`
+	// Name is the name of the tileset, defaults to the file name
+	Name string `json:"name,omitempty"`
+}
+
+// CreateCredentials requests temporary credentials for staging a file to be uploaded
+func (u *Uploads) CreateCredentials(ctx context.Context, username string) (*S3Credentials, error) {
+	v := url.Values{}
+
+	resp := S3Credentials{}
+
+	queryString := fmt.Sprintf("%s/%s/%s/credentials", apiName, apiVersion, username)
+
+	err := u.base.QueryWithBodyBase(ctx, http.MethodPost, queryString, &v, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Create an upload job from a file previously staged using CreateCredentials
+func (u *Uploads) Create(ctx context.Context, username string, opts *UploadOpts) (*Upload, error) {
+	if opts == nil || opts.Tileset == "" || opts.URL == "" {
+		return nil, errors.New("Uploads require a tileset and staged file URL")
+	}
+
+	v := url.Values{}
+
+	resp := Upload{}
+
+	queryString := fmt.Sprintf("%s/%s/%s", apiName, apiVersion, username)
+
+	err := u.base.QueryWithBodyBase(ctx, http.MethodPost, queryString, &v, opts, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Get the status of an upload job
+func (u *Uploads) Get(ctx context.Context, username, uploadID string) (*Upload, error) {
+	v := url.Values{}
+
+	resp := Upload{}
+
+	queryString := fmt.Sprintf("%s/%s/%s/%s", apiName, apiVersion, username, uploadID)
+
+	err := u.base.QueryBaseContext(ctx, queryString, &v, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// List the recent upload jobs of a user
+func (u *Uploads) List(ctx context.Context, username string) ([]Upload, error) {
+	v := url.Values{}
+
+	resp := make([]Upload, 0)
+
+	queryString := fmt.Sprintf("%s/%s/%s", apiName, apiVersion, username)
+
+	err := u.base.QueryBaseContext(ctx, queryString, &v, &resp)
+
+	return resp, err
+}
+
+// Delete a completed or failed upload job from the upload listing
+func (u *Uploads) Delete(ctx context.Context, username, uploadID string) error {
+	v := url.Values{}
+
+	queryString := fmt.Sprintf("%s/%s/%s/%s", apiName, apiVersion, username, uploadID)
+
+	return u.base.QueryWithBodyBase(ctx, http.MethodDelete, queryString, &v, nil, nil)
+}
+
+// UploadFile stages a local file, creates an upload job from it, and waits for processing to finish
+// The returned upload is complete, or failed in which case an error is also returned
+func (u *Uploads) UploadFile(ctx context.Context, username, filepath string, opts *UploadOpts) (*Upload, error) {
+	if opts == nil || opts.Tileset == "" {
+		return nil, errors.New("Uploads require a tileset")
+	}
+
+	f, err := os.Open(filepath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := u.CreateCredentials(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	err = stageFile(ctx, creds, f, info.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	o := *opts
+	o.URL = creds.URL
+	if o.Name == "" {
+		o.Name = info.Name()
+	}
+
+	upload, err := u.Create(ctx, username, &o)
+	if err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for upload.Status() == StatusProcessing {
+		select {
+		case <-ctx.Done():
+			return upload, ctx.Err()
+		case <-ticker.C:
+		}
+
+		upload, err = u.Get(ctx, username, upload.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if upload.Status() == StatusFailed {
+		return upload, fmt.Errorf("Upload %s failed (%s)", upload.ID, *upload.Error)
+	}
+
+	return upload, nil
+}
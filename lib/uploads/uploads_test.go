@@ -0,0 +1,175 @@
+/**
+ * go-mapbox Uploads Module Tests
+ * Wraps the mapbox uploads API for server side use
+ * See https://docs.mapbox.com/api/maps/uploads/ for API information
+ *
+ * https://github.com/ryankurte/go-mapbox
+ * Copyright 2017 Ryan Kurte
+ */
+
+package uploads
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ryankurte/go-mapbox/lib/internal/basetest"
+)
+
+// testServer emulates both the uploads API and the S3 staging bucket
+type testServer struct {
+	t   *testing.T
+	url string
+
+	mu      sync.Mutex
+	staged  map[int]string
+	polls   int
+	created UploadOpts
+}
+
+func newTestServer(t *testing.T) *testServer {
+	return &testServer{t: t, staged: make(map[int]string)}
+}
+
+func (s *testServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.url = "http://" + r.Host
+
+	if strings.HasPrefix(r.URL.Path, "/bucket/") {
+		s.handleS3(w, r)
+		return
+	}
+
+	switch r.Method + " " + r.URL.Path {
+	case "POST /uploads/v1/example/credentials":
+		json.NewEncoder(w).Encode(&S3Credentials{
+			Bucket:          "bucket",
+			Key:             "staged/file",
+			AccessKeyID:     "AKID",
+			SecretAccessKey: "secret",
+			SessionToken:    "session",
+			URL:             s.url + "/bucket/staged/file",
+		})
+	case "POST /uploads/v1/example":
+		assert.Nil(s.t, json.NewDecoder(r.Body).Decode(&s.created))
+		w.Write([]byte(`{"id":"cjupload","tileset":"example.trails","complete":false,"error":null,"progress":0}`))
+	case "GET /uploads/v1/example/cjupload":
+		s.polls++
+		if s.polls < 2 {
+			w.Write([]byte(`{"id":"cjupload","tileset":"example.trails","complete":false,"error":null,"progress":0.5}`))
+		} else {
+			w.Write([]byte(`{"id":"cjupload","tileset":"example.trails","complete":true,"error":null,"progress":1}`))
+		}
+	case "GET /uploads/v1/example":
+		w.Write([]byte(`[{"id":"cjupload","complete":false,"error":"Invalid file"}]`))
+	case "DELETE /uploads/v1/example/cjupload":
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s.t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		http.NotFound(w, r)
+	}
+}
+
+func (s *testServer) handleS3(w http.ResponseWriter, r *http.Request) {
+	assert.True(s.t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"))
+	assert.EqualValues(s.t, "session", r.Header.Get("X-Amz-Security-Token"))
+
+	q := r.URL.Query()
+	body, _ := ioutil.ReadAll(r.Body)
+
+	switch {
+	case r.Method == http.MethodPut && q.Get("uploadId") == "":
+		s.staged[1] = string(body)
+	case r.Method == http.MethodPost && q.Get("uploadId") == "":
+		w.Write([]byte(`<InitiateMultipartUploadResult><UploadId>multipart</UploadId></InitiateMultipartUploadResult>`))
+	case r.Method == http.MethodPut:
+		n := 0
+		fmt.Sscanf(q.Get("partNumber"), "%d", &n)
+		s.staged[n] = string(body)
+		w.Header().Set("ETag", fmt.Sprintf(`"etag-%d"`, n))
+	case r.Method == http.MethodPost:
+		complete := struct {
+			Parts []struct {
+				PartNumber int
+				ETag       string
+			} `xml:"Part"`
+		}{}
+		assert.Nil(s.t, xml.Unmarshal(body, &complete))
+		for i, p := range complete.Parts {
+			assert.EqualValues(s.t, i+1, p.PartNumber)
+			assert.EqualValues(s.t, fmt.Sprintf(`"etag-%d"`, i+1), p.ETag)
+		}
+	}
+}
+
+func writeTestFile(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "trails.geojson")
+	if !assert.Nil(t, ioutil.WriteFile(path, []byte(contents), os.ModePerm)) {
+		t.FailNow()
+	}
+	return path
+}
+
+func TestUploads(t *testing.T) {
+	pollInterval = time.Millisecond
+
+	t.Run("Can list and delete uploads", func(t *testing.T) {
+		server := newTestServer(t)
+		u := NewUploads(basetest.NewBase(t, server.handle))
+
+		list, err := u.List(context.Background(), "example")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.Len(t, list, 1)
+		assert.EqualValues(t, StatusFailed, list[0].Status())
+
+		assert.Nil(t, u.Delete(context.Background(), "example", "cjupload"))
+	})
+
+	t.Run("Uploads small files in a single request", func(t *testing.T) {
+		server := newTestServer(t)
+		u := NewUploads(basetest.NewBase(t, server.handle))
+
+		upload, err := u.UploadFile(context.Background(), "example", writeTestFile(t, `{"type":"FeatureCollection"}`), &UploadOpts{Tileset: "example.trails"})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.EqualValues(t, StatusComplete, upload.Status())
+		assert.EqualValues(t, 2, server.polls)
+		assert.EqualValues(t, map[int]string{1: `{"type":"FeatureCollection"}`}, server.staged)
+		assert.EqualValues(t, UploadOpts{Tileset: "example.trails", URL: server.url + "/bucket/staged/file", Name: "trails.geojson"}, server.created)
+	})
+
+	t.Run("Uploads large files in parts", func(t *testing.T) {
+		partSize := s3PartSize
+		s3PartSize = 4
+		defer func() { s3PartSize = partSize }()
+
+		server := newTestServer(t)
+		u := NewUploads(basetest.NewBase(t, server.handle))
+
+		_, err := u.UploadFile(context.Background(), "example", writeTestFile(t, "0123456789"), &UploadOpts{Tileset: "example.trails", Name: "Trails"})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.EqualValues(t, map[int]string{1: "0123", 2: "4567", 3: "89"}, server.staged)
+		assert.EqualValues(t, "Trails", server.created.Name)
+	})
+}